@@ -70,3 +70,10 @@ The image to use
 {{- define "proclaim.image" -}}
 {{- printf "%s:%s" .Values.image.repository (default (printf "v%s" .Chart.AppVersion) .Values.image.tag) }}
 {{- end }}
+
+{{/*
+The name of the webhook service and the secret containing its TLS certificate
+*/}}
+{{- define "proclaim.webhookName" -}}
+{{- printf "%s-webhook" (include "proclaim.fullname" .) | trunc 63 | trimSuffix "-" }}
+{{- end }}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dogmatiq/ferrite"
+)
+
+var probePort = ferrite.
+	NetworkPort("PROBE_PORT", "the port on which the health (/healthz) and readiness (/readyz) probes are served").
+	WithDefault("8081").
+	Required()
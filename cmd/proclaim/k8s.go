@@ -1,17 +1,24 @@
 package main
 
 import (
+	"net"
+	"time"
+
 	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/ferrite"
 	"github.com/dogmatiq/imbue"
 	"github.com/dogmatiq/proclaim/crd"
 	"github.com/dogmatiq/proclaim/reconciler"
 	"github.com/go-logr/logr"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	controller "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/scheme"
 )
 
+var resyncInterval = ferrite.
+	Duration("RESYNC_INTERVAL", "the interval at which advertised DNS records are checked for modifications made outside of Proclaim").
+	WithDefault(10 * time.Minute).
+	Required()
+
 func init() {
 	imbue.With1(
 		container,
@@ -24,12 +31,36 @@ func init() {
 				return nil, err
 			}
 
-			return controller.NewManager(
-				cfg,
-				controller.Options{
-					Logger: l.Value(),
-				},
-			)
+			opts := controller.Options{
+				Logger:                 l.Value(),
+				HealthProbeBindAddress: net.JoinHostPort("", probePort.Value()),
+			}
+
+			if leaderElectionEnabled.Value() {
+				leaseDuration := leaderElectionLeaseDuration.Value()
+				renewDeadline := leaderElectionRenewDeadline.Value()
+				retryPeriod := leaderElectionRetryPeriod.Value()
+
+				opts.LeaderElection = true
+				opts.LeaderElectionID = leaderElectionID.Value()
+				opts.LeaderElectionNamespace, _ = leaderElectionNamespace.Value()
+				opts.LeaderElectionReleaseOnCancel = true
+				opts.LeaseDuration = &leaseDuration
+				opts.RenewDeadline = &renewDeadline
+				opts.RetryPeriod = &retryPeriod
+			}
+
+			if webhookEnabled.Value() {
+				port, err := net.LookupPort("tcp", webhookPort.Value())
+				if err != nil {
+					return nil, err
+				}
+
+				opts.Port = port
+				opts.CertDir = webhookCertDir.Value()
+			}
+
+			return controller.NewManager(cfg, opts)
 		},
 	)
 
@@ -41,9 +72,10 @@ func init() {
 			r *dnssd.UnicastResolver,
 		) (*reconciler.Reconciler, error) {
 			return &reconciler.Reconciler{
-				Manager:  m,
-				Client:   m.GetClient(),
-				Resolver: r,
+				Manager:        m,
+				Client:         m.GetClient(),
+				Resolver:       r,
+				ResyncInterval: resyncInterval.Value(),
 			}, nil
 		},
 	)
@@ -54,19 +86,7 @@ func init() {
 			ctx imbue.Context,
 			m manager.Manager,
 		) (manager.Manager, error) {
-			b := &scheme.Builder{
-				GroupVersion: schema.GroupVersion{
-					Group:   crd.GroupName,
-					Version: crd.Version,
-				},
-			}
-
-			b.Register(
-				&crd.DNSSDServiceInstance{},
-				&crd.DNSSDServiceInstanceList{},
-			)
-
-			if err := b.AddToScheme(m.GetScheme()); err != nil {
+			if err := crd.AddToScheme(m.GetScheme()); err != nil {
 				return nil, err
 			}
 
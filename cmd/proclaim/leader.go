@@ -0,0 +1,36 @@
+package main
+
+import (
+	"time"
+
+	"github.com/dogmatiq/ferrite"
+)
+
+var leaderElectionEnabled = ferrite.
+	Bool("LEADER_ELECTION_ENABLED", "enable leader election, allowing multiple replicas to run with only one advertising records at a time").
+	WithDefault(false).
+	Required()
+
+var leaderElectionID = ferrite.
+	String("LEADER_ELECTION_ID", "the name of the lease used for leader election").
+	WithDefault("proclaim-leader").
+	Required(ferrite.RelevantIf(leaderElectionEnabled))
+
+var leaderElectionNamespace = ferrite.
+	String("LEADER_ELECTION_NAMESPACE", "the namespace of the lease used for leader election, defaults to the namespace of the pod").
+	Optional(ferrite.RelevantIf(leaderElectionEnabled))
+
+var leaderElectionLeaseDuration = ferrite.
+	Duration("LEADER_ELECTION_LEASE_DURATION", "the duration that non-leader replicas wait before attempting to acquire an unrenewed lease").
+	WithDefault(15 * time.Second).
+	Required(ferrite.RelevantIf(leaderElectionEnabled))
+
+var leaderElectionRenewDeadline = ferrite.
+	Duration("LEADER_ELECTION_RENEW_DEADLINE", "the duration that the leader retries renewing its lease before giving up leadership").
+	WithDefault(10 * time.Second).
+	Required(ferrite.RelevantIf(leaderElectionEnabled))
+
+var leaderElectionRetryPeriod = ferrite.
+	Duration("LEADER_ELECTION_RETRY_PERIOD", "the interval between attempts to acquire or renew the lease").
+	WithDefault(2 * time.Second).
+	Required(ferrite.RelevantIf(leaderElectionEnabled))
@@ -12,8 +12,13 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	controller "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	crsource "sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 var container = imbue.New()
@@ -32,15 +37,98 @@ func main() {
 			r *reconciler.Reconciler,
 			l imbue.ByName[systemLogger, logr.Logger],
 		) error {
-			err := builder.
+			instances := builder.
 				ControllerManagedBy(m).
 				For(&crd.DNSSDServiceInstance{}).
-				WithEventFilter(predicate.GenerationChangedPredicate{}).
-				Complete(r)
-			if err != nil {
+				WithEventFilter(predicate.GenerationChangedPredicate{})
+
+			browseDomains := builder.
+				ControllerManagedBy(m).
+				For(&crd.DNSSDBrowseDomain{}).
+				WithEventFilter(predicate.GenerationChangedPredicate{})
+
+			if providerResourcesEnabled.Value() {
+				// Resources are reconciled as soon as the provider they are
+				// associated with is created from a DNSProvider resource.
+				instances = instances.Watches(
+					&crsource.Channel{Source: r.InstanceEvents()},
+					&handler.EnqueueRequestForObject{},
+				)
+
+				browseDomains = browseDomains.Watches(
+					&crsource.Channel{Source: r.BrowseDomainEvents()},
+					&handler.EnqueueRequestForObject{},
+				)
+			}
+
+			if err := instances.Complete(r); err != nil {
+				return err
+			}
+
+			if err := browseDomains.Complete(reconcile.Func(r.ReconcileBrowseDomain)); err != nil {
+				return err
+			}
+
+			if providerResourcesEnabled.Value() {
+				err := builder.
+					ControllerManagedBy(m).
+					For(&crd.DNSProvider{}).
+					WithEventFilter(predicate.GenerationChangedPredicate{}).
+					Complete(reconcile.Func(r.ReconcileProvider))
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := registerSources(m); err != nil {
 				return err
 			}
 
+			if webhookEnabled.Value() {
+				err := builder.
+					WebhookManagedBy(m).
+					For(&crd.DNSSDServiceInstance{}).
+					WithValidator(crd.InstanceValidator{}).
+					Complete()
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := metrics.Registry.Register(
+				&reconciler.StatusCollector{
+					Client: m.GetClient(),
+				},
+			); err != nil {
+				return err
+			}
+
+			if err := m.Add(
+				&reconciler.ProviderHealthMonitor{
+					Reconciler: r,
+				},
+			); err != nil {
+				return err
+			}
+
+			if err := metrics.Registry.Register(
+				&reconciler.ProviderHealthCollector{
+					Reconciler: r,
+				},
+			); err != nil {
+				return err
+			}
+
+			if err := m.AddHealthzCheck("ping", healthz.Ping); err != nil {
+				return err
+			}
+
+			for name, check := range r.HealthChecks() {
+				if err := m.AddReadyzCheck(name, check); err != nil {
+					return err
+				}
+			}
+
 			for _, p := range r.Providers {
 				l.Value().Info(
 					"provider enabled",
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/dogmatiq/ferrite"
+	"github.com/dogmatiq/imbue"
+	"github.com/dogmatiq/proclaim/provider/azureprovider"
+	"github.com/dogmatiq/proclaim/reconciler"
+	"github.com/go-logr/logr"
+)
+
+var azureEnabled = ferrite.
+	Bool("AZURE_ENABLED", "enable the Azure DNS provider").
+	WithDefault(false).
+	Required()
+
+var azureScopes = ferrite.
+	String("AZURE_SCOPES", "a comma-separated list of subscription IDs, each optionally followed by a slash and a resource group name, that are searched for DNS zones in order").
+	Required(ferrite.RelevantIf(azureEnabled))
+
+func init() {
+	imbue.Decorate1(
+		container,
+		func(
+			ctx imbue.Context,
+			r *reconciler.Reconciler,
+			l imbue.ByName[providerLogger, logr.Logger],
+		) (*reconciler.Reconciler, error) {
+			if !azureEnabled.Value() {
+				return r, nil
+			}
+
+			// The default credential chain supports client secrets supplied
+			// via the AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET
+			// environment variables, AKS workload identity and managed
+			// identities.
+			cred, err := azidentity.NewDefaultAzureCredential(nil)
+			if err != nil {
+				return nil, err
+			}
+
+			p, err := newAzureProvider(cred, azureScopes.Value())
+			if err != nil {
+				return nil, err
+			}
+
+			p.Logger = l.Value()
+			r.Providers = append(r.Providers, p)
+
+			return r, nil
+		},
+	)
+}
+
+// newAzureProvider returns an Azure DNS provider that authenticates using the
+// given credential and searches the given comma-separated list of scopes for
+// DNS zones.
+func newAzureProvider(
+	cred azcore.TokenCredential,
+	scopes string,
+) (*azureprovider.Provider, error) {
+	p := &azureprovider.Provider{
+		Credential: cred,
+	}
+
+	for _, s := range strings.Split(scopes, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+
+		scope, err := azureprovider.ParseScope(s)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Scopes = append(p.Scopes, scope)
+	}
+
+	if len(p.Scopes) == 0 {
+		return nil, errors.New("at least one Azure subscription must be specified")
+	}
+
+	return p, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dogmatiq/ferrite"
+	"github.com/dogmatiq/imbue"
+	"github.com/dogmatiq/proclaim/provider/clouddnsprovider"
+	"github.com/dogmatiq/proclaim/reconciler"
+	"github.com/go-logr/logr"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// cloudDNSScope is the OAuth 2.0 scope required to manage Cloud DNS records.
+const cloudDNSScope = "https://www.googleapis.com/auth/ndev.clouddns.readwrite"
+
+var cloudDNSEnabled = ferrite.
+	Bool("CLOUDDNS_ENABLED", "enable the Google Cloud DNS provider").
+	WithDefault(false).
+	Required()
+
+var cloudDNSProject = ferrite.
+	String("CLOUDDNS_PROJECT", "the ID of the Google Cloud project that contains the managed zones, defaults to the project of the application default credentials").
+	Optional(ferrite.RelevantIf(cloudDNSEnabled))
+
+func init() {
+	imbue.Decorate1(
+		container,
+		func(
+			ctx imbue.Context,
+			r *reconciler.Reconciler,
+			l imbue.ByName[providerLogger, logr.Logger],
+		) (*reconciler.Reconciler, error) {
+			if !cloudDNSEnabled.Value() {
+				return r, nil
+			}
+
+			project, _ := cloudDNSProject.Value()
+
+			p, err := newCloudDNSProvider(ctx, nil, project)
+			if err != nil {
+				return nil, err
+			}
+
+			p.Logger = l.Value()
+			r.Providers = append(r.Providers, p)
+
+			return r, nil
+		},
+	)
+}
+
+// newCloudDNSProvider returns a Cloud DNS provider that authenticates using
+// the given service account key.
+//
+// If key is empty the application default credentials are used instead, which
+// includes GKE workload identity and the GOOGLE_APPLICATION_CREDENTIALS
+// environment variable. If project is empty, the project associated with the
+// credentials is used.
+func newCloudDNSProvider(
+	ctx context.Context,
+	key []byte,
+	project string,
+) (*clouddnsprovider.Provider, error) {
+	var (
+		creds *google.Credentials
+		err   error
+	)
+
+	if len(key) == 0 {
+		creds, err = google.FindDefaultCredentials(ctx, cloudDNSScope)
+	} else {
+		creds, err = google.CredentialsFromJSON(ctx, key, cloudDNSScope)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if project == "" {
+		project = creds.ProjectID
+	}
+
+	if project == "" {
+		return nil, errors.New("the Cloud DNS project can not be determined from the credentials, it must be specified explicitly")
+	}
+
+	return &clouddnsprovider.Provider{
+		Project: project,
+		// The provider outlives the context passed to this function, so the
+		// token source must not be bound to it.
+		HTTPClient: oauth2.NewClient(context.Background(), creds.TokenSource),
+	}, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/dogmatiq/ferrite"
+	"github.com/dogmatiq/imbue"
+	"github.com/dogmatiq/proclaim/provider/cloudflareprovider"
+	"github.com/dogmatiq/proclaim/reconciler"
+	"github.com/go-logr/logr"
+)
+
+var cloudflareEnabled = ferrite.
+	Bool("CLOUDFLARE_ENABLED", "enable the Cloudflare provider").
+	WithDefault(false).
+	Required()
+
+var cloudflareToken = ferrite.
+	String("CLOUDFLARE_API_TOKEN", "the Cloudflare API token, with Zone:Read and DNS:Edit permissions").
+	WithSensitiveContent().
+	Required(ferrite.RelevantIf(cloudflareEnabled))
+
+var cloudflareURL = ferrite.
+	URL("CLOUDFLARE_API_URL", "the URL of the Cloudflare v4 API").
+	WithDefault("https://api.cloudflare.com/client/v4").
+	Required(ferrite.RelevantIf(cloudflareEnabled))
+
+func init() {
+	imbue.Decorate1(
+		container,
+		func(
+			ctx imbue.Context,
+			r *reconciler.Reconciler,
+			l imbue.ByName[providerLogger, logr.Logger],
+		) (*reconciler.Reconciler, error) {
+			if !cloudflareEnabled.Value() {
+				return r, nil
+			}
+
+			r.Providers = append(
+				r.Providers,
+				&cloudflareprovider.Provider{
+					APIToken: cloudflareToken.Value(),
+					APIURL:   cloudflareURL.Value().String(),
+					Logger:   l.Value(),
+				},
+			)
+
+			return r, nil
+		},
+	)
+}
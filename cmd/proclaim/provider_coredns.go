@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/ferrite"
+	"github.com/dogmatiq/imbue"
+	"github.com/dogmatiq/proclaim/provider/corednsprovider"
+	"github.com/dogmatiq/proclaim/reconciler"
+	"github.com/go-logr/logr"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+var coreDNSEnabled = ferrite.
+	Bool("COREDNS_ENABLED", "enable the CoreDNS (etcd plugin) provider").
+	WithDefault(false).
+	Required()
+
+var coreDNSEndpoints = ferrite.
+	String("COREDNS_ETCD_ENDPOINTS", "a comma-separated list of etcd endpoints used by CoreDNS's etcd plugin").
+	Required(ferrite.RelevantIf(coreDNSEnabled))
+
+var coreDNSPrefix = ferrite.
+	String("COREDNS_ETCD_PREFIX", "the etcd key prefix under which CoreDNS looks for records, as configured by the etcd plugin's path option").
+	WithDefault(corednsprovider.DefaultPrefix).
+	Required(ferrite.RelevantIf(coreDNSEnabled))
+
+var coreDNSUsername = ferrite.
+	String("COREDNS_ETCD_USERNAME", "the username used to authenticate with etcd").
+	Optional(ferrite.RelevantIf(coreDNSEnabled))
+
+var coreDNSPassword = ferrite.
+	String("COREDNS_ETCD_PASSWORD", "the password used to authenticate with etcd").
+	WithSensitiveContent().
+	Required(ferrite.RelevantIf(coreDNSUsername))
+
+var coreDNSDomains = ferrite.
+	String("COREDNS_DOMAINS", "a comma-separated list of domains that CoreDNS serves from etcd").
+	Required(ferrite.RelevantIf(coreDNSEnabled))
+
+var coreDNSLeaseTTL = ferrite.
+	Duration("COREDNS_LEASE_TTL", "the TTL of the etcd lease attached to each record, records are removed within this duration if the controller stops").
+	WithDefault(corednsprovider.DefaultLeaseTTL).
+	WithMinimum(5 * time.Second).
+	Required(ferrite.RelevantIf(coreDNSEnabled))
+
+func init() {
+	imbue.Decorate1(
+		container,
+		func(
+			ctx imbue.Context,
+			r *reconciler.Reconciler,
+			l imbue.ByName[providerLogger, logr.Logger],
+		) (*reconciler.Reconciler, error) {
+			if !coreDNSEnabled.Value() {
+				return r, nil
+			}
+
+			var username, password string
+			if u, ok := coreDNSUsername.Value(); ok {
+				username = u
+				password = coreDNSPassword.Value()
+			}
+
+			client, err := newCoreDNSClient(coreDNSEndpoints.Value(), username, password)
+			if err != nil {
+				return nil, err
+			}
+			ctx.Defer(client.Close)
+
+			r.Providers = append(
+				r.Providers,
+				&corednsprovider.Provider{
+					Client:   client,
+					Prefix:   coreDNSPrefix.Value(),
+					Domains:  splitList(coreDNSDomains.Value()),
+					LeaseTTL: coreDNSLeaseTTL.Value(),
+					Logger:   l.Value(),
+				},
+			)
+
+			return r, nil
+		},
+	)
+}
+
+// newCoreDNSClient returns an etcd client that connects to the given
+// comma-separated list of endpoints.
+func newCoreDNSClient(endpoints, username, password string) (*clientv3.Client, error) {
+	eps := splitList(endpoints)
+	if len(eps) == 0 {
+		return nil, errors.New("at least one etcd endpoint must be specified")
+	}
+
+	return clientv3.New(clientv3.Config{
+		Endpoints:   eps,
+		Username:    username,
+		Password:    password,
+		DialTimeout: 10 * time.Second,
+	})
+}
+
+// splitList splits a comma-separated list, discarding empty elements.
+func splitList(s string) []string {
+	var list []string
+
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
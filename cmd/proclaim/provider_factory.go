@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/cloudflareprovider"
+	"github.com/dogmatiq/proclaim/provider/corednsprovider"
+	"github.com/dogmatiq/proclaim/provider/dnsimpleprovider"
+	"github.com/dogmatiq/proclaim/provider/powerdnsprovider"
+	"github.com/dogmatiq/proclaim/provider/rfc2136provider"
+	"github.com/dogmatiq/proclaim/provider/route53provider"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// providerFactory creates providers from DNSProvider resources and from the
+// entries in the providers file.
+type providerFactory struct {
+	// Reader is used to read credentials secrets. It bypasses the manager's
+	// cache so that the controller does not need to watch secrets. It is only
+	// required when creating providers from DNSProvider resources.
+	Reader    client.Reader
+	Namespace string
+	Logger    logr.Logger
+}
+
+// New returns the provider described by res.
+func (f *providerFactory) New(
+	ctx context.Context,
+	res *crd.DNSProvider,
+) (provider.Provider, error) {
+	creds, err := f.credentials(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.create(ctx, res.Name, res.Spec, creds)
+}
+
+// create returns a provider with the given name, as described by spec.
+//
+// creds contains the provider's credentials, keyed by the same names that are
+// used within a credentials secret.
+func (f *providerFactory) create(
+	ctx context.Context,
+	name string,
+	spec crd.DNSProviderSpec,
+	creds map[string]string,
+) (provider.Provider, error) {
+	switch spec.Type {
+	case "route53":
+		return f.route53(ctx, name, spec, creds)
+	case "dnsimple":
+		return f.dnsimple(ctx, name, spec, creds)
+	case "rfc2136":
+		return f.rfc2136(name, spec, creds)
+	case "cloudflare":
+		return f.cloudflare(name, spec, creds)
+	case "clouddns":
+		return f.clouddns(ctx, name, spec, creds)
+	case "azure":
+		return f.azure(name, spec, creds)
+	case "powerdns":
+		return f.powerdns(name, spec, creds)
+	case "coredns":
+		return f.coredns(name, spec, creds)
+	default:
+		return nil, fmt.Errorf("unsupported provider type (%s)", spec.Type)
+	}
+}
+
+// credentials returns the contents of the secret referenced by res, or an
+// empty map if res does not reference a secret.
+func (f *providerFactory) credentials(
+	ctx context.Context,
+	res *crd.DNSProvider,
+) (map[string]string, error) {
+	creds := map[string]string{}
+
+	ref := res.Spec.CredentialsSecretRef
+	if ref == nil {
+		return creds, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := f.Reader.Get(
+		ctx,
+		client.ObjectKey{
+			Namespace: f.Namespace,
+			Name:      ref.Name,
+		},
+		secret,
+	); err != nil {
+		return nil, fmt.Errorf("unable to read credentials secret: %w", err)
+	}
+
+	for k, v := range secret.Data {
+		creds[k] = string(v)
+	}
+
+	return creds, nil
+}
+
+func (f *providerFactory) route53(
+	ctx context.Context,
+	name string,
+	spec crd.DNSProviderSpec,
+	creds map[string]string,
+) (provider.Provider, error) {
+	var options []func(*config.LoadOptions) error
+
+	if id, ok := creds["AWS_ACCESS_KEY_ID"]; ok {
+		options = append(
+			options,
+			config.WithCredentialsProvider(
+				credentials.NewStaticCredentialsProvider(
+					id,
+					creds["AWS_SECRET_ACCESS_KEY"],
+					creds["AWS_SESSION_TOKEN"],
+				),
+			),
+		)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	roleARN := creds["AWS_ROLE_ARN"]
+	if roleARN != "" {
+		sessionName := creds["AWS_ROLE_SESSION_NAME"]
+		if sessionName == "" {
+			sessionName = "proclaim"
+		}
+
+		cfg = assumeRole(cfg, roleARN, creds["AWS_EXTERNAL_ID"], sessionName)
+	}
+
+	cli := route53.NewFromConfig(
+		cfg,
+		func(o *route53.Options) {
+			if spec.Endpoint != "" {
+				o.EndpointResolver = route53.EndpointResolverFromURL(spec.Endpoint)
+			}
+		},
+	)
+
+	return &route53provider.Provider{
+		Client:  cli,
+		RoleARN: roleARN,
+		Name:    name,
+		Logger:  f.Logger,
+	}, nil
+}
+
+func (f *providerFactory) dnsimple(
+	ctx context.Context,
+	name string,
+	spec crd.DNSProviderSpec,
+	creds map[string]string,
+) (provider.Provider, error) {
+	token, ok := creds["DNSIMPLE_TOKEN"]
+	if !ok {
+		return nil, errors.New("credentials secret must contain a DNSIMPLE_TOKEN key")
+	}
+
+	cli := dnsimple.NewClient(
+		dnsimple.StaticTokenHTTPClient(ctx, token),
+	)
+
+	if spec.Endpoint != "" {
+		cli.BaseURL = spec.Endpoint
+	}
+
+	return &dnsimpleprovider.Provider{
+		Client: cli,
+		Name:   name,
+		Logger: f.Logger,
+	}, nil
+}
+
+func (f *providerFactory) rfc2136(
+	name string,
+	spec crd.DNSProviderSpec,
+	creds map[string]string,
+) (provider.Provider, error) {
+	server := spec.Endpoint
+	if server == "" {
+		return nil, errors.New("endpoint must be set to the address of the name server")
+	}
+
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	p := &rfc2136provider.Provider{
+		Server: server,
+		Name:   name,
+		Logger: f.Logger,
+	}
+
+	if name, ok := creds["RFC2136_TSIG_KEY_NAME"]; ok {
+		alg := creds["RFC2136_TSIG_ALGORITHM"]
+		if alg == "" {
+			alg = "hmac-sha256"
+		}
+
+		p.Key = &rfc2136provider.TSIGKey{
+			Name:      name,
+			Algorithm: alg,
+			Secret:    creds["RFC2136_TSIG_SECRET"],
+		}
+	}
+
+	return p, nil
+}
+
+func (f *providerFactory) cloudflare(
+	name string,
+	spec crd.DNSProviderSpec,
+	creds map[string]string,
+) (provider.Provider, error) {
+	token, ok := creds["CLOUDFLARE_API_TOKEN"]
+	if !ok {
+		return nil, errors.New("credentials secret must contain a CLOUDFLARE_API_TOKEN key")
+	}
+
+	return &cloudflareprovider.Provider{
+		APIToken: token,
+		APIURL:   spec.Endpoint,
+		Name:     name,
+		Logger:   f.Logger,
+	}, nil
+}
+
+func (f *providerFactory) clouddns(
+	ctx context.Context,
+	name string,
+	spec crd.DNSProviderSpec,
+	creds map[string]string,
+) (provider.Provider, error) {
+	p, err := newCloudDNSProvider(
+		ctx,
+		[]byte(creds["GOOGLE_CREDENTIALS"]),
+		creds["GOOGLE_PROJECT"],
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p.APIURL = spec.Endpoint
+	p.Name = name
+	p.Logger = f.Logger
+
+	return p, nil
+}
+
+func (f *providerFactory) azure(
+	name string,
+	spec crd.DNSProviderSpec,
+	creds map[string]string,
+) (provider.Provider, error) {
+	var (
+		cred azcore.TokenCredential
+		err  error
+	)
+
+	// Use the client secret if one is provided, otherwise fall back to the
+	// controller's workload identity, optionally overriding the client and
+	// tenant IDs to use a different identity federated with the same service
+	// account.
+	if secret, ok := creds["AZURE_CLIENT_SECRET"]; ok {
+		cred, err = azidentity.NewClientSecretCredential(
+			creds["AZURE_TENANT_ID"],
+			creds["AZURE_CLIENT_ID"],
+			secret,
+			nil,
+		)
+	} else {
+		cred, err = azidentity.NewWorkloadIdentityCredential(
+			&azidentity.WorkloadIdentityCredentialOptions{
+				ClientID: creds["AZURE_CLIENT_ID"],
+				TenantID: creds["AZURE_TENANT_ID"],
+			},
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := newAzureProvider(cred, creds["AZURE_SCOPES"])
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Endpoint != "" {
+		c := cloud.AzurePublic
+		c.Services = map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Endpoint: spec.Endpoint,
+				Audience: c.Services[cloud.ResourceManager].Audience,
+			},
+		}
+
+		p.ClientOptions = &arm.ClientOptions{
+			ClientOptions: policy.ClientOptions{Cloud: c},
+		}
+	}
+
+	p.Name = name
+	p.Logger = f.Logger
+
+	return p, nil
+}
+
+func (f *providerFactory) powerdns(
+	name string,
+	spec crd.DNSProviderSpec,
+	creds map[string]string,
+) (provider.Provider, error) {
+	if spec.Endpoint == "" {
+		return nil, errors.New("endpoint must be set to the URL of the PowerDNS web server")
+	}
+
+	key, ok := creds["POWERDNS_API_KEY"]
+	if !ok {
+		return nil, errors.New("credentials secret must contain a POWERDNS_API_KEY key")
+	}
+
+	return &powerdnsprovider.Provider{
+		APIURL:   spec.Endpoint,
+		APIKey:   key,
+		ServerID: creds["POWERDNS_SERVER_ID"],
+		Name:     name,
+		Logger:   f.Logger,
+	}, nil
+}
+
+func (f *providerFactory) coredns(
+	name string,
+	spec crd.DNSProviderSpec,
+	creds map[string]string,
+) (provider.Provider, error) {
+	if spec.Endpoint == "" {
+		return nil, errors.New("endpoint must be set to a comma-separated list of etcd endpoints")
+	}
+
+	if len(spec.Zones) == 0 {
+		return nil, errors.New("zones must list the domains that CoreDNS serves from etcd")
+	}
+
+	client, err := newCoreDNSClient(
+		spec.Endpoint,
+		creds["COREDNS_ETCD_USERNAME"],
+		creds["COREDNS_ETCD_PASSWORD"],
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corednsprovider.Provider{
+		Client:  client,
+		Prefix:  creds["COREDNS_ETCD_PREFIX"],
+		Domains: spec.Zones,
+		Name:    name,
+		Logger:  f.Logger,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/dogmatiq/ferrite"
+	"github.com/dogmatiq/imbue"
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/reconciler"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/yaml"
+)
+
+var providersFile = ferrite.
+	File("PROVIDERS_FILE", "the path to a YAML file that declares named providers, allowing multiple accounts of the same provider type").
+	Optional()
+
+// providerConfig is an entry in the providers file, which contains a list of
+// such entries. See examples/providers.yaml.
+//
+// The fields have the same meaning as those of a DNSProvider resource, except
+// that the credentials are specified inline, using the same keys as a
+// credentials secret.
+type providerConfig struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Endpoint    string            `json:"endpoint,omitempty"`
+	Zones       []string          `json:"zones,omitempty"`
+	Credentials map[string]string `json:"credentials,omitempty"`
+}
+
+func init() {
+	imbue.Decorate1(
+		container,
+		func(
+			ctx imbue.Context,
+			r *reconciler.Reconciler,
+			l imbue.ByName[providerLogger, logr.Logger],
+		) (*reconciler.Reconciler, error) {
+			filename, ok := providersFile.Value()
+			if !ok {
+				return r, nil
+			}
+
+			data, err := filename.ReadBytes()
+			if err != nil {
+				return nil, err
+			}
+
+			var configs []providerConfig
+			if err := yaml.UnmarshalStrict(data, &configs); err != nil {
+				return nil, fmt.Errorf("unable to parse %s: %w", filename, err)
+			}
+
+			f := &providerFactory{
+				Logger: l.Value(),
+			}
+
+			names := map[string]struct{}{}
+
+			for _, c := range configs {
+				if c.Name == "" {
+					return nil, fmt.Errorf("unable to parse %s: each provider must have a name", filename)
+				}
+
+				if _, ok := names[c.Name]; ok {
+					return nil, fmt.Errorf("unable to parse %s: multiple providers are named %q", filename, c.Name)
+				}
+				names[c.Name] = struct{}{}
+
+				p, err := f.create(
+					ctx,
+					c.Name,
+					crd.DNSProviderSpec{
+						Type:     c.Type,
+						Endpoint: c.Endpoint,
+						Zones:    c.Zones,
+					},
+					c.Credentials,
+				)
+				if err != nil {
+					return nil, fmt.Errorf("unable to create %q provider: %w", c.Name, err)
+				}
+
+				if len(c.Zones) != 0 {
+					p = provider.FilterZones(p, c.Zones)
+				}
+
+				r.Providers = append(r.Providers, p)
+			}
+
+			return r, nil
+		},
+	)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/dogmatiq/ferrite"
+	"github.com/dogmatiq/imbue"
+	"github.com/dogmatiq/proclaim/provider/powerdnsprovider"
+	"github.com/dogmatiq/proclaim/reconciler"
+	"github.com/go-logr/logr"
+)
+
+var powerDNSEnabled = ferrite.
+	Bool("POWERDNS_ENABLED", "enable the PowerDNS provider").
+	WithDefault(false).
+	Required()
+
+var powerDNSURL = ferrite.
+	URL("POWERDNS_API_URL", "the URL of the PowerDNS Authoritative Server's web server, without the /api/v1 path").
+	Required(ferrite.RelevantIf(powerDNSEnabled))
+
+var powerDNSKey = ferrite.
+	String("POWERDNS_API_KEY", "the PowerDNS API key").
+	WithSensitiveContent().
+	Required(ferrite.RelevantIf(powerDNSEnabled))
+
+var powerDNSServerID = ferrite.
+	String("POWERDNS_SERVER_ID", "the ID of the PowerDNS server that hosts the zones").
+	WithDefault("localhost").
+	Required(ferrite.RelevantIf(powerDNSEnabled))
+
+func init() {
+	imbue.Decorate1(
+		container,
+		func(
+			ctx imbue.Context,
+			r *reconciler.Reconciler,
+			l imbue.ByName[providerLogger, logr.Logger],
+		) (*reconciler.Reconciler, error) {
+			if !powerDNSEnabled.Value() {
+				return r, nil
+			}
+
+			r.Providers = append(
+				r.Providers,
+				&powerdnsprovider.Provider{
+					APIURL:   powerDNSURL.Value().String(),
+					APIKey:   powerDNSKey.Value(),
+					ServerID: powerDNSServerID.Value(),
+					Logger:   l.Value(),
+				},
+			)
+
+			return r, nil
+		},
+	)
+}
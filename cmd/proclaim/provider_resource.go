@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/dogmatiq/ferrite"
+	"github.com/dogmatiq/imbue"
+	"github.com/dogmatiq/proclaim/reconciler"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var providerResourcesEnabled = ferrite.
+	Bool("DNS_PROVIDER_RESOURCES_ENABLED", "enable configuration of providers using DNSProvider resources").
+	WithDefault(false).
+	Required()
+
+var providerSecretNamespace = ferrite.
+	String("DNS_PROVIDER_SECRET_NAMESPACE", "the namespace that contains the credentials secrets referenced by DNSProvider resources").
+	Required(ferrite.RelevantIf(providerResourcesEnabled))
+
+func init() {
+	imbue.Decorate2(
+		container,
+		func(
+			ctx imbue.Context,
+			r *reconciler.Reconciler,
+			m manager.Manager,
+			l imbue.ByName[providerLogger, logr.Logger],
+		) (*reconciler.Reconciler, error) {
+			if !providerResourcesEnabled.Value() {
+				return r, nil
+			}
+
+			f := &providerFactory{
+				Reader:    m.GetAPIReader(),
+				Namespace: providerSecretNamespace.Value(),
+				Logger:    l.Value(),
+			}
+
+			r.NewProvider = f.New
+
+			return r, nil
+		},
+	)
+}
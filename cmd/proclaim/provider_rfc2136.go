@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net"
+
+	"github.com/dogmatiq/ferrite"
+	"github.com/dogmatiq/imbue"
+	"github.com/dogmatiq/proclaim/provider/rfc2136provider"
+	"github.com/dogmatiq/proclaim/reconciler"
+	"github.com/go-logr/logr"
+)
+
+var rfc2136Enabled = ferrite.
+	Bool("RFC2136_ENABLED", "enable the RFC 2136 (dynamic DNS update) provider").
+	WithDefault(false).
+	Required()
+
+var rfc2136Server = ferrite.
+	String("RFC2136_SERVER", "the hostname or IP address of the primary name server that accepts dynamic updates").
+	Required(ferrite.RelevantIf(rfc2136Enabled))
+
+var rfc2136Port = ferrite.
+	NetworkPort("RFC2136_PORT", "the port of the primary name server that accepts dynamic updates").
+	WithDefault("53").
+	Required(ferrite.RelevantIf(rfc2136Enabled))
+
+var rfc2136TSIGKeyName = ferrite.
+	String("RFC2136_TSIG_KEY_NAME", "the name of the TSIG key used to authenticate dynamic updates").
+	Optional(ferrite.RelevantIf(rfc2136Enabled))
+
+var rfc2136TSIGAlgorithm = ferrite.
+	Enum("RFC2136_TSIG_ALGORITHM", "the HMAC algorithm of the TSIG key").
+	WithMembers(
+		"hmac-sha1",
+		"hmac-sha224",
+		"hmac-sha256",
+		"hmac-sha384",
+		"hmac-sha512",
+	).
+	WithDefault("hmac-sha256").
+	Required(ferrite.RelevantIf(rfc2136TSIGKeyName))
+
+var rfc2136TSIGSecret = ferrite.
+	String("RFC2136_TSIG_SECRET", "the base64-encoded secret of the TSIG key").
+	WithSensitiveContent().
+	Required(ferrite.RelevantIf(rfc2136TSIGKeyName))
+
+func init() {
+	imbue.Decorate1(
+		container,
+		func(
+			ctx imbue.Context,
+			r *reconciler.Reconciler,
+			l imbue.ByName[providerLogger, logr.Logger],
+		) (*reconciler.Reconciler, error) {
+			if !rfc2136Enabled.Value() {
+				return r, nil
+			}
+
+			p := &rfc2136provider.Provider{
+				Server: net.JoinHostPort(
+					rfc2136Server.Value(),
+					rfc2136Port.Value(),
+				),
+				Logger: l.Value(),
+			}
+
+			if name, ok := rfc2136TSIGKeyName.Value(); ok {
+				p.Key = &rfc2136provider.TSIGKey{
+					Name:      name,
+					Algorithm: rfc2136TSIGAlgorithm.Value(),
+					Secret:    rfc2136TSIGSecret.Value(),
+				}
+			}
+
+			r.Providers = append(r.Providers, p)
+
+			return r, nil
+		},
+	)
+}
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"strings"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/dogmatiq/ferrite"
 	"github.com/dogmatiq/imbue"
 	"github.com/dogmatiq/proclaim/provider/route53provider"
@@ -16,6 +20,33 @@ var route53Enabled = ferrite.
 	WithDefault(false).
 	Required()
 
+var route53RoleARN = ferrite.
+	String("ROUTE53_ROLE_ARN", "the ARN of an IAM role to assume when accessing Route 53, such as a role in a dedicated DNS account").
+	Optional(ferrite.RelevantIf(route53Enabled))
+
+var route53ExternalID = ferrite.
+	String("ROUTE53_EXTERNAL_ID", "the external ID to present when assuming the IAM role").
+	WithSensitiveContent().
+	Optional(ferrite.RelevantIf(route53RoleARN))
+
+var route53RoleSessionName = ferrite.
+	String("ROUTE53_ROLE_SESSION_NAME", "the session name to use when assuming the IAM role").
+	WithDefault("proclaim").
+	Required(ferrite.RelevantIf(route53RoleARN))
+
+var route53ZoneVisibility = ferrite.
+	Enum("ROUTE53_ZONE_VISIBILITY", "the type of hosted zones to advertise on when public and private zones share the same name").
+	WithMember("prefer-public", "use the public zone if there is one, otherwise a private zone").
+	WithMember("public", "use only public zones").
+	WithMember("private", "use only private zones").
+	WithMember("all", "use every public and private zone").
+	WithDefault("prefer-public").
+	Required(ferrite.RelevantIf(route53Enabled))
+
+var route53VPCIDs = ferrite.
+	String("ROUTE53_VPC_IDS", "a comma-separated list of VPC IDs, private hosted zones are only used if they are associated with one of these VPCs").
+	Optional(ferrite.RelevantIf(route53Enabled))
+
 func init() {
 	imbue.Decorate2(
 		container,
@@ -34,13 +65,25 @@ func init() {
 				return nil, err
 			}
 
-			r.Providers = append(
-				r.Providers,
-				&route53provider.Provider{
-					Client: cli,
-					Logger: l.Value(),
-				},
-			)
+			p := &route53provider.Provider{
+				Client: cli,
+				Logger: l.Value(),
+			}
+			p.RoleARN, _ = route53RoleARN.Value()
+
+			if v := route53ZoneVisibility.Value(); v != "prefer-public" {
+				p.ZoneVisibility = route53provider.ZoneVisibility(v)
+			}
+
+			if ids, ok := route53VPCIDs.Value(); ok {
+				for _, id := range strings.Split(ids, ",") {
+					if id = strings.TrimSpace(id); id != "" {
+						p.VPCIDs = append(p.VPCIDs, id)
+					}
+				}
+			}
+
+			r.Providers = append(r.Providers, p)
 
 			return r, nil
 		},
@@ -52,6 +95,11 @@ func init() {
 			ctx imbue.Context,
 			cfg aws.Config,
 		) (*route53.Client, error) {
+			if arn, ok := route53RoleARN.Value(); ok {
+				externalID, _ := route53ExternalID.Value()
+				cfg = assumeRole(cfg, arn, externalID, route53RoleSessionName.Value())
+			}
+
 			return route53.NewFromConfig(cfg), nil
 		},
 	)
@@ -65,3 +113,29 @@ func init() {
 		},
 	)
 }
+
+// assumeRole returns a copy of cfg that uses credentials obtained by assuming
+// the IAM role with the given ARN.
+//
+// The temporary credentials are cached, and refreshed automatically before
+// they expire.
+func assumeRole(
+	cfg aws.Config,
+	arn, externalID, sessionName string,
+) aws.Config {
+	creds := stscreds.NewAssumeRoleProvider(
+		sts.NewFromConfig(cfg),
+		arn,
+		func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = sessionName
+			if externalID != "" {
+				o.ExternalID = aws.String(externalID)
+			}
+		},
+	)
+
+	cfg = cfg.Copy()
+	cfg.Credentials = aws.NewCredentialsCache(creds)
+
+	return cfg
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"github.com/dogmatiq/ferrite"
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/source"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	crsource "sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var serviceSourceEnabled = ferrite.
+	Bool("SERVICE_SOURCE_ENABLED", "create DNS-SD service instances from Kubernetes Services with the proclaim.dogmatiq.io/service-type annotation").
+	WithDefault(false).
+	Required()
+
+var ingressSourceEnabled = ferrite.
+	Bool("INGRESS_SOURCE_ENABLED", "create DNS-SD service instances from Kubernetes Ingresses with the proclaim.dogmatiq.io/domain annotation").
+	WithDefault(false).
+	Required()
+
+var gatewaySourceEnabled = ferrite.
+	Bool("GATEWAY_SOURCE_ENABLED", "create DNS-SD service instances from Gateway API Gateways and HTTPRoutes with the proclaim.dogmatiq.io/domain annotation").
+	WithDefault(false).
+	Required()
+
+var templateSourceEnabled = ferrite.
+	Bool("TEMPLATE_SOURCE_ENABLED", "create a DNS-SD service instance for each ready endpoint of the headless Service selected by each DNSSDServiceTemplate").
+	WithDefault(false).
+	Required()
+
+// registerSources registers the controllers that create service instances
+// from other Kubernetes resources.
+func registerSources(m manager.Manager) error {
+	if serviceSourceEnabled.Value() {
+		if err := sourceController(m, &corev1.Service{}).
+			Complete(&source.ServiceReconciler{
+				Manager: m,
+				Client:  m.GetClient(),
+			}); err != nil {
+			return err
+		}
+	}
+
+	if ingressSourceEnabled.Value() {
+		if err := sourceController(m, &networkingv1.Ingress{}).
+			Complete(&source.IngressReconciler{
+				Manager: m,
+				Client:  m.GetClient(),
+			}); err != nil {
+			return err
+		}
+	}
+
+	if gatewaySourceEnabled.Value() {
+		if err := sourceController(m, source.NewGateway()).
+			Complete(&source.GatewayReconciler{
+				Manager: m,
+				Client:  m.GetClient(),
+			}); err != nil {
+			return err
+		}
+
+		r := &source.HTTPRouteReconciler{
+			Manager: m,
+			Client:  m.GetClient(),
+		}
+
+		// Routes are also reconciled when their parent gateway changes, as
+		// the gateway's listeners determine the ports and protocols that are
+		// advertised.
+		if err := sourceController(m, source.NewHTTPRoute()).
+			Watches(
+				&crsource.Kind{Type: source.NewGateway()},
+				handler.EnqueueRequestsFromMapFunc(r.RequestsForGateway),
+			).
+			Complete(r); err != nil {
+			return err
+		}
+	}
+
+	if templateSourceEnabled.Value() {
+		r := &source.TemplateReconciler{
+			Manager: m,
+			Client:  m.GetClient(),
+		}
+
+		// Templates are reconciled when the endpoints of the service they
+		// select change, such as when a pod becomes ready or unready.
+		if err := sourceController(m, &crd.DNSSDServiceTemplate{}).
+			Watches(
+				&crsource.Kind{Type: &corev1.Service{}},
+				handler.EnqueueRequestsFromMapFunc(r.RequestsForService),
+			).
+			Watches(
+				&crsource.Kind{Type: &discoveryv1.EndpointSlice{}},
+				handler.EnqueueRequestsFromMapFunc(r.RequestsForService),
+			).
+			Complete(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sourceController returns a builder for a controller that reconciles the
+// service instances owned by resources of the same type as obj.
+func sourceController(m manager.Manager, obj client.Object) *builder.Builder {
+	return builder.
+		ControllerManagedBy(m).
+		For(obj).
+		Owns(
+			&crd.DNSSDServiceInstance{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		)
+}
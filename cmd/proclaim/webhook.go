@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/dogmatiq/ferrite"
+)
+
+var webhookEnabled = ferrite.
+	Bool("WEBHOOK_ENABLED", "enable the validating admission webhook server").
+	WithDefault(false).
+	Required()
+
+var webhookPort = ferrite.
+	NetworkPort("WEBHOOK_PORT", "the port on which the validating admission webhook server listens").
+	WithDefault("9443").
+	Required(ferrite.RelevantIf(webhookEnabled))
+
+var webhookCertDir = ferrite.
+	String("WEBHOOK_CERT_DIR", "the directory containing the webhook server's TLS certificate (tls.crt) and private key (tls.key)").
+	WithDefault("/etc/proclaim/webhook/certs").
+	Required(ferrite.RelevantIf(webhookEnabled))
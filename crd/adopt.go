@@ -59,3 +59,82 @@ func InstanceIgnoredCondition() metav1.Condition {
 		Message: "no running Proclaim controllers have providers that can advertise on this domain",
 	}
 }
+
+// BrowseDomainAdopted records an event indicating that the browse domain was
+// adopted by the controller.
+func BrowseDomainAdopted(m manager.Manager, res *DNSSDBrowseDomain) {
+	m.
+		GetEventRecorderFor("proclaim-"+res.Status.Provider).
+		Eventf(
+			res,
+			"Normal",
+			"BrowseDomainAdopted",
+			"%s can advertise browse domains on %q",
+			res.Status.ProviderDescription,
+			res.Spec.Domain,
+		)
+}
+
+// BrowseDomainAdoptedCondition returns a condition indicating that the browse
+// domain has been adopted by a provider.
+func BrowseDomainAdoptedCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    ConditionTypeAdopted,
+		Status:  metav1.ConditionTrue,
+		Reason:  "BrowseDomainAdopted",
+		Message: "at least one Proclaim controller has a provider that can advertise browse domains on this domain",
+	}
+}
+
+// BrowseDomainIgnored records an event indicating that the browse domain was
+// ignored by the controller.
+func BrowseDomainIgnored(m manager.Manager, res *DNSSDBrowseDomain) {
+	m.
+		GetEventRecorderFor("proclaim").
+		Eventf(
+			res,
+			"Warning",
+			"BrowseDomainIgnored",
+			"none of the configured providers can advertise browse domains on %q",
+			res.Spec.Domain,
+		)
+}
+
+// BrowseDomainIgnoredCondition returns a condition indicating that the browse
+// domain has been ignored by all providers.
+func BrowseDomainIgnoredCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    ConditionTypeAdopted,
+		Status:  metav1.ConditionFalse,
+		Reason:  "BrowseDomainIgnored",
+		Message: "no running Proclaim controllers have providers that can advertise browse domains on this domain",
+	}
+}
+
+// BrowseDomainUnsupported records an event indicating that the browse domain
+// was not adopted because the providers that manage its domain do not support
+// browse domains.
+func BrowseDomainUnsupported(m manager.Manager, res *DNSSDBrowseDomain, desc string) {
+	m.
+		GetEventRecorderFor("proclaim").
+		Eventf(
+			res,
+			"Warning",
+			"BrowseDomainUnsupported",
+			"%s manages %q but does not support browse domains",
+			desc,
+			res.Spec.Domain,
+		)
+}
+
+// BrowseDomainUnsupportedCondition returns a condition indicating that the
+// browse domain has not been adopted because the providers that manage its
+// domain do not support browse domains.
+func BrowseDomainUnsupportedCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    ConditionTypeAdopted,
+		Status:  metav1.ConditionFalse,
+		Reason:  "BrowseDomainUnsupported",
+		Message: "the providers that manage this domain do not support browse domains",
+	}
+}
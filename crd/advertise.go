@@ -11,9 +11,9 @@ const ConditionTypeAdvertised = "Advertised"
 
 // DNSRecordsCreated records an event indicating that new DNS records were
 // created.
-func DNSRecordsCreated(m manager.Manager, res *DNSSDServiceInstance) {
+func DNSRecordsCreated(m manager.Manager, res Resource) {
 	m.
-		GetEventRecorderFor("proclaim-"+res.Status.Provider).
+		GetEventRecorderFor("proclaim-"+res.status().Provider).
 		Event(
 			res,
 			"Normal",
@@ -35,9 +35,9 @@ func DNSRecordsCreatedCondition() metav1.Condition {
 
 // DNSRecordsUpdated records an event indicating that existing DNS records were
 // updated.
-func DNSRecordsUpdated(m manager.Manager, res *DNSSDServiceInstance) {
+func DNSRecordsUpdated(m manager.Manager, res Resource) {
 	m.
-		GetEventRecorderFor("proclaim-"+res.Status.Provider).
+		GetEventRecorderFor("proclaim-"+res.status().Provider).
 		Event(
 			res,
 			"Normal",
@@ -59,9 +59,9 @@ func DNSRecordsUpdatedCondition() metav1.Condition {
 
 // DNSRecordsVerified records an event indicating that existing DNS records were
 // verified to match the service instance spec.
-func DNSRecordsVerified(m manager.Manager, res *DNSSDServiceInstance) {
+func DNSRecordsVerified(m manager.Manager, res Resource) {
 	m.
-		GetEventRecorderFor("proclaim-"+res.Status.Provider).
+		GetEventRecorderFor("proclaim-"+res.status().Provider).
 		Event(
 			res,
 			"Normal",
@@ -81,11 +81,47 @@ func DNSRecordsObservedCondition() metav1.Condition {
 	}
 }
 
+// DNSRecordsPropagatingCondition returns a condition indicating that the
+// instance's DNS records have been changed, but the changes have not yet
+// propagated to the provider's name servers.
+func DNSRecordsPropagatingCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    ConditionTypeAdvertised,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Propagating",
+		Message: "waiting for DNS changes to propagate to the provider's name servers",
+	}
+}
+
+// DNSRecordsPropagated records an event indicating that changes to DNS records
+// have propagated to the provider's name servers.
+func DNSRecordsPropagated(m manager.Manager, res Resource) {
+	m.
+		GetEventRecorderFor("proclaim-"+res.status().Provider).
+		Event(
+			res,
+			"Normal",
+			"RecordsPropagated",
+			"DNS changes have propagated to the provider's name servers",
+		)
+}
+
+// DNSRecordsPropagatedCondition returns a condition indicating that changes to
+// the instance's DNS records have propagated to the provider's name servers.
+func DNSRecordsPropagatedCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    ConditionTypeAdvertised,
+		Status:  metav1.ConditionTrue,
+		Reason:  "RecordsPropagated",
+		Message: "DNS changes have propagated to the provider's name servers",
+	}
+}
+
 // DNSRecordsDeleted records an event indicating that existing DNS records were
 // deleted.
-func DNSRecordsDeleted(m manager.Manager, res *DNSSDServiceInstance) {
+func DNSRecordsDeleted(m manager.Manager, res Resource) {
 	m.
-		GetEventRecorderFor("proclaim-"+res.Status.Provider).
+		GetEventRecorderFor("proclaim-"+res.status().Provider).
 		Event(
 			res,
 			"Normal",
@@ -109,7 +145,7 @@ func DNSRecordsDeletedCondition() metav1.Condition {
 // interacting with a DNS provider.
 func ProviderError(
 	m manager.Manager,
-	res *DNSSDServiceInstance,
+	res Resource,
 	id, desc string,
 	err error,
 ) {
@@ -146,3 +182,42 @@ func UnadvertiseErrorCondition(err error) metav1.Condition {
 		Message: err.Error(),
 	}
 }
+
+// DriftDetected records an event indicating that DNS records that were
+// previously in sync have been modified outside of Proclaim, and are being
+// repaired.
+func DriftDetected(m manager.Manager, res Resource) {
+	m.
+		GetEventRecorderFor("proclaim-"+res.status().Provider).
+		Event(
+			res,
+			"Warning",
+			"DriftDetected",
+			"DNS records no longer match the specification, repairing",
+		)
+}
+
+// InvalidSpec records an event indicating that the service instance can not be
+// advertised because its specification is invalid.
+func InvalidSpec(m manager.Manager, res Resource, err error) {
+	m.
+		GetEventRecorderFor("proclaim").
+		Eventf(
+			res,
+			"Warning",
+			"InvalidSpec",
+			"unable to advertise instance: %s",
+			err.Error(),
+		)
+}
+
+// InvalidSpecCondition returns a condition indicating that the instance can not
+// be advertised because its specification is invalid.
+func InvalidSpecCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    ConditionTypeAdvertised,
+		Status:  metav1.ConditionFalse,
+		Reason:  "InvalidSpec",
+		Message: err.Error(),
+	}
+}
@@ -0,0 +1,72 @@
+package crd
+
+import (
+	"github.com/dogmatiq/dyad"
+	"github.com/dogmatiq/proclaim/provider"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DNSSDBrowseDomain is a cluster-scoped resource that describes the DNS-SD
+// browse and registration domains that are advertised on a specific domain.
+//
+// See https://www.rfc-editor.org/rfc/rfc6763#section-11.
+type DNSSDBrowseDomain struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DNSSDBrowseDomainSpec `json:"spec,omitempty"`
+	Status Status                `json:"status,omitempty"`
+}
+
+// Condition returns the condition with the given type.
+func (d *DNSSDBrowseDomain) Condition(t string) metav1.Condition {
+	return d.Status.condition(t)
+}
+
+// DeepCopyObject returns a deep clone of d.
+func (d *DNSSDBrowseDomain) DeepCopyObject() runtime.Object {
+	return dyad.Clone(d)
+}
+
+func (d *DNSSDBrowseDomain) status() *Status {
+	return &d.Status
+}
+
+// DNSSDBrowseDomainList is a list of DNS-SD browse domains.
+type DNSSDBrowseDomainList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DNSSDBrowseDomain `json:"items"`
+}
+
+// DeepCopyObject returns a deep clone of l.
+func (l *DNSSDBrowseDomainList) DeepCopyObject() runtime.Object {
+	return dyad.Clone(l)
+}
+
+// DNSSDBrowseDomainSpec is the specification for a set of browse domains.
+type DNSSDBrowseDomainSpec struct {
+	Domain              string          `json:"domain"`
+	Browse              []string        `json:"browse,omitempty"`
+	DefaultBrowse       string          `json:"defaultBrowse,omitempty"`
+	LegacyBrowse        []string        `json:"legacyBrowse,omitempty"`
+	Registration        []string        `json:"registration,omitempty"`
+	DefaultRegistration string          `json:"defaultRegistration,omitempty"`
+	TTL                 metav1.Duration `json:"ttl,omitempty"`
+}
+
+// ToBrowseDomains returns the provider.BrowseDomains described by a CRD
+// browse domain specification.
+func (s DNSSDBrowseDomainSpec) ToBrowseDomains() provider.BrowseDomains {
+	return provider.BrowseDomains{
+		Domain:              s.Domain,
+		Browse:              s.Browse,
+		DefaultBrowse:       s.DefaultBrowse,
+		LegacyBrowse:        s.LegacyBrowse,
+		Registration:        s.Registration,
+		DefaultRegistration: s.DefaultRegistration,
+		TTL:                 s.TTL.Duration,
+	}
+}
@@ -12,7 +12,7 @@ const (
 
 	// FinalizerName is the name of the finalizer used by Proclaim to ensure
 	// that DNS-SD services are unadvertised when they're underlying resource
-	// is deleted.
+	// is deleted, and that DNS providers remain available until then.
 	FinalizerName = GroupName + "/unadvertise"
 
 	// Version is the version of the API/CRDs.
@@ -24,8 +24,13 @@ type DNSSDServiceInstance struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   DNSSDServiceInstanceSpec   `json:"spec,omitempty"`
-	Status DNSSDServiceInstanceStatus `json:"status,omitempty"`
+	Spec   DNSSDServiceInstanceSpec `json:"spec,omitempty"`
+	Status Status                   `json:"status,omitempty"`
+}
+
+// Condition returns the condition with the given type.
+func (i *DNSSDServiceInstance) Condition(t string) metav1.Condition {
+	return i.Status.condition(t)
 }
 
 // DeepCopyObject returns a deep clone of i.
@@ -33,6 +38,10 @@ func (i *DNSSDServiceInstance) DeepCopyObject() runtime.Object {
 	return dyad.Clone(i)
 }
 
+func (i *DNSSDServiceInstance) status() *Status {
+	return &i.Status
+}
+
 // DNSSDServiceInstanceList is a list of DNS-SD service instances.
 type DNSSDServiceInstanceList struct {
 	metav1.TypeMeta `json:",inline"`
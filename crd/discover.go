@@ -1,6 +1,8 @@
 package crd
 
 import (
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -57,6 +59,37 @@ func NegativeBrowseResultCondition() metav1.Condition {
 	}
 }
 
+// NegativeSubtypeBrowseResult records an event indicating that the service
+// instance was not discoverable via DNS-SD when browsing by one of its
+// subtypes.
+func NegativeSubtypeBrowseResult(
+	m manager.Manager,
+	res *DNSSDServiceInstance,
+	subtype string,
+) {
+	m.
+		GetEventRecorderFor("proclaim-dnssd").
+		Eventf(
+			res,
+			"Warning",
+			"NegativeSubtypeBrowseResult",
+			"instance not discovered under the %q subtype",
+			subtype,
+		)
+}
+
+// NegativeSubtypeBrowseResultCondition returns a condition indicating that the
+// instance was not present in the result of a DNS-SD browse operation for one
+// of its subtypes.
+func NegativeSubtypeBrowseResultCondition(subtype string) metav1.Condition {
+	return metav1.Condition{
+		Type:    ConditionTypeDiscoverable,
+		Status:  metav1.ConditionFalse,
+		Reason:  "NegativeSubtypeBrowseResult",
+		Message: fmt.Sprintf("DNS-SD browse could not find this instance under the %q subtype", subtype),
+	}
+}
+
 // NegativeLookupResult records an event indicating that the service instance
 // was not discoverable via DNS-SD.
 func NegativeLookupResult(m manager.Manager, res *DNSSDServiceInstance) {
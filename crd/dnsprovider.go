@@ -0,0 +1,143 @@
+package crd
+
+import (
+	"github.com/dogmatiq/dyad"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// DNSProvider is a cluster-scoped resource that configures a DNS provider
+// that is used to advertise service instances and browse domains.
+//
+// It allows providers to be added and removed without restarting the
+// controller, and multiple providers of the same type to be configured with
+// different credentials.
+type DNSProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DNSProviderSpec `json:"spec,omitempty"`
+	Status Status          `json:"status,omitempty"`
+}
+
+// Condition returns the condition with the given type.
+func (p *DNSProvider) Condition(t string) metav1.Condition {
+	return p.Status.condition(t)
+}
+
+// DeepCopyObject returns a deep clone of p.
+func (p *DNSProvider) DeepCopyObject() runtime.Object {
+	return dyad.Clone(p)
+}
+
+func (p *DNSProvider) status() *Status {
+	return &p.Status
+}
+
+// DNSProviderList is a list of DNS providers.
+type DNSProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DNSProvider `json:"items"`
+}
+
+// DeepCopyObject returns a deep clone of l.
+func (l *DNSProviderList) DeepCopyObject() runtime.Object {
+	return dyad.Clone(l)
+}
+
+// DNSProviderSpec is the specification for a DNS provider.
+type DNSProviderSpec struct {
+	// Type is the type of the provider, such as "route53", "dnsimple",
+	// "rfc2136", "cloudflare", "clouddns", "azure", "powerdns" or "coredns".
+	Type string `json:"type"`
+
+	// CredentialsSecretRef refers to a Secret in the controller's namespace
+	// that contains the provider's credentials. The keys within the secret
+	// depend on the provider type.
+	CredentialsSecretRef *SecretReference `json:"credentialsSecretRef,omitempty"`
+
+	// Endpoint is the provider-specific API URL or server address. If it is
+	// empty, the provider's default endpoint is used.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Zones is a list of domains that the provider may advertise on. Each
+	// zone includes its subdomains. If it is empty, the provider may
+	// advertise on any domain that it manages.
+	Zones []string `json:"zones,omitempty"`
+}
+
+// SecretReference is a reference to a Secret in the controller's namespace.
+type SecretReference struct {
+	Name string `json:"name"`
+}
+
+// ConditionTypeReady is a condition that indicates whether or not a DNS
+// provider is ready to advertise DNS records.
+const ConditionTypeReady = "Ready"
+
+// ProviderReady records an event indicating that a DNS provider is ready.
+func ProviderReady(m manager.Manager, res *DNSProvider) {
+	m.
+		GetEventRecorderFor("proclaim-"+res.Status.Provider).
+		Eventf(
+			res,
+			"Normal",
+			"ProviderReady",
+			"%s is ready to advertise DNS records",
+			res.Status.ProviderDescription,
+		)
+}
+
+// ProviderReadyCondition returns a condition indicating that a DNS provider
+// is ready.
+func ProviderReadyCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    ConditionTypeReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ProviderReady",
+		Message: "the provider is configured and can authenticate",
+	}
+}
+
+// ProviderNotReady records an event indicating that a DNS provider is not
+// ready for the given reason.
+func ProviderNotReady(m manager.Manager, res *DNSProvider, reason string, err error) {
+	m.
+		GetEventRecorderFor("proclaim").
+		Eventf(
+			res,
+			"Warning",
+			reason,
+			"provider is not ready: %s",
+			err,
+		)
+}
+
+// ProviderNotReadyCondition returns a condition indicating that a DNS provider
+// is not ready for the given reason.
+func ProviderNotReadyCondition(reason string, err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    ConditionTypeReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: err.Error(),
+	}
+}
+
+// ProviderInUse records an event indicating that a deleted DNS provider is
+// retained because it is still associated with the given number of service
+// instances and browse domains.
+func ProviderInUse(m manager.Manager, res *DNSProvider, n int) {
+	m.
+		GetEventRecorderFor("proclaim-"+res.Status.Provider).
+		Eventf(
+			res,
+			"Normal",
+			"ProviderInUse",
+			"waiting for %d associated resource(s) to be unadvertised before removing the provider",
+			n,
+		)
+}
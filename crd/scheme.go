@@ -0,0 +1,30 @@
+package crd
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+// AddToScheme adds Proclaim's resource types to s.
+func AddToScheme(s *runtime.Scheme) error {
+	b := &scheme.Builder{
+		GroupVersion: schema.GroupVersion{
+			Group:   GroupName,
+			Version: Version,
+		},
+	}
+
+	b.Register(
+		&DNSSDServiceInstance{},
+		&DNSSDServiceInstanceList{},
+		&DNSSDBrowseDomain{},
+		&DNSSDBrowseDomainList{},
+		&DNSSDServiceTemplate{},
+		&DNSSDServiceTemplateList{},
+		&DNSProvider{},
+		&DNSProviderList{},
+	)
+
+	return b.AddToScheme(s)
+}
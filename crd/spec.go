@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -14,8 +15,9 @@ type Instance struct {
 	Name        string           `json:"name"`
 	ServiceType string           `json:"serviceType"`
 	Domain      string           `json:"domain"`
+	Subtypes    []string         `json:"subtypes,omitempty"`
 	TTL         metav1.Duration  `json:"ttl,omitempty"`
-	Targets     [1]Target        `json:"targets"`
+	Targets     []Target         `json:"targets"`
 	Attributes  []map[string]any `json:"attributes,omitempty"`
 }
 
@@ -32,20 +34,34 @@ type DNSSDServiceInstanceSpec struct {
 	Instance Instance `json:"instance"`
 }
 
-// ToDissolve returns a Dissolve dnssd.Instance from a CRD service instance
-// specification.
-func (s DNSSDServiceInstanceSpec) ToDissolve() dnssd.ServiceInstance {
-	inst := dnssd.ServiceInstance{
+// ToServiceInstance returns the provider.ServiceInstance described by a CRD
+// service instance specification.
+//
+// It returns an error if the specification contains an attribute value that
+// can not be represented in a TXT record. Such resources are rejected by
+// InstanceValidator, but may still exist if they were created before the
+// webhook was enabled, or if it is disabled.
+func (s DNSSDServiceInstanceSpec) ToServiceInstance() (provider.ServiceInstance, error) {
+	inst := provider.ServiceInstance{
 		Name:        s.Instance.Name,
 		ServiceType: s.Instance.ServiceType,
 		Domain:      s.Instance.Domain,
-		TargetHost:  s.Instance.Targets[0].Host,
-		TargetPort:  s.Instance.Targets[0].Port,
-		Priority:    s.Instance.Targets[0].Priority,
-		Weight:      s.Instance.Targets[0].Weight,
+		Subtypes:    s.Instance.Subtypes,
 		TTL:         s.Instance.TTL.Duration,
 	}
 
+	for _, t := range s.Instance.Targets {
+		inst.Targets = append(
+			inst.Targets,
+			provider.Target{
+				Host:     t.Host,
+				Port:     t.Port,
+				Priority: t.Priority,
+				Weight:   t.Weight,
+			},
+		)
+	}
+
 	if inst.TTL == 0 {
 		inst.TTL = 60 * time.Second
 	}
@@ -68,9 +84,11 @@ func (s DNSSDServiceInstanceSpec) ToDissolve() dnssd.ServiceInstance {
 				s := strconv.FormatFloat(v, 'g', -1, 64)
 				dst = dst.WithPair(k, []byte(s))
 			default:
-				// TODO: A validating web-hook will make it so this branch
-				// cannot be reached.
-				panic(fmt.Sprintf("unsupported attribute value: %s = %T", k, v))
+				return provider.ServiceInstance{}, fmt.Errorf(
+					"unsupported value for attribute %q: %T",
+					k,
+					v,
+				)
 			}
 		}
 
@@ -79,5 +97,5 @@ func (s DNSSDServiceInstanceSpec) ToDissolve() dnssd.ServiceInstance {
 		}
 	}
 
-	return inst
+	return inst, nil
 }
@@ -4,24 +4,38 @@ import (
 	"context"
 	"reflect"
 
-	"github.com/dogmatiq/dyad"
 	"golang.org/x/exp/slices"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// DNSSDServiceInstanceStatus contains the status of a service instance.
-type DNSSDServiceInstanceStatus struct {
+// Resource is a Proclaim resource that is advertised via a DNS provider.
+type Resource interface {
+	client.Object
+
+	// Condition returns the condition with the given type.
+	Condition(t string) metav1.Condition
+
+	status() *Status
+}
+
+// Status contains the status of a resource that is advertised via a DNS
+// provider.
+type Status struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	ProviderDescription string         `json:"providerDescription,omitempty"`
 	Provider            string         `json:"provider,omitempty"`
 	Advertiser          map[string]any `json:"advertiser,omitempty"`
+
+	// PendingChanges contains provider-specific identifiers of changes that
+	// have not yet propagated to the provider's name servers.
+	PendingChanges []string `json:"pendingChanges,omitempty"`
 }
 
-// Condition returns the condition with the given type.
-func (res *DNSSDServiceInstance) Condition(t string) metav1.Condition {
-	for _, c := range res.Status.Conditions {
+// condition returns the condition with the given type.
+func (s *Status) condition(t string) metav1.Condition {
+	for _, c := range s.Conditions {
 		if c.Type == t {
 			return c
 		}
@@ -37,16 +51,16 @@ func (res *DNSSDServiceInstance) Condition(t string) metav1.Condition {
 func UpdateStatus(
 	ctx context.Context,
 	cli client.Client,
-	res *DNSSDServiceInstance,
+	res Resource,
 	updates ...StatusUpdate,
 ) error {
-	clone := dyad.Clone(res)
+	clone := res.DeepCopyObject().(Resource)
 
 	for _, update := range updates {
 		update(clone)
 	}
 
-	if reflect.DeepEqual(clone.Status, res.Status) {
+	if reflect.DeepEqual(clone.status(), res.status()) {
 		return nil
 	}
 
@@ -54,13 +68,13 @@ func UpdateStatus(
 		return err
 	}
 
-	*res = *clone
+	reflect.ValueOf(res).Elem().Set(reflect.ValueOf(clone).Elem())
 
 	return nil
 }
 
 // StatusUpdate is a function that updates a resource's status in some way.
-type StatusUpdate func(*DNSSDServiceInstance)
+type StatusUpdate func(Resource)
 
 // MergeCondition is an StatusUpdate that merges a new Condition into the
 // resource's status.
@@ -68,23 +82,25 @@ type StatusUpdate func(*DNSSDServiceInstance)
 // If a Condition with the same type already exists, it is replaced with the new
 // Condition, otherwise the new Condition is appended.
 func MergeCondition(c metav1.Condition) StatusUpdate {
-	return func(res *DNSSDServiceInstance) {
-		c.ObservedGeneration = res.Generation
+	return func(res Resource) {
+		s := res.status()
+
+		c.ObservedGeneration = res.GetGeneration()
 		c.LastTransitionTime = metav1.Now()
 
 		index := slices.IndexFunc(
-			res.Status.Conditions,
+			s.Conditions,
 			func(x metav1.Condition) bool {
 				return x.Type == c.Type
 			},
 		)
 
 		if index == -1 {
-			res.Status.Conditions = append(res.Status.Conditions, c)
+			s.Conditions = append(s.Conditions, c)
 			return
 		}
 
-		x := res.Status.Conditions[index]
+		x := s.Conditions[index]
 
 		// Only update the LastTransitionTime if the status has actually
 		// transitioned.
@@ -92,30 +108,39 @@ func MergeCondition(c metav1.Condition) StatusUpdate {
 			c.LastTransitionTime = x.LastTransitionTime
 		}
 
-		res.Status.Conditions[index] = c
+		s.Conditions[index] = c
 	}
 }
 
 // UpdateProviderDescription is an StatusUpdate that sets the
 // ProviderDescription field of the resource's status.
 func UpdateProviderDescription(desc string) StatusUpdate {
-	return func(res *DNSSDServiceInstance) {
-		res.Status.ProviderDescription = desc
+	return func(res Resource) {
+		res.status().ProviderDescription = desc
 	}
 }
 
 // AssociateProvider is an StatusUpdate that sets the Provider and
 // Advertiser fields of the resource's status.
 func AssociateProvider(provider string, advertiser map[string]any) StatusUpdate {
-	return func(res *DNSSDServiceInstance) {
-		res.Status.Provider = provider
-		res.Status.Advertiser = advertiser
+	return func(res Resource) {
+		s := res.status()
+		s.Provider = provider
+		s.Advertiser = advertiser
+	}
+}
+
+// SetPendingChanges is an StatusUpdate that sets the PendingChanges field of
+// the resource's status.
+func SetPendingChanges(ids []string) StatusUpdate {
+	return func(res Resource) {
+		res.status().PendingChanges = ids
 	}
 }
 
 // If is an StatusUpdate that conditionally applies other StatusUpdates.
 func If(test bool, updates ...StatusUpdate) StatusUpdate {
-	return func(res *DNSSDServiceInstance) {
+	return func(res Resource) {
 		if test {
 			for _, update := range updates {
 				update(res)
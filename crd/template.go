@@ -0,0 +1,67 @@
+package crd
+
+import (
+	"github.com/dogmatiq/dyad"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DNSSDServiceTemplate is a resource that describes a set of DNS-SD service
+// instances, one for each ready endpoint of a headless Kubernetes Service.
+//
+// It is used to advertise each pod of a peer-to-peer application, such as
+// those managed by a StatefulSet, as a separate instance.
+type DNSSDServiceTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DNSSDServiceTemplateSpec `json:"spec,omitempty"`
+}
+
+// DeepCopyObject returns a deep clone of t.
+func (t *DNSSDServiceTemplate) DeepCopyObject() runtime.Object {
+	return dyad.Clone(t)
+}
+
+// DNSSDServiceTemplateList is a list of DNS-SD service templates.
+type DNSSDServiceTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DNSSDServiceTemplate `json:"items"`
+}
+
+// DeepCopyObject returns a deep clone of l.
+func (l *DNSSDServiceTemplateList) DeepCopyObject() runtime.Object {
+	return dyad.Clone(l)
+}
+
+// DNSSDServiceTemplateSpec is the specification for a service template.
+type DNSSDServiceTemplateSpec struct {
+	// ServiceName is the name of the headless Service, in the same namespace
+	// as the template, whose endpoints are advertised.
+	ServiceName string `json:"serviceName"`
+
+	// Port is the name or number of the endpoint port to advertise. It may be
+	// omitted if the endpoints have a single port.
+	Port string `json:"port,omitempty"`
+
+	// TargetDomain is the domain under which each endpoint's hostname is
+	// resolvable. It defaults to the cluster-local domain of the service,
+	// "<service>.<namespace>.svc.cluster.local".
+	TargetDomain string `json:"targetDomain,omitempty"`
+
+	// Instance is the template for each service instance. The instance name
+	// and target are derived from each endpoint.
+	Instance InstanceTemplate `json:"instance"`
+}
+
+// InstanceTemplate is the template for the DNS-SD service instances created
+// from a DNSSDServiceTemplate.
+type InstanceTemplate struct {
+	ServiceType string           `json:"serviceType"`
+	Domain      string           `json:"domain"`
+	Subtypes    []string         `json:"subtypes,omitempty"`
+	TTL         metav1.Duration  `json:"ttl,omitempty"`
+	Attributes  []map[string]any `json:"attributes,omitempty"`
+}
@@ -0,0 +1,337 @@
+package crd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// maxLabelLength is the maximum length of a single DNS label, in octets.
+	maxLabelLength = 63
+
+	// maxDomainLength is the maximum length of a domain name in its textual
+	// form, excluding the trailing dot.
+	maxDomainLength = 253
+
+	// maxServiceNameLength is the maximum length of the service name portion
+	// of a service type, excluding the leading underscore.
+	//
+	// See https://www.rfc-editor.org/rfc/rfc6335#section-5.1.
+	maxServiceNameLength = 15
+
+	// maxTXTStringLength is the maximum length of a single "key=value" string
+	// within a TXT record.
+	//
+	// See https://www.rfc-editor.org/rfc/rfc6763#section-6.1.
+	maxTXTStringLength = 255
+
+	// maxTXTRecordLength is the maximum total size of the strings within a
+	// single TXT record.
+	//
+	// RFC 6763 states that TXT records larger than 1300 bytes are NOT
+	// RECOMMENDED, as they may not fit in a single packet.
+	//
+	// See https://www.rfc-editor.org/rfc/rfc6763#section-6.2.
+	maxTXTRecordLength = 1300
+)
+
+// Validate returns the errors in the service instance's specification, if
+// any.
+func (i *DNSSDServiceInstance) Validate() field.ErrorList {
+	return i.Spec.Instance.validate(
+		field.NewPath("spec", "instance"),
+	)
+}
+
+func (i Instance) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, validateInstanceName(path.Child("name"), i.Name)...)
+	errs = append(errs, validateServiceType(path.Child("serviceType"), i.ServiceType)...)
+	errs = append(errs, validateDomain(path.Child("domain"), i.Domain)...)
+
+	for n, st := range i.Subtypes {
+		errs = append(errs, validateSubtype(path.Child("subtypes").Index(n), st)...)
+	}
+
+	if i.TTL.Duration < 0 {
+		errs = append(errs, field.Invalid(path.Child("ttl"), i.TTL.Duration.String(), "must not be negative"))
+	}
+
+	if len(i.Targets) == 0 {
+		errs = append(errs, field.Required(path.Child("targets"), "at least one target is required"))
+	}
+
+	for n, t := range i.Targets {
+		errs = append(errs, validateDomain(path.Child("targets").Index(n).Child("host"), t.Host)...)
+	}
+
+	for n, attrs := range i.Attributes {
+		errs = append(errs, validateAttributes(path.Child("attributes").Index(n), attrs)...)
+	}
+
+	return errs
+}
+
+// validateInstanceName validates a service instance name.
+//
+// The instance name is a single DNS label that may contain any UTF-8
+// characters, except for ASCII control characters.
+//
+// See https://www.rfc-editor.org/rfc/rfc6763#section-4.1.1.
+func validateInstanceName(path *field.Path, name string) field.ErrorList {
+	if name == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	if len(name) > maxLabelLength {
+		return field.ErrorList{field.TooLong(path, name, maxLabelLength)}
+	}
+
+	if !utf8.ValidString(name) {
+		return field.ErrorList{field.Invalid(path, name, "must be valid UTF-8")}
+	}
+
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return field.ErrorList{field.Invalid(path, name, "must not contain ASCII control characters")}
+		}
+	}
+
+	return nil
+}
+
+// validateServiceType validates a service type, such as "_http._tcp".
+//
+// See https://www.rfc-editor.org/rfc/rfc6763#section-7.
+func validateServiceType(path *field.Path, serviceType string) field.ErrorList {
+	if serviceType == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	service, proto, ok := strings.Cut(serviceType, ".")
+	if !ok || (proto != "_tcp" && proto != "_udp") {
+		return field.ErrorList{field.Invalid(path, serviceType, `must be of the form "_<service>._tcp" or "_<service>._udp"`)}
+	}
+
+	if !strings.HasPrefix(service, "_") {
+		return field.ErrorList{field.Invalid(path, serviceType, "service name must begin with an underscore")}
+	}
+
+	if msg := validateServiceName(service[1:]); msg != "" {
+		return field.ErrorList{field.Invalid(path, serviceType, msg)}
+	}
+
+	return nil
+}
+
+// validateServiceName validates the service name portion of a service type,
+// without its leading underscore. It returns a description of the problem, or
+// an empty string if the name is valid.
+//
+// See https://www.rfc-editor.org/rfc/rfc6335#section-5.1.
+func validateServiceName(name string) string {
+	if name == "" {
+		return "service name must not be empty"
+	}
+
+	if len(name) > maxServiceNameLength {
+		return fmt.Sprintf("service name must be no more than %d characters", maxServiceNameLength)
+	}
+
+	hasLetter := false
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+			hasLetter = true
+		case c >= '0' && c <= '9':
+		case c == '-':
+			if i == 0 || i == len(name)-1 {
+				return "service name must not begin or end with a hyphen"
+			}
+			if name[i-1] == '-' {
+				return "service name must not contain consecutive hyphens"
+			}
+		default:
+			return "service name must contain only letters, digits and hyphens"
+		}
+	}
+
+	if !hasLetter {
+		return "service name must contain at least one letter"
+	}
+
+	return ""
+}
+
+// validateSubtype validates a service subtype, such as "_printer".
+//
+// See https://www.rfc-editor.org/rfc/rfc6763#section-7.1.
+func validateSubtype(path *field.Path, subtype string) field.ErrorList {
+	if subtype == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	if len(subtype) > maxLabelLength {
+		return field.ErrorList{field.TooLong(path, subtype, maxLabelLength)}
+	}
+
+	if strings.Contains(subtype, ".") {
+		return field.ErrorList{field.Invalid(path, subtype, "must be a single DNS label")}
+	}
+
+	return nil
+}
+
+// validateDomain validates a domain name, such as "example.org".
+func validateDomain(path *field.Path, domain string) field.ErrorList {
+	if domain == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	name := strings.TrimSuffix(domain, ".")
+
+	if len(name) > maxDomainLength {
+		return field.ErrorList{field.TooLong(path, domain, maxDomainLength)}
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" {
+			return field.ErrorList{field.Invalid(path, domain, "must not contain empty labels")}
+		}
+
+		if len(label) > maxLabelLength {
+			return field.ErrorList{
+				field.Invalid(
+					path,
+					domain,
+					fmt.Sprintf("label %q must be no more than %d characters", label, maxLabelLength),
+				),
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateAttributes validates the attributes that are encoded in a single TXT
+// record.
+//
+// See https://www.rfc-editor.org/rfc/rfc6763#section-6.
+func validateAttributes(path *field.Path, attrs map[string]any) field.ErrorList {
+	var errs field.ErrorList
+
+	// Visit the keys in a deterministic order so that the errors are stable.
+	var sorted []string
+	for k := range attrs {
+		sorted = append(sorted, k)
+	}
+	slices.Sort(sorted)
+
+	keys := map[string]string{}
+	size := 0
+
+	for _, k := range sorted {
+		v := attrs[k]
+		p := path.Key(k)
+
+		if msg := validateAttributeKey(k); msg != "" {
+			errs = append(errs, field.Invalid(p, k, msg))
+			continue
+		}
+
+		// Keys are case-insensitive, so a TXT record can not contain keys
+		// that only differ by case.
+		//
+		// See https://www.rfc-editor.org/rfc/rfc6763#section-6.4.
+		if x, ok := keys[strings.ToLower(k)]; ok {
+			errs = append(errs, field.Duplicate(p, fmt.Sprintf("%s (conflicts with %q)", k, x)))
+			continue
+		}
+		keys[strings.ToLower(k)] = k
+
+		n, ok := attributeLength(k, v)
+		if !ok {
+			errs = append(errs, field.TypeInvalid(p, v, "must be a string, number or boolean"))
+			continue
+		}
+
+		if n > maxTXTStringLength {
+			errs = append(errs, field.TooLong(p, v, maxTXTStringLength-len(k)-1))
+			continue
+		}
+
+		if n > 0 {
+			// Each string is prefixed with a single length octet.
+			size += n + 1
+		}
+	}
+
+	if size > maxTXTRecordLength {
+		errs = append(
+			errs,
+			field.Invalid(
+				path,
+				fmt.Sprintf("<%d bytes>", size),
+				fmt.Sprintf("encoded TXT record must be no more than %d bytes", maxTXTRecordLength),
+			),
+		)
+	}
+
+	return errs
+}
+
+// validateAttributeKey validates a TXT record attribute key. It returns a
+// description of the problem, or an empty string if the key is valid.
+//
+// See https://www.rfc-editor.org/rfc/rfc6763#section-6.4.
+func validateAttributeKey(k string) string {
+	if k == "" {
+		return "key must not be empty"
+	}
+
+	for i := 0; i < len(k); i++ {
+		c := k[i]
+
+		if c < 0x20 || c > 0x7e {
+			return "key must contain only printable US-ASCII characters"
+		}
+
+		if c == '=' {
+			return "key must not contain an equals sign"
+		}
+	}
+
+	return ""
+}
+
+// attributeLength returns the length of the "key=value" string that
+// represents the attribute with the given key and value, or 0 if the attribute
+// is omitted from the TXT record entirely.
+//
+// ok is false if v is not a supported attribute value type.
+func attributeLength(k string, v any) (n int, ok bool) {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return len(k), true
+		}
+		return 0, true
+	case string:
+		return len(k) + 1 + len(v), true
+	case int64:
+		return len(k) + 1 + len(strconv.FormatInt(v, 10)), true
+	case float64:
+		return len(k) + 1 + len(strconv.FormatFloat(v, 'g', -1, 64)), true
+	default:
+		return 0, false
+	}
+}
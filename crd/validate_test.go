@@ -0,0 +1,283 @@
+package crd_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/dogmatiq/proclaim/crd"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("func (*DNSSDServiceInstance) Validate()", func() {
+	var res *DNSSDServiceInstance
+
+	BeforeEach(func() {
+		res = &DNSSDServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "instance",
+			},
+			Spec: DNSSDServiceInstanceSpec{
+				Instance: Instance{
+					Name:        "Office Printer",
+					ServiceType: "_ipp._tcp",
+					Domain:      "example.org",
+					Subtypes:    []string{"_universal"},
+					Targets: []Target{
+						{Host: "printer.example.org", Port: 631},
+					},
+					Attributes: []map[string]any{
+						{
+							"txtvers": "1",
+							"color":   true,
+							"pages":   int64(20),
+							"ratio":   float64(1.5),
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("returns no errors for a valid instance", func() {
+		Expect(res.Validate()).To(BeEmpty())
+	})
+
+	DescribeTable(
+		"it returns an error with the expected field path",
+		func(modify func(*Instance), path, detail string) {
+			modify(&res.Spec.Instance)
+
+			errs := res.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal(path))
+			Expect(errs[0].Error()).To(ContainSubstring(detail))
+		},
+		Entry(
+			"empty instance name",
+			func(i *Instance) { i.Name = "" },
+			"spec.instance.name",
+			"Required value",
+		),
+		Entry(
+			"instance name longer than 63 octets",
+			func(i *Instance) { i.Name = strings.Repeat("x", 64) },
+			"spec.instance.name",
+			"must have at most 63 bytes",
+		),
+		Entry(
+			"instance name containing control characters",
+			func(i *Instance) { i.Name = "Office\tPrinter" },
+			"spec.instance.name",
+			"must not contain ASCII control characters",
+		),
+		Entry(
+			"service type without protocol",
+			func(i *Instance) { i.ServiceType = "_ipp" },
+			"spec.instance.serviceType",
+			`must be of the form "_<service>._tcp" or "_<service>._udp"`,
+		),
+		Entry(
+			"service type with unsupported protocol",
+			func(i *Instance) { i.ServiceType = "_ipp._sctp" },
+			"spec.instance.serviceType",
+			`must be of the form "_<service>._tcp" or "_<service>._udp"`,
+		),
+		Entry(
+			"service type without leading underscore",
+			func(i *Instance) { i.ServiceType = "ipp._tcp" },
+			"spec.instance.serviceType",
+			"service name must begin with an underscore",
+		),
+		Entry(
+			"service name longer than 15 characters",
+			func(i *Instance) { i.ServiceType = "_" + strings.Repeat("x", 16) + "._tcp" },
+			"spec.instance.serviceType",
+			"service name must be no more than 15 characters",
+		),
+		Entry(
+			"service name with invalid characters",
+			func(i *Instance) { i.ServiceType = "_i_pp._tcp" },
+			"spec.instance.serviceType",
+			"service name must contain only letters, digits and hyphens",
+		),
+		Entry(
+			"service name with consecutive hyphens",
+			func(i *Instance) { i.ServiceType = "_i--pp._tcp" },
+			"spec.instance.serviceType",
+			"service name must not contain consecutive hyphens",
+		),
+		Entry(
+			"service name with no letters",
+			func(i *Instance) { i.ServiceType = "_123._tcp" },
+			"spec.instance.serviceType",
+			"service name must contain at least one letter",
+		),
+		Entry(
+			"domain with a label longer than 63 characters",
+			func(i *Instance) { i.Domain = strings.Repeat("x", 64) + ".example.org" },
+			"spec.instance.domain",
+			"must be no more than 63 characters",
+		),
+		Entry(
+			"domain with empty labels",
+			func(i *Instance) { i.Domain = "example..org" },
+			"spec.instance.domain",
+			"must not contain empty labels",
+		),
+		Entry(
+			"subtype longer than 63 characters",
+			func(i *Instance) { i.Subtypes = append(i.Subtypes, strings.Repeat("x", 64)) },
+			"spec.instance.subtypes[1]",
+			"must have at most 63 bytes",
+		),
+		Entry(
+			"subtype containing multiple labels",
+			func(i *Instance) { i.Subtypes = []string{"_uni.versal"} },
+			"spec.instance.subtypes[0]",
+			"must be a single DNS label",
+		),
+		Entry(
+			"no targets",
+			func(i *Instance) { i.Targets = nil },
+			"spec.instance.targets",
+			"at least one target is required",
+		),
+		Entry(
+			"target host with a label longer than 63 characters",
+			func(i *Instance) { i.Targets[0].Host = strings.Repeat("x", 64) + ".example.org" },
+			"spec.instance.targets[0].host",
+			"must be no more than 63 characters",
+		),
+		Entry(
+			"attribute key containing an equals sign",
+			func(i *Instance) { i.Attributes[0]["a=b"] = "c" },
+			"spec.instance.attributes[0][a=b]",
+			"key must not contain an equals sign",
+		),
+		Entry(
+			"attribute key containing non-printable characters",
+			func(i *Instance) { i.Attributes[0]["café"] = "c" },
+			"spec.instance.attributes[0][café]",
+			"key must contain only printable US-ASCII characters",
+		),
+		Entry(
+			"empty attribute key",
+			func(i *Instance) { i.Attributes[0][""] = "c" },
+			"spec.instance.attributes[0][]",
+			"key must not be empty",
+		),
+		Entry(
+			"attribute keys that differ only by case",
+			func(i *Instance) { i.Attributes = []map[string]any{{"Key": "a", "key": "b"}} },
+			"spec.instance.attributes[0][key]",
+			"Duplicate value",
+		),
+		Entry(
+			"unsupported attribute value type",
+			func(i *Instance) { i.Attributes[0]["list"] = []any{"a", "b"} },
+			"spec.instance.attributes[0][list]",
+			"must be a string, number or boolean",
+		),
+		Entry(
+			"attribute longer than 255 bytes",
+			func(i *Instance) { i.Attributes[0]["long"] = strings.Repeat("x", 251) },
+			"spec.instance.attributes[0][long]",
+			"must have at most 250 bytes",
+		),
+		Entry(
+			"TXT record larger than 1300 bytes",
+			func(i *Instance) {
+				for _, k := range []string{"a", "b", "c", "d", "e", "f"} {
+					i.Attributes[0][k] = strings.Repeat("x", 250)
+				}
+			},
+			"spec.instance.attributes[0]",
+			"encoded TXT record must be no more than 1300 bytes",
+		),
+	)
+})
+
+var _ = Describe("type InstanceValidator", func() {
+	It("returns an invalid error that describes each problem", func() {
+		res := &DNSSDServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "instance",
+			},
+			Spec: DNSSDServiceInstanceSpec{
+				Instance: Instance{
+					Name:        "instance",
+					ServiceType: "_proclaim",
+					Domain:      "example.org",
+				},
+			},
+		}
+
+		err := InstanceValidator{}.ValidateCreate(context.Background(), res)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+		status := err.(apierrors.APIStatus).Status()
+		Expect(status.Details.Causes).To(HaveLen(2))
+		Expect(status.Details.Causes[0].Field).To(Equal("spec.instance.serviceType"))
+		Expect(status.Details.Causes[1].Field).To(Equal("spec.instance.targets"))
+	})
+
+	It("allows deletion of invalid instances", func() {
+		res := &DNSSDServiceInstance{}
+		err := InstanceValidator{}.ValidateDelete(context.Background(), res)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("rejects updates that change the spec of an instance to be invalid", func() {
+		old := &DNSSDServiceInstance{}
+		res := old.DeepCopyObject().(*DNSSDServiceInstance)
+		res.Spec.Instance.Name = "instance"
+
+		err := InstanceValidator{}.ValidateUpdate(context.Background(), old, res)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+
+	It("allows updates that do not change the spec of an invalid instance", func() {
+		old := &DNSSDServiceInstance{}
+		res := old.DeepCopyObject().(*DNSSDServiceInstance)
+		res.Finalizers = []string{FinalizerName}
+
+		err := InstanceValidator{}.ValidateUpdate(context.Background(), old, res)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("allows updates to invalid instances that are being deleted", func() {
+		old := &DNSSDServiceInstance{}
+		res := old.DeepCopyObject().(*DNSSDServiceInstance)
+		res.Spec.Instance.Name = "instance"
+		res.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+		err := InstanceValidator{}.ValidateUpdate(context.Background(), old, res)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("func (DNSSDServiceInstanceSpec) ToServiceInstance()", func() {
+	It("returns an error if an attribute has an unsupported value", func() {
+		spec := DNSSDServiceInstanceSpec{
+			Instance: Instance{
+				Name:        "instance",
+				ServiceType: "_proclaim._tcp",
+				Domain:      "example.org",
+				Targets: []Target{
+					{Host: "host.example.org", Port: 443},
+				},
+				Attributes: []map[string]any{
+					{"key": []any{"value"}},
+				},
+			},
+		}
+
+		_, err := spec.ToServiceInstance()
+		Expect(err).To(MatchError(`unsupported value for attribute "key": []interface {}`))
+	})
+})
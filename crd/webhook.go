@@ -0,0 +1,71 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// InstanceValidator is an admission.CustomValidator that rejects
+// DNSSDServiceInstance resources with invalid specifications.
+type InstanceValidator struct{}
+
+var _ admission.CustomValidator = InstanceValidator{}
+
+// ValidateCreate returns an error if obj is not a valid DNSSDServiceInstance.
+func (v InstanceValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return v.validate(obj)
+}
+
+// ValidateUpdate returns an error if newObj is not a valid
+// DNSSDServiceInstance.
+//
+// Updates that do not change the specification, such as the addition or
+// removal of finalizers, and updates to resources that are being deleted are
+// always allowed. Otherwise, resources that were created before a validation
+// rule was introduced could never be deleted.
+func (v InstanceValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	if res, ok := newObj.(*DNSSDServiceInstance); ok {
+		if res.DeletionTimestamp != nil {
+			return nil
+		}
+
+		if old, ok := oldObj.(*DNSSDServiceInstance); ok {
+			if equality.Semantic.DeepEqual(old.Spec, res.Spec) {
+				return nil
+			}
+		}
+	}
+
+	return v.validate(newObj)
+}
+
+// ValidateDelete always returns nil, deletions are never rejected.
+func (v InstanceValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (v InstanceValidator) validate(obj runtime.Object) error {
+	res, ok := obj.(*DNSSDServiceInstance)
+	if !ok {
+		return fmt.Errorf("unexpected object type: %T", obj)
+	}
+
+	if errs := res.Validate(); len(errs) != 0 {
+		return apierrors.NewInvalid(
+			schema.GroupKind{
+				Group: GroupName,
+				Kind:  "DNSSDServiceInstance",
+			},
+			res.Name,
+			errs,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,2 @@
+// Package dnssdx contains DNS-SD utilities that are not provided by Dissolve.
+package dnssdx
@@ -0,0 +1,170 @@
+package dnssdx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+// LookupInstance looks up a DNS-SD service instance, including all of its
+// targets.
+//
+// It is equivalent to res.LookupInstance(), except that the result includes
+// one target for each of the instance's SRV records, whereas
+// dnssd.UnicastResolver only reports a single target.
+//
+// ok is false if the instance can not be resolved.
+func LookupInstance(
+	ctx context.Context,
+	res *dnssd.UnicastResolver,
+	instance, serviceType, domain string,
+) (_ provider.ServiceInstance, ok bool, _ error) {
+	name := dnssd.ServiceInstanceName(instance, serviceType, domain) + "."
+
+	srv, err := query(ctx, res, name, dns.TypeSRV)
+	if err != nil {
+		return provider.ServiceInstance{}, false, err
+	}
+
+	txt, err := query(ctx, res, name, dns.TypeTXT)
+	if err != nil {
+		return provider.ServiceInstance{}, false, err
+	}
+
+	inst := provider.ServiceInstance{
+		Name:        instance,
+		ServiceType: serviceType,
+		Domain:      domain,
+		TTL:         math.MaxInt64,
+	}
+
+	hasTXT := false
+
+	for _, rr := range append(srv, txt...) {
+		ttl := time.Duration(rr.Header().Ttl) * time.Second
+		if ttl < inst.TTL {
+			inst.TTL = ttl
+		}
+
+		switch rr := rr.(type) {
+		case *dns.SRV:
+			inst.Targets = append(
+				inst.Targets,
+				provider.Target{
+					Host:     strings.TrimSuffix(rr.Target, "."),
+					Port:     rr.Port,
+					Priority: rr.Priority,
+					Weight:   rr.Weight,
+				},
+			)
+		case *dns.TXT:
+			hasTXT = true
+
+			var attrs dnssd.Attributes
+			for _, pair := range rr.Txt {
+				attrs, _, err = attrs.WithTXT(pair)
+				if err != nil {
+					return provider.ServiceInstance{}, false, fmt.Errorf("unable to parse TXT record: %w", err)
+				}
+			}
+
+			if !attrs.IsEmpty() {
+				inst.Attributes = append(inst.Attributes, attrs)
+			}
+		}
+	}
+
+	return inst, len(inst.Targets) != 0 && hasTXT, nil
+}
+
+// query performs a DNS query against the servers in res.Config and returns the
+// records in the answer section that match the question.
+//
+// Like dnssd.UnicastResolver, it only returns an error if ctx is canceled.
+func query(
+	ctx context.Context,
+	res *dnssd.UnicastResolver,
+	name string,
+	recordType uint16,
+) ([]dns.RR, error) {
+	if res.Config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(res.Config.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	client := res.Client
+	if client == nil {
+		client = &dns.Client{}
+	}
+
+	req := &dns.Msg{}
+	req.SetQuestion(name, recordType)
+
+	// Advertise a larger UDP buffer so that record sets with many SRV targets
+	// or large TXT records are not truncated by servers that support EDNS(0).
+	req.SetEdns0(dns.DefaultMsgSize, false)
+
+	for _, s := range res.Config.Servers {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		addr := net.JoinHostPort(s, res.Config.Port)
+		r, _, err := client.ExchangeContext(ctx, req, addr)
+		if err == nil && r.Truncated && isUDP(client) {
+			// The response did not fit within a UDP message, retry the query
+			// over TCP so that the answer is complete.
+			tcp := &dns.Client{
+				Net:            "tcp",
+				Timeout:        client.Timeout,
+				DialTimeout:    client.DialTimeout,
+				ReadTimeout:    client.ReadTimeout,
+				WriteTimeout:   client.WriteTimeout,
+				TsigSecret:     client.TsigSecret,
+				TsigProvider:   client.TsigProvider,
+				SingleInflight: client.SingleInflight,
+			}
+			r, _, err = tcp.ExchangeContext(ctx, req, addr)
+		}
+		if err != nil {
+			// Server was not contactable or had no response for this query.
+			continue
+		}
+
+		if r.Rcode == dns.RcodeNameError {
+			// The server responded authoritatively to indicate that the name
+			// does not exist.
+			return nil, nil
+		}
+
+		if r.Rcode != dns.RcodeSuccess {
+			continue
+		}
+
+		var records []dns.RR
+		for _, rr := range r.Answer {
+			h := rr.Header()
+			if h.Rrtype == recordType && strings.EqualFold(h.Name, name) {
+				records = append(records, rr)
+			}
+		}
+
+		return records, nil
+	}
+
+	// None of the servers had a result for this query.
+	return nil, nil
+}
+
+// isUDP returns true if c sends queries over UDP.
+func isUDP(c *dns.Client) bool {
+	return c.Net == "" || strings.HasPrefix(c.Net, "udp")
+}
@@ -0,0 +1,142 @@
+package dnssdx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	. "github.com/dogmatiq/proclaim/internal/dnssdx"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/memoryprovider"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const domain = "proclaim-test.example.org"
+
+var _ = Describe("func LookupInstance()", func() {
+	var (
+		ctx      context.Context
+		dnsp     *memoryprovider.Provider
+		resolver *dnssd.UnicastResolver
+		inst     provider.ServiceInstance
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		DeferCleanup(cancel)
+
+		dnsp = &memoryprovider.Provider{
+			Domains: []string{domain},
+			Logger:  logr.Discard(),
+		}
+
+		// Bind TCP first and reuse its port for UDP, trying another port if
+		// that one is already taken for UDP.
+		var (
+			listener net.Listener
+			conn     net.PacketConn
+		)
+
+		for attempt := 0; conn == nil; attempt++ {
+			var err error
+			listener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			conn, err = net.ListenPacket("udp", listener.Addr().String())
+			if err != nil {
+				listener.Close()
+
+				if !errors.Is(err, syscall.EADDRINUSE) || attempt == 10 {
+					Expect(err).ShouldNot(HaveOccurred())
+				}
+			}
+		}
+
+		_, port, err := net.SplitHostPort(listener.Addr().String())
+		Expect(err).ShouldNot(HaveOccurred())
+
+		serve(&dns.Server{PacketConn: conn, Handler: dnsp})
+		serve(&dns.Server{Listener: listener, Handler: dnsp})
+
+		resolver = &dnssd.UnicastResolver{
+			Config: &dns.ClientConfig{
+				Servers:  []string{"127.0.0.1"},
+				Port:     port,
+				Ndots:    1,
+				Timeout:  1,
+				Attempts: 3,
+			},
+		}
+
+		inst = provider.ServiceInstance{
+			Name:        "instance",
+			ServiceType: "_proclaim._tcp",
+			Domain:      domain,
+			TTL:         5 * time.Second,
+			Attributes: []dnssd.Attributes{
+				dnssd.
+					NewAttributes().
+					WithPair("key", []byte(strings.Repeat("x", 250))),
+			},
+		}
+	})
+
+	// advertise advertises inst with n targets.
+	advertise := func(n int) {
+		for i := 0; i < n; i++ {
+			inst.Targets = append(
+				inst.Targets,
+				provider.Target{
+					Host: fmt.Sprintf("host-%03d.example.com", i),
+					Port: 443,
+				},
+			)
+		}
+
+		a, ok, err := dnsp.AdvertiserByDomain(ctx, domain)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		_, err = a.Advertise(ctx, inst)
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	It("returns all targets when the response is larger than a standard UDP message", func() {
+		advertise(20)
+
+		actual, ok, err := LookupInstance(ctx, resolver, inst.Name, inst.ServiceType, inst.Domain)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(actual.Targets).To(ConsistOf(inst.Targets))
+		Expect(actual.Attributes).To(Equal(inst.Attributes))
+	})
+
+	It("retries over TCP when the response is truncated", func() {
+		advertise(200)
+
+		actual, ok, err := LookupInstance(ctx, resolver, inst.Name, inst.ServiceType, inst.Domain)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(actual.Targets).To(ConsistOf(inst.Targets))
+	})
+})
+
+// serve starts srv and stops it when the current test ends.
+func serve(srv *dns.Server) {
+	started := make(chan struct{})
+	srv.NotifyStartedFunc = func() { close(started) }
+
+	go srv.ActivateAndServe() //nolint:errcheck
+	<-started
+
+	DeferCleanup(srv.Shutdown)
+}
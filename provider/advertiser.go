@@ -1,10 +1,6 @@
 package provider
 
-import (
-	"context"
-
-	"github.com/dogmatiq/dissolve/dnssd"
-)
+import "context"
 
 // Advertiser is an interface for advertising DNS-SD service instances on a
 // specific domain.
@@ -15,11 +11,11 @@ type Advertiser interface {
 
 	// Advertise adds/updates DNS records to advertise the given service
 	// instance.
-	Advertise(ctx context.Context, inst dnssd.ServiceInstance) (ChangeSet, error)
+	Advertise(ctx context.Context, inst ServiceInstance) (ChangeSet, error)
 
 	// Advertise removes/updates DNS records to stop advertising the given
 	// service instance.
-	Unadvertise(ctx context.Context, inst dnssd.ServiceInstance) (ChangeSet, error)
+	Unadvertise(ctx context.Context, inst ServiceInstance) (ChangeSet, error)
 }
 
 // ChangeSet describes the changes made to DNS records.
@@ -27,6 +23,14 @@ type ChangeSet struct {
 	PTR Change
 	SRV Change
 	TXT Change
+
+	// PendingChanges contains provider-specific identifiers of changes that
+	// have been accepted by the provider but may not yet have propagated to
+	// its name servers.
+	//
+	// It is only populated by advertisers that implement
+	// PropagationAdvertiser.
+	PendingChanges []string
 }
 
 // Change is a bit-field that describes the changes made to a specific DNS
@@ -0,0 +1,361 @@
+package azureprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
+)
+
+type advertiser struct {
+	Client *armdns.RecordSetsClient
+	Zone   zone
+	Logger logr.Logger
+}
+
+func (a *advertiser) ID() map[string]any {
+	return marshalAdvertiserID(a.Zone)
+}
+
+func (a *advertiser) Advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs, err := a.advertise(ctx, inst)
+	return cs, apiError(err)
+}
+
+// advertise creates and updates the DNS records for inst.
+//
+// Azure DNS can not change several record sets atomically, so the records are
+// written one record set at a time, in an order that ensures that each PTR
+// record refers to records that already exist.
+func (a *advertiser) advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	var cs provider.ChangeSet
+
+	if err := a.syncSRV(ctx, inst, &cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncTXT(ctx, inst, &cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncPTR(ctx, inst, &cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncSubtypePTRs(ctx, inst, &cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncServiceTypePTR(ctx, inst, &cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	return cs, nil
+}
+
+func (a *advertiser) Unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs, err := a.unadvertise(ctx, inst)
+	return cs, apiError(err)
+}
+
+// unadvertise removes the DNS records for inst.
+//
+// The PTR records that refer to the instance are removed before the records
+// that they refer to.
+func (a *advertiser) unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	var cs provider.ChangeSet
+
+	if err := a.deleteSubtypePTRs(ctx, inst, &cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deletePTR(ctx, inst, &cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteSRV(ctx, inst, &cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteTXT(ctx, inst, &cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	return cs, nil
+}
+
+// get returns the record set with the given fully-qualified name and type, or
+// nil if there is no such record set.
+func (a *advertiser) get(
+	ctx context.Context,
+	name string,
+	recordType armdns.RecordType,
+) (*armdns.RecordSet, error) {
+	res, err := a.Client.Get(
+		ctx,
+		a.Zone.ResourceGroup,
+		a.Zone.Name,
+		a.relativeName(name),
+		recordType,
+		nil,
+	)
+	if err != nil {
+		if hasStatusCode(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to get %s record set: %w", recordType, err)
+	}
+
+	return &res.RecordSet, nil
+}
+
+// write creates, replaces or deletes the record set with the given
+// fully-qualified name and type so that it contains the desired records. The
+// record set is deleted if desired contains no records.
+//
+// current is the record set as it was last read, or nil if it did not exist.
+// The write fails if the record set has been modified since it was read, as
+// determined by its ETag. This prevents lost updates to record sets that are
+// shared with other instances, and with other controllers.
+func (a *advertiser) write(
+	ctx context.Context,
+	name string,
+	recordType armdns.RecordType,
+	current *armdns.RecordSet,
+	desired *armdns.RecordSetProperties,
+	cs *provider.ChangeSet,
+) error {
+	var (
+		change provider.Change
+		err    error
+	)
+
+	switch {
+	case current != nil && sameRecords(current.Properties, desired):
+		return nil
+
+	case len(recordValues(desired)) == 0:
+		if current == nil {
+			return nil
+		}
+
+		change = provider.Deleted
+		_, err = a.Client.Delete(
+			ctx,
+			a.Zone.ResourceGroup,
+			a.Zone.Name,
+			a.relativeName(name),
+			recordType,
+			&armdns.RecordSetsClientDeleteOptions{
+				IfMatch: current.Etag,
+			},
+		)
+
+	case current == nil:
+		change = provider.Created
+		_, err = a.Client.CreateOrUpdate(
+			ctx,
+			a.Zone.ResourceGroup,
+			a.Zone.Name,
+			a.relativeName(name),
+			recordType,
+			armdns.RecordSet{Properties: desired},
+			&armdns.RecordSetsClientCreateOrUpdateOptions{
+				IfNoneMatch: to.Ptr("*"),
+			},
+		)
+
+	default:
+		change = provider.Updated
+		_, err = a.Client.CreateOrUpdate(
+			ctx,
+			a.Zone.ResourceGroup,
+			a.Zone.Name,
+			a.relativeName(name),
+			recordType,
+			armdns.RecordSet{Properties: desired},
+			&armdns.RecordSetsClientCreateOrUpdateOptions{
+				IfMatch: current.Etag,
+			},
+		)
+	}
+
+	if err != nil {
+		if hasStatusCode(err, http.StatusPreconditionFailed) {
+			return fmt.Errorf("%s record set was modified concurrently: %w", recordType, err)
+		}
+		return fmt.Errorf("unable to write %s record set: %w", recordType, err)
+	}
+
+	switch recordType {
+	case armdns.RecordTypePTR:
+		cs.PTR |= change
+	case armdns.RecordTypeSRV:
+		cs.SRV |= change
+	case armdns.RecordTypeTXT:
+		cs.TXT |= change
+	}
+
+	switch change {
+	case provider.Created:
+		a.log("CREATE", name, recordType, desired)
+	case provider.Updated:
+		a.log("UPDATE", name, recordType, desired)
+	case provider.Deleted:
+		a.log("DELETE", name, recordType, current.Properties)
+	}
+
+	return nil
+}
+
+func (a *advertiser) log(
+	action, name string,
+	recordType armdns.RecordType,
+	props *armdns.RecordSetProperties,
+) {
+	for _, v := range recordValues(props) {
+		a.Logger.Info(
+			action+" record",
+			"type", recordType,
+			"name", name,
+			"value", v,
+			"ttl", ttl(props),
+		)
+	}
+}
+
+// relativeName returns the name of a record set relative to the zone apex, as
+// used by the Azure DNS API, given its fully-qualified name.
+func (a *advertiser) relativeName(name string) string {
+	name = strings.TrimSuffix(name, ".")
+
+	if strings.EqualFold(name, a.Zone.Name) {
+		return "@"
+	}
+
+	if hasSuffixFold(name, "."+a.Zone.Name) {
+		return name[:len(name)-len(a.Zone.Name)-1]
+	}
+
+	return name
+}
+
+// absoluteName returns the fully-qualified name of a record set given its name
+// relative to the zone apex.
+func (a *advertiser) absoluteName(name string) string {
+	if name == "@" {
+		return a.Zone.Name + "."
+	}
+	return name + "." + a.Zone.Name + "."
+}
+
+func instanceName(inst provider.ServiceInstance) string {
+	return dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, inst.Domain) + "."
+}
+
+func serviceName(inst provider.ServiceInstance) string {
+	return dnssd.InstanceEnumerationDomain(inst.ServiceType, inst.Domain) + "."
+}
+
+// sameRecords returns true if a and b contain the same records with the same
+// TTL.
+func sameRecords(a, b *armdns.RecordSetProperties) bool {
+	return ttl(a) == ttl(b) &&
+		slices.Equal(recordValues(a), recordValues(b))
+}
+
+// recordValues returns the values of the PTR, SRV and TXT records in a record
+// set, in zone file format.
+func recordValues(props *armdns.RecordSetProperties) []string {
+	if props == nil {
+		return nil
+	}
+
+	var values []string
+
+	for _, rec := range props.PtrRecords {
+		values = append(values, *rec.Ptrdname)
+	}
+
+	for _, rec := range props.SrvRecords {
+		values = append(
+			values,
+			fmt.Sprintf(
+				"%d %d %d %s",
+				*rec.Priority,
+				*rec.Weight,
+				*rec.Port,
+				*rec.Target,
+			),
+		)
+	}
+
+	for _, rec := range props.TxtRecords {
+		var quoted []string
+		for _, v := range rec.Value {
+			quoted = append(quoted, strconv.Quote(*v))
+		}
+		values = append(values, strings.Join(quoted, " "))
+	}
+
+	return values
+}
+
+func ttl(props *armdns.RecordSetProperties) int64 {
+	if props == nil || props.TTL == nil {
+		return 0
+	}
+	return *props.TTL
+}
+
+func hasSuffixFold(s, suffix string) bool {
+	return len(s) >= len(suffix) &&
+		strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
+
+// apiError returns err in a form that the reconciler can classify.
+//
+// It returns a provider.RateLimitError if err indicates that an Azure Resource
+// Manager rate limit was exceeded, or a provider.HTTPError if err is any other
+// error response. Otherwise, it returns err unchanged.
+func apiError(err error) error {
+	var e *azcore.ResponseError
+	if !errors.As(err, &e) {
+		return err
+	}
+
+	if e.StatusCode == http.StatusTooManyRequests {
+		return provider.RateLimitError{
+			Reset: time.Now().Add(throttleInterval),
+			Cause: err,
+		}
+	}
+
+	return provider.HTTPError{
+		StatusCode: e.StatusCode,
+		Cause:      err,
+	}
+}
@@ -0,0 +1,75 @@
+package azureprovider
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/dogmatiq/proclaim/provider"
+)
+
+func (a *advertiser) AdvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs, err := a.advertiseBrowseDomains(ctx, d)
+	return cs, apiError(err)
+}
+
+func (a *advertiser) advertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	var cs provider.ChangeSet
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		current, err := a.get(ctx, set.Name, armdns.RecordTypePTR)
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		desired := &armdns.RecordSetProperties{}
+
+		for _, rec := range set.Records {
+			desired.TTL = to.Ptr(int64(rec.Hdr.Ttl))
+			desired.PtrRecords = append(
+				desired.PtrRecords,
+				&armdns.PtrRecord{Ptrdname: to.Ptr(rec.Ptr)},
+			)
+		}
+
+		if err := a.write(ctx, set.Name, armdns.RecordTypePTR, current, desired, &cs); err != nil {
+			return provider.ChangeSet{}, err
+		}
+	}
+
+	return cs, nil
+}
+
+func (a *advertiser) UnadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs, err := a.unadvertiseBrowseDomains(ctx, d)
+	return cs, apiError(err)
+}
+
+func (a *advertiser) unadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	var cs provider.ChangeSet
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		current, err := a.get(ctx, set.Name, armdns.RecordTypePTR)
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		if err := a.write(ctx, set.Name, armdns.RecordTypePTR, current, nil, &cs); err != nil {
+			return provider.ChangeSet{}, err
+		}
+	}
+
+	return cs, nil
+}
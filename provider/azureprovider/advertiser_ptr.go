@@ -0,0 +1,147 @@
+package azureprovider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/dogmatiq/proclaim/provider"
+	"golang.org/x/exp/slices"
+)
+
+// ptrTTL is the TTL of PTR records that enumerate service instances.
+//
+// Normally we'd use each service's TTL for its respective PTR record, but Azure
+// DNS stores all records with the same name and type in a single "record set",
+// which means they all share a TTL.
+const ptrTTL = 30 * time.Second
+
+func (a *advertiser) syncPTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *provider.ChangeSet,
+) error {
+	return a.addToPTRSet(ctx, serviceName(inst), instanceName(inst), cs)
+}
+
+func (a *advertiser) deletePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *provider.ChangeSet,
+) error {
+	current, err := a.get(ctx, serviceName(inst), armdns.RecordTypePTR)
+	if current == nil || err != nil {
+		return err
+	}
+
+	if indexOf(current, instanceName(inst)) == -1 {
+		return nil
+	}
+
+	if err := a.removeFromPTRSet(ctx, serviceName(inst), current, instanceName(inst), cs); err != nil {
+		return err
+	}
+
+	// If this was the last instance of its service type, the service type
+	// itself is no longer advertised.
+	if len(current.Properties.PtrRecords) == 1 {
+		return a.deleteServiceTypePTR(ctx, inst, cs)
+	}
+
+	return nil
+}
+
+// addToPTRSet adds a PTR record that refers to the target name to the shared
+// PTR record set with the given name.
+//
+// The record set is shared with other instances, so it is only replaced if no
+// other process has modified it since it was read. Unlike Route 53 there is no
+// need to version the record set using a set identifier, as Azure DNS provides
+// an ETag for each record set.
+func (a *advertiser) addToPTRSet(
+	ctx context.Context,
+	name, target string,
+	cs *provider.ChangeSet,
+) error {
+	current, err := a.get(ctx, name, armdns.RecordTypePTR)
+	if err != nil {
+		return err
+	}
+
+	var records []*armdns.PtrRecord
+
+	if current != nil {
+		if indexOf(current, target) != -1 {
+			return nil
+		}
+
+		records = slices.Clone(current.Properties.PtrRecords)
+	}
+
+	return a.write(
+		ctx,
+		name,
+		armdns.RecordTypePTR,
+		current,
+		&armdns.RecordSetProperties{
+			TTL: to.Ptr(int64(ptrTTL.Seconds())),
+			PtrRecords: append(
+				records,
+				&armdns.PtrRecord{Ptrdname: to.Ptr(target)},
+			),
+		},
+		cs,
+	)
+}
+
+// removeFromPTRSet removes the PTR record that refers to the target name from
+// the shared PTR record set with the given name. The record set is deleted if
+// it no longer contains any records.
+//
+// current is the record set as it was last read. See addToPTRSet() for details
+// about how the shared record set is updated.
+func (a *advertiser) removeFromPTRSet(
+	ctx context.Context,
+	name string,
+	current *armdns.RecordSet,
+	target string,
+	cs *provider.ChangeSet,
+) error {
+	index := indexOf(current, target)
+	if index == -1 {
+		return nil
+	}
+
+	return a.write(
+		ctx,
+		name,
+		armdns.RecordTypePTR,
+		current,
+		&armdns.RecordSetProperties{
+			TTL: to.Ptr(int64(ptrTTL.Seconds())),
+			PtrRecords: slices.Delete(
+				slices.Clone(current.Properties.PtrRecords),
+				index,
+				index+1,
+			),
+		},
+		cs,
+	)
+}
+
+// indexOf returns the index of the PTR record that refers to the target name
+// in a PTR record set, or -1 if it is not present.
+func indexOf(set *armdns.RecordSet, target string) int {
+	for i, rec := range set.Properties.PtrRecords {
+		if strings.EqualFold(
+			strings.TrimSuffix(*rec.Ptrdname, "."),
+			strings.TrimSuffix(target, "."),
+		) {
+			return i
+		}
+	}
+
+	return -1
+}
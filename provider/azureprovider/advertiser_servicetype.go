@@ -0,0 +1,55 @@
+package azureprovider
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+)
+
+// syncServiceTypePTR adds the instance's service type to the shared PTR record
+// set that enumerates the service types within the domain.
+func (a *advertiser) syncServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *provider.ChangeSet,
+) error {
+	return a.addToPTRSet(ctx, typeEnumerationName(inst), serviceName(inst), cs)
+}
+
+// deleteServiceTypePTR removes the instance's service type from the shared PTR
+// record set that enumerates the service types within the domain.
+//
+// It must only be called after the instance enumeration PTR record set for the
+// instance's service type has been deleted.
+func (a *advertiser) deleteServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *provider.ChangeSet,
+) error {
+	current, err := a.get(ctx, typeEnumerationName(inst), armdns.RecordTypePTR)
+	if current == nil || err != nil {
+		return err
+	}
+
+	if err := a.removeFromPTRSet(ctx, typeEnumerationName(inst), current, serviceName(inst), cs); err != nil {
+		return err
+	}
+
+	// Another controller may have advertised an instance of the same service
+	// type since the instance enumeration record set was deleted. Controllers
+	// add the service type only after creating the instance enumeration
+	// record set, so restoring the service type if that record set now exists
+	// ensures that it is not removed while it is still in use.
+	instances, err := a.get(ctx, serviceName(inst), armdns.RecordTypePTR)
+	if instances == nil || err != nil {
+		return err
+	}
+
+	return a.syncServiceTypePTR(ctx, inst, cs)
+}
+
+func typeEnumerationName(inst provider.ServiceInstance) string {
+	return dnssd.TypeEnumerationDomain(inst.Domain) + "."
+}
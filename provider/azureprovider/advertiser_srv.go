@@ -0,0 +1,51 @@
+package azureprovider
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/dogmatiq/proclaim/provider"
+)
+
+func (a *advertiser) syncSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *provider.ChangeSet,
+) error {
+	current, err := a.get(ctx, instanceName(inst), armdns.RecordTypeSRV)
+	if err != nil {
+		return err
+	}
+
+	desired := &armdns.RecordSetProperties{
+		TTL: to.Ptr(int64(inst.TTL.Seconds())),
+	}
+
+	for _, rec := range provider.NewSRVRecords(inst) {
+		desired.SrvRecords = append(
+			desired.SrvRecords,
+			&armdns.SrvRecord{
+				Priority: to.Ptr(int32(rec.Priority)),
+				Weight:   to.Ptr(int32(rec.Weight)),
+				Port:     to.Ptr(int32(rec.Port)),
+				Target:   to.Ptr(rec.Target),
+			},
+		)
+	}
+
+	return a.write(ctx, instanceName(inst), armdns.RecordTypeSRV, current, desired, cs)
+}
+
+func (a *advertiser) deleteSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *provider.ChangeSet,
+) error {
+	current, err := a.get(ctx, instanceName(inst), armdns.RecordTypeSRV)
+	if err != nil {
+		return err
+	}
+
+	return a.write(ctx, instanceName(inst), armdns.RecordTypeSRV, current, nil, cs)
+}
@@ -0,0 +1,107 @@
+package azureprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"golang.org/x/exp/slices"
+)
+
+// findSubtypePTRs returns all of the subtype PTR record sets for the instance's
+// service type, regardless of which subtypes the instance provides, keyed by
+// their fully-qualified names.
+func (a *advertiser) findSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (map[string]*armdns.RecordSet, error) {
+	parent := "._sub." + serviceName(inst)
+	sets := map[string]*armdns.RecordSet{}
+
+	pager := a.Client.NewListByTypePager(
+		a.Zone.ResourceGroup,
+		a.Zone.Name,
+		armdns.RecordTypePTR,
+		&armdns.RecordSetsClientListByTypeOptions{
+			Recordsetnamesuffix: to.Ptr(a.relativeName(parent[1:])),
+		},
+	)
+
+	for pager.More() {
+		res, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list PTR record sets: %w", err)
+		}
+
+		for _, set := range res.Value {
+			name := a.absoluteName(*set.Name)
+			if hasSuffixFold(name, parent) {
+				sets[name] = set
+			}
+		}
+	}
+
+	return sets, nil
+}
+
+func (a *advertiser) syncSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *provider.ChangeSet,
+) error {
+	for _, st := range inst.Subtypes {
+		if err := a.addToPTRSet(ctx, subtypeName(inst, st), instanceName(inst), cs); err != nil {
+			return err
+		}
+	}
+
+	// Remove the instance from any subtypes that it no longer provides.
+	current, err := a.findSubtypePTRs(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for name, set := range current {
+		if slices.ContainsFunc(
+			inst.Subtypes,
+			func(st string) bool {
+				return strings.EqualFold(name, subtypeName(inst, st))
+			},
+		) {
+			continue
+		}
+
+		if err := a.removeFromPTRSet(ctx, name, set, instanceName(inst), cs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *advertiser) deleteSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *provider.ChangeSet,
+) error {
+	current, err := a.findSubtypePTRs(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for name, set := range current {
+		if err := a.removeFromPTRSet(ctx, name, set, instanceName(inst), cs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func subtypeName(inst provider.ServiceInstance, subtype string) string {
+	return dnssd.SelectiveInstanceEnumerationDomain(subtype, inst.ServiceType, inst.Domain) + "."
+}
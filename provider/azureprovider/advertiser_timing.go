@@ -0,0 +1,44 @@
+package azureprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+// throttleInterval is the amount of time to wait after a request is rejected
+// because an Azure Resource Manager rate limit was exceeded.
+//
+// The Azure SDK already retries throttled requests, honoring the Retry-After
+// header, so by the time the error is returned the limit is likely to be reset
+// soon.
+const throttleInterval = 5 * time.Second
+
+func (a *advertiser) ZoneTiming(ctx context.Context) (provider.ZoneTiming, error) {
+	set, err := a.get(ctx, a.Zone.Name+".", armdns.RecordTypeSOA)
+	if err != nil {
+		return provider.ZoneTiming{}, fmt.Errorf("unable to find SOA record: %w", err)
+	}
+
+	if set == nil || set.Properties == nil || set.Properties.SoaRecord == nil {
+		return provider.ZoneTiming{}, nil
+	}
+
+	soa := &dns.SOA{
+		Hdr: dns.RR_Header{
+			Ttl: uint32(ttl(set.Properties)),
+		},
+	}
+
+	if min := set.Properties.SoaRecord.MinimumTTL; min != nil {
+		soa.Minttl = uint32(*min)
+	}
+
+	return provider.ZoneTiming{
+		NegativeTTL: provider.NegativeTTL(soa),
+	}, nil
+}
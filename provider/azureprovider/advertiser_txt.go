@@ -0,0 +1,48 @@
+package azureprovider
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/dogmatiq/proclaim/provider"
+)
+
+func (a *advertiser) syncTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *provider.ChangeSet,
+) error {
+	current, err := a.get(ctx, instanceName(inst), armdns.RecordTypeTXT)
+	if err != nil {
+		return err
+	}
+
+	desired := &armdns.RecordSetProperties{
+		TTL: to.Ptr(int64(inst.TTL.Seconds())),
+	}
+
+	for _, rec := range provider.NewTXTRecords(inst) {
+		desired.TxtRecords = append(
+			desired.TxtRecords,
+			&armdns.TxtRecord{
+				Value: to.SliceOfPtrs(rec.Txt...),
+			},
+		)
+	}
+
+	return a.write(ctx, instanceName(inst), armdns.RecordTypeTXT, current, desired, cs)
+}
+
+func (a *advertiser) deleteTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *provider.ChangeSet,
+) error {
+	current, err := a.get(ctx, instanceName(inst), armdns.RecordTypeTXT)
+	if err != nil {
+		return err
+	}
+
+	return a.write(ctx, instanceName(inst), armdns.RecordTypeTXT, current, nil, cs)
+}
@@ -0,0 +1,3 @@
+// Package azureprovider provides a driver implementation that advertises DNS-SD
+// service instances on domain names hosted by Azure DNS.
+package azureprovider
@@ -0,0 +1,23 @@
+package azureprovider
+
+import (
+	"context"
+	"fmt"
+)
+
+// CheckHealth returns an error if the provider is unable to communicate or
+// authenticate with Azure DNS, or is unable to list the DNS zones within any
+// of its scopes.
+func (p *Provider) CheckHealth(ctx context.Context) error {
+	for _, s := range p.Scopes {
+		if err := p.eachZone(
+			ctx,
+			s,
+			func(zone) bool { return false },
+		); err != nil {
+			return fmt.Errorf("unable to list DNS zones in %s: %w", s, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+package azureprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/go-logr/logr"
+)
+
+// Provider is an implementation of provider.Provider that advertises DNS-SD
+// services on domains hosted by Azure DNS.
+type Provider struct {
+	// Credential is used to authenticate with Azure Resource Manager, such as
+	// a client secret or workload identity credential from the azidentity
+	// package.
+	Credential azcore.TokenCredential
+
+	// ClientOptions configures the Azure Resource Manager clients. It may be
+	// nil, in which case the Azure public cloud is used.
+	ClientOptions *arm.ClientOptions
+
+	// Scopes is the list of subscriptions and resource groups that are searched
+	// for DNS zones, in order of preference.
+	Scopes []Scope
+
+	// Name distinguishes this provider from other Azure DNS providers that use
+	// different credentials. It may be empty if there is only one such
+	// provider.
+	Name string
+
+	Logger logr.Logger
+}
+
+// Scope is a subscription, or a resource group within a subscription, that
+// contains DNS zones.
+type Scope struct {
+	SubscriptionID string
+
+	// ResourceGroup is the name of the resource group that contains the zones.
+	// If it is empty, zones in any resource group within the subscription may
+	// be used.
+	ResourceGroup string
+}
+
+// ParseScope parses a scope from its string representation, which is either
+// a subscription ID, or a subscription ID and resource group name separated by
+// a slash.
+func ParseScope(s string) (Scope, error) {
+	sub, rg, _ := strings.Cut(s, "/")
+
+	if sub == "" || strings.Contains(rg, "/") {
+		return Scope{}, fmt.Errorf("invalid scope (%s): expected <subscription-id> or <subscription-id>/<resource-group>", s)
+	}
+
+	return Scope{sub, rg}, nil
+}
+
+// String returns the string representation of the scope.
+func (s Scope) String() string {
+	if s.ResourceGroup == "" {
+		return s.SubscriptionID
+	}
+	return s.SubscriptionID + "/" + s.ResourceGroup
+}
+
+// ID returns a short unique identifier for the provider.
+func (p *Provider) ID() string {
+	return provider.NamedID("azure", p.Name)
+}
+
+// Describe returns a human-readable description of the provider.
+func (p *Provider) Describe() string {
+	return provider.NamedDescription("Azure DNS", p.Name)
+}
+
+// AdvertiserByID returns the Advertiser with the given ID.
+func (p *Provider) AdvertiserByID(
+	ctx context.Context,
+	id map[string]any,
+) (provider.Advertiser, error) {
+	z, err := unmarshalAdvertiserID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armdns.NewZonesClient(z.SubscriptionID, p.Credential, p.ClientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := client.Get(ctx, z.ResourceGroup, z.Name, nil); err != nil {
+		return nil, fmt.Errorf("unable to get DNS zone: %w", err)
+	}
+
+	return p.newAdvertiser(z)
+}
+
+// AdvertiserByDomain returns the Advertiser used to advertise services on the
+// given domain.
+//
+// If several of the provider's scopes contain a zone for the domain, the zone
+// in the first such scope is used.
+//
+// ok is false if this provider does not manage the given domain.
+func (p *Provider) AdvertiserByDomain(
+	ctx context.Context,
+	domain string,
+) (provider.Advertiser, bool, error) {
+	name := strings.TrimSuffix(domain, ".")
+
+	for _, s := range p.Scopes {
+		var (
+			z     zone
+			found bool
+		)
+
+		if err := p.eachZone(
+			ctx,
+			s,
+			func(candidate zone) bool {
+				if strings.EqualFold(candidate.Name, name) {
+					z = candidate
+					found = true
+				}
+				return !found
+			},
+		); err != nil {
+			return nil, false, fmt.Errorf("unable to list DNS zones in %s: %w", s, err)
+		}
+
+		if found {
+			a, err := p.newAdvertiser(z)
+			return a, true, err
+		}
+	}
+
+	return nil, false, nil
+}
+
+// eachZone calls fn for each DNS zone within the given scope until it returns
+// false.
+func (p *Provider) eachZone(
+	ctx context.Context,
+	s Scope,
+	fn func(zone) bool,
+) error {
+	client, err := armdns.NewZonesClient(s.SubscriptionID, p.Credential, p.ClientOptions)
+	if err != nil {
+		return err
+	}
+
+	if s.ResourceGroup == "" {
+		return eachZone(
+			ctx,
+			client.NewListPager(nil),
+			func(res armdns.ZonesClientListResponse) []*armdns.Zone { return res.Value },
+			fn,
+		)
+	}
+
+	return eachZone(
+		ctx,
+		client.NewListByResourceGroupPager(s.ResourceGroup, nil),
+		func(res armdns.ZonesClientListByResourceGroupResponse) []*armdns.Zone { return res.Value },
+		fn,
+	)
+}
+
+// eachZone calls fn for each DNS zone returned by a pager until it returns
+// false.
+func eachZone[T any](
+	ctx context.Context,
+	pager *runtime.Pager[T],
+	zones func(T) []*armdns.Zone,
+	fn func(zone) bool,
+) error {
+	for pager.More() {
+		res, err := pager.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, z := range zones(res) {
+			id, err := arm.ParseResourceID(*z.ID)
+			if err != nil {
+				return err
+			}
+
+			if !fn(zone{
+				SubscriptionID: id.SubscriptionID,
+				ResourceGroup:  id.ResourceGroupName,
+				Name:           *z.Name,
+			}) {
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) newAdvertiser(z zone) (*advertiser, error) {
+	client, err := armdns.NewRecordSetsClient(z.SubscriptionID, p.Credential, p.ClientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &advertiser{
+		client,
+		z,
+		p.Logger,
+	}, nil
+}
+
+// zone identifies an Azure DNS zone.
+type zone struct {
+	SubscriptionID string
+	ResourceGroup  string
+	Name           string
+}
+
+// marshalAdvertiserID returns the ID of the advertiser for the given zone.
+func marshalAdvertiserID(z zone) map[string]any {
+	return map[string]any{
+		"subscriptionID": z.SubscriptionID,
+		"resourceGroup":  z.ResourceGroup,
+		"zone":           z.Name,
+	}
+}
+
+// unmarshalAdvertiserID parses an advertiser ID into its constituent parts.
+func unmarshalAdvertiserID(id map[string]any) (zone, error) {
+	var z zone
+
+	for _, f := range []struct {
+		Key   string
+		Value *string
+	}{
+		{"subscriptionID", &z.SubscriptionID},
+		{"resourceGroup", &z.ResourceGroup},
+		{"zone", &z.Name},
+	} {
+		v, ok := id[f.Key]
+		if !ok {
+			return zone{}, fmt.Errorf("invalid advertiser ID: missing %s key", f.Key)
+		}
+
+		*f.Value, ok = v.(string)
+		if !ok || *f.Value == "" {
+			return zone{}, fmt.Errorf("invalid advertiser ID: %s must be a non-empty string", f.Key)
+		}
+	}
+
+	return z, nil
+}
+
+// hasStatusCode returns true if err is an Azure API error with the given HTTP
+// status code.
+func hasStatusCode(err error, code int) bool {
+	var e *azcore.ResponseError
+	return errors.As(err, &e) && e.StatusCode == code
+}
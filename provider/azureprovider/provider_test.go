@@ -0,0 +1,404 @@
+package azureprovider_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	. "github.com/dogmatiq/proclaim/provider/azureprovider"
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	subscriptionID = "00000000-0000-0000-0000-000000000000"
+	resourceGroup  = "dns-group"
+	domain         = "proclaim-test.example.org"
+	token          = "<token>"
+)
+
+// credential is an azcore.TokenCredential that always returns the same access
+// token.
+type credential string
+
+func (c credential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{
+		Token:     string(c),
+		ExpiresOn: time.Now().Add(1 * time.Hour),
+	}, nil
+}
+
+// newClientOptions returns options that configure the Azure Resource Manager
+// clients to use the API at the given URL.
+func newClientOptions(apiURL string, client *http.Client) *arm.ClientOptions {
+	return &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Cloud: cloud.Configuration{
+				ActiveDirectoryAuthorityHost: "https://login.example.org/",
+				Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {
+						Endpoint: apiURL,
+						Audience: "https://management.example.org",
+					},
+				},
+			},
+			Transport: client,
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		},
+		DisableRPRegistration: true,
+	}
+}
+
+var _ = Describe("type Provider", func() {
+	providertest.DeclareTestSuite(
+		func(ctx context.Context) providertest.TestContext {
+			srv := newServer(subscriptionID, resourceGroup, domain, token)
+			apiURL, client, port := srv.start()
+
+			return providertest.TestContext{
+				Provider: &Provider{
+					Credential:    credential(token),
+					ClientOptions: newClientOptions(apiURL, client),
+					Scopes: []Scope{
+						{SubscriptionID: subscriptionID},
+					},
+					Logger: logr.Discard(),
+				},
+				Domain: domain,
+				NameServers: func(ctx context.Context) ([]string, error) {
+					return []string{"127.0.0.1"}, nil
+				},
+				NameServerPort: port,
+				DeleteRecords: func(ctx context.Context) error {
+					srv.DeleteRecords()
+					return nil
+				},
+			}
+		},
+	)
+
+	var (
+		ctx  context.Context
+		srv  *server
+		port string
+		p    *Provider
+		inst provider.ServiceInstance
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var (
+			apiURL string
+			client *http.Client
+		)
+
+		srv = newServer(subscriptionID, resourceGroup, domain, token)
+		apiURL, client, port = srv.start()
+
+		p = &Provider{
+			Credential:    credential(token),
+			ClientOptions: newClientOptions(apiURL, client),
+			Scopes: []Scope{
+				{SubscriptionID: subscriptionID},
+			},
+			Logger: logr.Discard(),
+		}
+
+		inst = provider.ServiceInstance{
+			Name:        "instance",
+			ServiceType: "_proclaim._tcp",
+			Domain:      domain,
+			Targets: []provider.Target{
+				{Host: "host.example.com", Port: 443},
+			},
+			TTL: 5 * time.Second,
+		}
+	})
+
+	newResolver := func() *dnssd.UnicastResolver {
+		return &dnssd.UnicastResolver{
+			Config: &dns.ClientConfig{
+				Servers:  []string{"127.0.0.1"},
+				Port:     port,
+				Ndots:    1,
+				Timeout:  1,
+				Attempts: 3,
+			},
+		}
+	}
+
+	Describe("func AdvertiserByDomain()", func() {
+		It("finds zones in any resource group within the subscription", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(a.ID()).To(Equal(map[string]any{
+				"subscriptionID": subscriptionID,
+				"resourceGroup":  resourceGroup,
+				"zone":           domain,
+			}))
+		})
+
+		It("finds zones in a specific resource group", func() {
+			p.Scopes = []Scope{
+				{SubscriptionID: subscriptionID, ResourceGroup: resourceGroup},
+			}
+
+			_, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+
+		It("ignores zones in resource groups outside of its scopes", func() {
+			p.Scopes = []Scope{
+				{SubscriptionID: subscriptionID, ResourceGroup: "other-group"},
+			}
+
+			_, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		It("searches each of its scopes in order", func() {
+			p.Scopes = []Scope{
+				{SubscriptionID: subscriptionID, ResourceGroup: "other-group"},
+				{SubscriptionID: subscriptionID, ResourceGroup: resourceGroup},
+			}
+
+			_, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+
+		It("returns an error if the subscription does not exist", func() {
+			p.Scopes = []Scope{
+				{SubscriptionID: "<unknown>"},
+			}
+
+			_, _, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).To(MatchError(ContainSubstring("unable to list DNS zones in <unknown>")))
+		})
+	})
+
+	Describe("func AdvertiserByID()", func() {
+		It("returns an advertiser for the zone with the given ID", func() {
+			id := map[string]any{
+				"subscriptionID": subscriptionID,
+				"resourceGroup":  resourceGroup,
+				"zone":           domain,
+			}
+
+			a, err := p.AdvertiserByID(ctx, id)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(a.ID()).To(Equal(id))
+		})
+
+		It("returns an error if the zone does not exist", func() {
+			_, err := p.AdvertiserByID(
+				ctx,
+				map[string]any{
+					"subscriptionID": subscriptionID,
+					"resourceGroup":  resourceGroup,
+					"zone":           "unknown.example.org",
+				},
+			)
+			Expect(err).To(MatchError(ContainSubstring("unable to get DNS zone")))
+		})
+
+		It("returns an error if the ID is invalid", func() {
+			_, err := p.AdvertiserByID(
+				ctx,
+				map[string]any{
+					"subscriptionID": subscriptionID,
+					"zone":           domain,
+				},
+			)
+			Expect(err).To(MatchError("invalid advertiser ID: missing resourceGroup key"))
+		})
+	})
+
+	When("there are more record sets than fit on a single page", func() {
+		It("finds record sets on subsequent pages", func() {
+			srv.MaxResults = 1
+
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			inst.Subtypes = []string{"_a", "_b"}
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			inst.Subtypes = []string{"_a"}
+
+			cs, err := a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cs.PTR).To(Equal(provider.Deleted))
+
+			_, err = a.Unadvertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			srv.m.Lock()
+			defer srv.m.Unlock()
+			Expect(srv.sets).To(HaveLen(2)) // SOA and NS
+		})
+	})
+
+	When("another client modifies a shared record set", func() {
+		It("does not overwrite the other client's changes", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			// Simulate another controller advertising a second instance of the
+			// same service type after the record set has been read, but before
+			// it is written.
+			srv.BeforeWrite = func(recordType, name string) {
+				if recordType == "PTR" && name == "_proclaim._tcp" {
+					srv.BeforeWrite = nil
+					srv.Insert(rrset{
+						Name: "_proclaim._tcp",
+						Type: "PTR",
+						Properties: armdns.RecordSetProperties{
+							TTL: to.Ptr[int64](30),
+							PtrRecords: []*armdns.PtrRecord{
+								{Ptrdname: to.Ptr("other._proclaim._tcp." + domain + ".")},
+							},
+						},
+					})
+				}
+			}
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).To(MatchError(ContainSubstring("PTR record set was modified concurrently")))
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			instances, err := newResolver().EnumerateInstances(ctx, "_proclaim._tcp", domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(instances).To(ConsistOf("instance", "other"))
+		})
+	})
+
+	When("another client advertises an instance of the same type while the last instance is unadvertised", func() {
+		It("does not remove the service type enumeration record", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// Simulate another controller advertising a second instance of the
+			// same service type after the instance enumeration record set has
+			// been deleted, but before the service type is removed. The other
+			// controller sees that the service type is already enumerated.
+			srv.BeforeWrite = func(recordType, name string) {
+				if recordType == "PTR" && name == "_services._dns-sd._udp" {
+					srv.BeforeWrite = nil
+					srv.Insert(rrset{
+						Name: "_proclaim._tcp",
+						Type: "PTR",
+						Properties: armdns.RecordSetProperties{
+							TTL: to.Ptr[int64](30),
+							PtrRecords: []*armdns.PtrRecord{
+								{Ptrdname: to.Ptr("other._proclaim._tcp." + domain + ".")},
+							},
+						},
+					})
+				}
+			}
+
+			_, err = a.Unadvertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceTypes, err := newResolver().EnumerateServiceTypes(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(serviceTypes).To(ConsistOf("_proclaim._tcp"))
+		})
+	})
+
+	When("the credentials are invalid", func() {
+		BeforeEach(func() {
+			p.Credential = credential("<invalid>")
+		})
+
+		It("returns an error when finding an advertiser", func() {
+			_, _, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).To(MatchError(ContainSubstring("InvalidAuthenticationToken")))
+		})
+
+		It("fails the health check", func() {
+			err := p.CheckHealth(ctx)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("func CheckHealth()", func() {
+		It("returns nil if the zones in each scope can be listed", func() {
+			err := p.CheckHealth(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Describe("func ID()", func() {
+		It("includes the provider's name", func() {
+			a := &Provider{Name: "a"}
+			b := &Provider{Name: "b"}
+
+			Expect(a.ID()).To(Equal("azure:a"))
+			Expect(b.ID()).To(Equal("azure:b"))
+			Expect(a.Describe()).To(Equal("Azure DNS [a]"))
+		})
+
+		It("does not include an empty name", func() {
+			p := &Provider{}
+
+			Expect(p.ID()).To(Equal("azure"))
+			Expect(p.Describe()).To(Equal("Azure DNS"))
+		})
+	})
+})
+
+var _ = Describe("func ParseScope()", func() {
+	It("parses a subscription ID", func() {
+		s, err := ParseScope(subscriptionID)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(s).To(Equal(Scope{SubscriptionID: subscriptionID}))
+		Expect(s.String()).To(Equal(subscriptionID))
+	})
+
+	It("parses a subscription ID and resource group", func() {
+		s, err := ParseScope(subscriptionID + "/" + resourceGroup)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(s).To(Equal(Scope{SubscriptionID: subscriptionID, ResourceGroup: resourceGroup}))
+		Expect(s.String()).To(Equal(subscriptionID + "/" + resourceGroup))
+	})
+
+	DescribeTable(
+		"it returns an error if the scope is invalid",
+		func(s string) {
+			_, err := ParseScope(s)
+			Expect(err).To(MatchError(ContainSubstring("invalid scope")))
+		},
+		Entry("empty", ""),
+		Entry("empty subscription ID", "/"+resourceGroup),
+		Entry("too many components", subscriptionID+"/"+resourceGroup+"/extra"),
+	)
+})
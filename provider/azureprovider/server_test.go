@@ -0,0 +1,471 @@
+package azureprovider_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	"github.com/miekg/dns"
+	"github.com/onsi/ginkgo/v2"
+	"golang.org/x/exp/slices"
+)
+
+// server is a stand-in for Azure DNS. It serves the subset of the Azure
+// Resource Manager API used by the provider over HTTPS, and answers DNS
+// queries for the records in its zone so that the advertised services can be
+// discovered.
+//
+// The subscription contains a second, empty zone in a different resource
+// group.
+type server struct {
+	SubscriptionID string
+	ResourceGroup  string
+	Zone           string
+	Token          string
+
+	// MaxResults is the maximum number of results in each page of a list
+	// response.
+	MaxResults int
+
+	// BeforeWrite, if non-nil, is called before each record set is created,
+	// replaced or deleted. It can be used to simulate changes made by other
+	// clients.
+	BeforeWrite func(recordType, name string)
+
+	m     sync.Mutex
+	sets  []rrset
+	etags int
+}
+
+// rrset is a record set within the server's zone.
+type rrset struct {
+	Name       string
+	Type       string
+	Etag       string
+	Properties armdns.RecordSetProperties
+}
+
+const (
+	otherResourceGroup = "other-group"
+	otherZone          = "other.example.org"
+)
+
+// newServer returns a new server with a single zone in the given subscription
+// and resource group.
+func newServer(subscriptionID, resourceGroup, zone, token string) *server {
+	s := &server{
+		SubscriptionID: subscriptionID,
+		ResourceGroup:  resourceGroup,
+		Zone:           zone,
+		Token:          token,
+		MaxResults:     100,
+	}
+
+	s.DeleteRecords()
+
+	return s
+}
+
+// start starts the HTTPS and DNS servers on random ports on the loopback
+// interface. It returns the URL of the API, an HTTP client that trusts the
+// server's certificate and the DNS port. The servers are stopped when the
+// current test ends.
+func (s *server) start() (apiURL string, client *http.Client, dnsPort string) {
+	api := httptest.NewTLSServer(http.HandlerFunc(s.ServeHTTP))
+	ginkgo.DeferCleanup(api.Close)
+
+	port := providertest.StartDNSServer(
+		&providertest.Responder{
+			Zone:    s.Zone + ".",
+			Records: s.dnsRecords,
+		},
+		nil,
+	)
+
+	return api.URL, api.Client(), port
+}
+
+// DeleteRecords removes all record sets from the zone other than the SOA and
+// NS record sets at the zone apex.
+func (s *server) DeleteRecords() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.sets = nil
+
+	s.insert(rrset{
+		Name: "@",
+		Type: "SOA",
+		Properties: armdns.RecordSetProperties{
+			TTL: to.Ptr[int64](3600),
+			SoaRecord: &armdns.SoaRecord{
+				Host:         to.Ptr("ns1-01.azure-dns.com."),
+				Email:        to.Ptr("azuredns-hostmaster.microsoft.com"),
+				SerialNumber: to.Ptr[int64](1),
+				RefreshTime:  to.Ptr[int64](3600),
+				RetryTime:    to.Ptr[int64](300),
+				ExpireTime:   to.Ptr[int64](2419200),
+				MinimumTTL:   to.Ptr[int64](5),
+			},
+		},
+	})
+
+	s.insert(rrset{
+		Name: "@",
+		Type: "NS",
+		Properties: armdns.RecordSetProperties{
+			TTL: to.Ptr[int64](172800),
+			NsRecords: []*armdns.NsRecord{
+				{Nsdname: to.Ptr("ns1-01.azure-dns.com.")},
+			},
+		},
+	})
+}
+
+// Insert adds a record set to the zone, or replaces an existing set with the
+// same name and type.
+func (s *server) Insert(set rrset) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.insert(set)
+}
+
+func (s *server) insert(set rrset) {
+	s.etags++
+	set.Etag = strconv.Itoa(s.etags)
+
+	if i := s.indexOf(set.Name, set.Type); i != -1 {
+		s.sets[i] = set
+	} else {
+		s.sets = append(s.sets, set)
+	}
+}
+
+func (s *server) indexOf(name, recordType string) int {
+	for i, set := range s.sets {
+		if strings.EqualFold(set.Name, name) && set.Type == recordType {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "InvalidAuthenticationToken", "The access token is invalid.")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	if len(segments) < 2 || segments[0] != "subscriptions" || segments[1] != s.SubscriptionID {
+		writeError(w, http.StatusNotFound, "SubscriptionNotFound", "The subscription could not be found.")
+		return
+	}
+	segments = segments[2:]
+
+	if r.Method != http.MethodGet && len(segments) == 8 && s.BeforeWrite != nil {
+		s.BeforeWrite(segments[6], segments[7])
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// /subscriptions/{sub}/providers/Microsoft.Network/dnszones
+	if len(segments) == 3 && segments[0] == "providers" && strings.EqualFold(segments[2], "dnszones") {
+		s.writePage(w, r, []any{
+			s.zone(s.ResourceGroup, s.Zone),
+			s.zone(otherResourceGroup, otherZone),
+		})
+		return
+	}
+
+	// /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.Network/dnsZones/...
+	if len(segments) < 5 || segments[0] != "resourceGroups" || segments[2] != "providers" || !strings.EqualFold(segments[4], "dnszones") {
+		writeError(w, http.StatusNotFound, "NotFound", "Not Found")
+		return
+	}
+
+	group := segments[1]
+	segments = segments[5:]
+
+	var zones []any
+	if group == s.ResourceGroup {
+		zones = append(zones, s.zone(s.ResourceGroup, s.Zone))
+	} else if group == otherResourceGroup {
+		zones = append(zones, s.zone(otherResourceGroup, otherZone))
+	}
+
+	if len(segments) == 0 {
+		s.writePage(w, r, zones)
+		return
+	}
+
+	if group != s.ResourceGroup || segments[0] != s.Zone {
+		writeError(w, http.StatusNotFound, "ResourceNotFound", fmt.Sprintf("The DNS zone '%s' could not be found.", segments[0]))
+		return
+	}
+
+	switch len(segments) {
+	case 1:
+		writeJSON(w, http.StatusOK, zones[0])
+
+	case 2:
+		recordType := segments[1]
+		suffix := r.URL.Query().Get("$recordsetnamesuffix")
+
+		var sets []any
+		for _, set := range s.sets {
+			if set.Type == recordType && (suffix == "" || hasSuffixFold(set.Name, "."+suffix)) {
+				sets = append(sets, s.recordSet(set))
+			}
+		}
+
+		s.writePage(w, r, sets)
+
+	case 3:
+		s.serveRecordSet(w, r, segments[1], segments[2])
+
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", "Not Found")
+	}
+}
+
+// serveRecordSet handles requests that get, create, replace or delete a
+// single record set.
+func (s *server) serveRecordSet(w http.ResponseWriter, r *http.Request, recordType, name string) {
+	i := s.indexOf(name, recordType)
+
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch != "" && (i == -1 || s.sets[i].Etag != ifMatch) {
+		writeError(w, http.StatusPreconditionFailed, "PreconditionFailed", "The condition specified using HTTP conditional header(s) is not met.")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if i == -1 {
+			writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("The resource record '%s' does not exist in resource group '%s' of subscription '%s'.", name, s.ResourceGroup, s.SubscriptionID))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, s.recordSet(s.sets[i]))
+
+	case http.MethodPut:
+		if r.Header.Get("If-None-Match") == "*" && i != -1 {
+			writeError(w, http.StatusPreconditionFailed, "PreconditionFailed", "The condition specified using HTTP conditional header(s) is not met.")
+			return
+		}
+
+		var req armdns.RecordSet
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Properties == nil {
+			writeError(w, http.StatusBadRequest, "BadRequest", "The request body is invalid.")
+			return
+		}
+
+		set := rrset{
+			Name:       name,
+			Type:       recordType,
+			Properties: *req.Properties,
+		}
+
+		for _, rr := range s.toRRs(set) {
+			if rr == nil {
+				writeError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("The record set '%s' contains invalid records.", name))
+				return
+			}
+		}
+
+		status := http.StatusCreated
+		if i != -1 {
+			status = http.StatusOK
+		}
+
+		s.insert(set)
+		writeJSON(w, status, s.recordSet(s.sets[s.indexOf(name, recordType)]))
+
+	case http.MethodDelete:
+		if i == -1 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		s.sets = slices.Delete(s.sets, i, i+1)
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "The method is not allowed.")
+	}
+}
+
+func (s *server) zone(group, name string) map[string]any {
+	return map[string]any{
+		"id":       fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/dnszones/%s", s.SubscriptionID, group, name),
+		"name":     name,
+		"type":     "Microsoft.Network/dnszones",
+		"location": "global",
+		"properties": map[string]any{
+			"zoneType": "Public",
+		},
+	}
+}
+
+func (s *server) recordSet(set rrset) armdns.RecordSet {
+	props := set.Properties
+	props.Fqdn = to.Ptr(s.fqdn(set.Name))
+
+	return armdns.RecordSet{
+		ID:         to.Ptr(fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/dnszones/%s/%s/%s", s.SubscriptionID, s.ResourceGroup, s.Zone, set.Type, set.Name)),
+		Name:       to.Ptr(set.Name),
+		Type:       to.Ptr("Microsoft.Network/dnszones/" + set.Type),
+		Etag:       to.Ptr(set.Etag),
+		Properties: &props,
+	}
+}
+
+func (s *server) fqdn(name string) string {
+	if name == "@" {
+		return s.Zone + "."
+	}
+	return name + "." + s.Zone + "."
+}
+
+// writePage writes the requested page of a list of items.
+func (s *server) writePage(w http.ResponseWriter, r *http.Request, items []any) {
+	q := r.URL.Query()
+	offset, _ := strconv.Atoi(q.Get("$skipToken"))
+
+	end := offset + s.MaxResults
+	if end > len(items) {
+		end = len(items)
+	}
+
+	res := map[string]any{
+		"value": append([]any{}, items[offset:end]...),
+	}
+
+	if end < len(items) {
+		q.Set("$skipToken", strconv.Itoa(end))
+		res["nextLink"] = (&url.URL{
+			Scheme:   "https",
+			Host:     r.Host,
+			Path:     r.URL.Path,
+			RawQuery: q.Encode(),
+		}).String()
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// toRRs converts a record set to its DNS representation. An element is nil
+// if the corresponding record is invalid.
+func (s *server) toRRs(set rrset) []dns.RR {
+	hdr := dns.RR_Header{
+		Name:   s.fqdn(set.Name),
+		Rrtype: dns.StringToType[set.Type],
+		Class:  dns.ClassINET,
+	}
+
+	if set.Properties.TTL != nil {
+		hdr.Ttl = uint32(*set.Properties.TTL)
+	}
+
+	var records []dns.RR
+	p := set.Properties
+
+	switch set.Type {
+	case "SOA":
+		records = append(records, &dns.SOA{
+			Hdr:     hdr,
+			Ns:      *p.SoaRecord.Host,
+			Mbox:    dns.Fqdn(*p.SoaRecord.Email),
+			Serial:  uint32(*p.SoaRecord.SerialNumber),
+			Refresh: uint32(*p.SoaRecord.RefreshTime),
+			Retry:   uint32(*p.SoaRecord.RetryTime),
+			Expire:  uint32(*p.SoaRecord.ExpireTime),
+			Minttl:  uint32(*p.SoaRecord.MinimumTTL),
+		})
+
+	case "NS":
+		for _, rec := range p.NsRecords {
+			records = append(records, &dns.NS{Hdr: hdr, Ns: *rec.Nsdname})
+		}
+
+	case "PTR":
+		for _, rec := range p.PtrRecords {
+			var rr dns.RR
+			if rec.Ptrdname != nil {
+				rr = &dns.PTR{Hdr: hdr, Ptr: dns.Fqdn(*rec.Ptrdname)}
+			}
+			records = append(records, rr)
+		}
+
+	case "SRV":
+		for _, rec := range p.SrvRecords {
+			var rr dns.RR
+			if rec.Priority != nil && rec.Weight != nil && rec.Port != nil && rec.Target != nil {
+				rr = &dns.SRV{
+					Hdr:      hdr,
+					Priority: uint16(*rec.Priority),
+					Weight:   uint16(*rec.Weight),
+					Port:     uint16(*rec.Port),
+					Target:   dns.Fqdn(*rec.Target),
+				}
+			}
+			records = append(records, rr)
+		}
+
+	case "TXT":
+		for _, rec := range p.TxtRecords {
+			txt := &dns.TXT{Hdr: hdr}
+			for _, v := range rec.Value {
+				txt.Txt = append(txt.Txt, *v)
+			}
+			records = append(records, txt)
+		}
+	}
+
+	return records
+}
+
+// dnsRecords returns the DNS representation of the record sets in the zone.
+func (s *server) dnsRecords() []dns.RR {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var records []dns.RR
+	for _, set := range s.sets {
+		records = append(records, s.toRRs(set)...)
+	}
+
+	return records
+}
+
+func hasSuffixFold(s, suffix string) bool {
+	return len(s) >= len(suffix) &&
+		strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
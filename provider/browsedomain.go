@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/miekg/dns"
+	"golang.org/x/exp/slices"
+)
+
+// BrowseDomainAdvertiser is an Advertiser that can also advertise the DNS-SD
+// browse and registration domains of the domain it manages.
+//
+// See https://www.rfc-editor.org/rfc/rfc6763#section-11.
+type BrowseDomainAdvertiser interface {
+	Advertiser
+
+	// AdvertiseBrowseDomains adds/updates the domain enumeration PTR record
+	// sets such that they contain exactly the records described by d.
+	//
+	// Unlike the PTR record sets used to enumerate service instances, the
+	// domain enumeration record sets are not shared. Any records within the
+	// sets that are not described by d are removed.
+	AdvertiseBrowseDomains(ctx context.Context, d BrowseDomains) (ChangeSet, error)
+
+	// UnadvertiseBrowseDomains removes the domain enumeration PTR record sets
+	// for d.Domain.
+	UnadvertiseBrowseDomains(ctx context.Context, d BrowseDomains) (ChangeSet, error)
+}
+
+// BrowseDomains describes the domains that DNS-SD clients are directed to
+// when performing domain enumeration.
+type BrowseDomains struct {
+	// Domain is the domain on which the domain enumeration records are
+	// published, that is, the domain that clients query.
+	Domain string
+
+	// Browse is the set of domains that are recommended for browsing.
+	Browse []string
+
+	// DefaultBrowse is the domain that is recommended as the default for
+	// browsing, if any.
+	DefaultBrowse string
+
+	// LegacyBrowse is the set of domains that are recommended for browsing by
+	// clients that do not perform domain enumeration themselves.
+	LegacyBrowse []string
+
+	// Registration is the set of domains that are recommended for registering
+	// services.
+	Registration []string
+
+	// DefaultRegistration is the domain that is recommended as the default for
+	// registering services, if any.
+	DefaultRegistration string
+
+	// TTL is the time-to-live of the domain enumeration records.
+	TTL time.Duration
+}
+
+// BrowseDomainRecordSet is a set of PTR records that share the same name.
+type BrowseDomainRecordSet struct {
+	// Name is the fully-qualified name of the record set, for example
+	// "b._dns-sd._udp.example.org.".
+	Name string
+
+	// Records contains the PTR records within the set. It is empty if the set
+	// should not exist.
+	Records []*dns.PTR
+}
+
+// NewBrowseDomainRecordSets returns the domain enumeration PTR record sets for
+// d.
+//
+// It always returns a record set for each of the domain enumeration names,
+// including those that have no records.
+func NewBrowseDomainRecordSets(d BrowseDomains) []BrowseDomainRecordSet {
+	var defaultBrowse, defaultRegistration []string
+
+	if d.DefaultBrowse != "" {
+		defaultBrowse = []string{d.DefaultBrowse}
+	}
+
+	if d.DefaultRegistration != "" {
+		defaultRegistration = []string{d.DefaultRegistration}
+	}
+
+	return []BrowseDomainRecordSet{
+		newBrowseDomainRecordSet(d, "b", d.Browse),
+		newBrowseDomainRecordSet(d, "db", defaultBrowse),
+		newBrowseDomainRecordSet(d, "lb", d.LegacyBrowse),
+		newBrowseDomainRecordSet(d, "r", d.Registration),
+		newBrowseDomainRecordSet(d, "dr", defaultRegistration),
+	}
+}
+
+func newBrowseDomainRecordSet(
+	d BrowseDomains,
+	label string,
+	domains []string,
+) BrowseDomainRecordSet {
+	set := BrowseDomainRecordSet{
+		Name: dns.Fqdn(label + "._dns-sd._udp." + d.Domain),
+	}
+
+	ttl := d.TTL
+	if ttl <= 0 {
+		ttl = dnssd.DefaultTTL
+	}
+
+	for _, domain := range domains {
+		domain = dns.Fqdn(domain)
+
+		// A record set can not contain duplicate records.
+		if slices.ContainsFunc(
+			set.Records,
+			func(rr *dns.PTR) bool {
+				return strings.EqualFold(rr.Ptr, domain)
+			},
+		) {
+			continue
+		}
+
+		set.Records = append(
+			set.Records,
+			&dns.PTR{
+				Hdr: dns.RR_Header{
+					Name:   set.Name,
+					Rrtype: dns.TypePTR,
+					Class:  dns.ClassINET,
+					Ttl:    uint32(ttl.Seconds()),
+				},
+				Ptr: domain,
+			},
+		)
+	}
+
+	return set
+}
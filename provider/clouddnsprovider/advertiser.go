@@ -0,0 +1,259 @@
+package clouddnsprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/clouddnsprovider/internal/clouddnsapi"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+)
+
+type advertiser struct {
+	Client *clouddnsapi.Client
+	Zone   clouddnsapi.ManagedZone
+	Logger logr.Logger
+}
+
+func (a *advertiser) ID() map[string]any {
+	return marshalAdvertiserID(a.Zone)
+}
+
+func (a *advertiser) Advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs, err := a.advertise(ctx, inst)
+	return cs, rateLimitError(err)
+}
+
+func (a *advertiser) advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs := &clouddnsapi.Change{}
+
+	if err := a.syncServiceTypePTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncPTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncSRV(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncTXT(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) Unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs, err := a.unadvertise(ctx, inst)
+	return cs, rateLimitError(err)
+}
+
+func (a *advertiser) unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs := &clouddnsapi.Change{}
+
+	if err := a.deletePTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteSRV(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteTXT(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) apply(
+	ctx context.Context,
+	cs *clouddnsapi.Change,
+) (provider.ChangeSet, error) {
+	if len(cs.Additions) == 0 && len(cs.Deletions) == 0 {
+		return provider.ChangeSet{}, nil
+	}
+
+	out, err := a.Client.CreateChange(ctx, a.Zone.Name, *cs)
+	if err != nil {
+		if clouddnsapi.IsConflict(err) {
+			return provider.ChangeSet{}, fmt.Errorf("records were modified concurrently: %w", err)
+		}
+		return provider.ChangeSet{}, fmt.Errorf("unable to create change: %w", err)
+	}
+
+	var result provider.ChangeSet
+
+	if out.Status != clouddnsapi.ChangeStatusDone {
+		result.PendingChanges = append(result.PendingChanges, out.ID)
+	}
+
+	// A record set that is both deleted and added within the same change has
+	// been updated.
+	type setKey struct{ Name, Type string }
+	changes := map[setKey]provider.Change{}
+	key := func(set clouddnsapi.ResourceRecordSet) setKey {
+		return setKey{strings.ToLower(set.Name), set.Type}
+	}
+
+	for _, set := range cs.Deletions {
+		changes[key(set)] = provider.Deleted
+	}
+
+	for _, set := range cs.Additions {
+		if _, ok := changes[key(set)]; ok {
+			changes[key(set)] = provider.Updated
+		} else {
+			changes[key(set)] = provider.Created
+		}
+	}
+
+	for k, change := range changes {
+		switch k.Type {
+		case "PTR":
+			result.PTR |= change
+		case "SRV":
+			result.SRV |= change
+		case "TXT":
+			result.TXT |= change
+		}
+	}
+
+	for _, set := range cs.Deletions {
+		a.log("DELETE", set)
+	}
+
+	for _, set := range cs.Additions {
+		a.log("CREATE", set)
+	}
+
+	return result, nil
+}
+
+func (a *advertiser) log(action string, set clouddnsapi.ResourceRecordSet) {
+	for _, v := range set.Rrdatas {
+		a.Logger.Info(
+			action+" record",
+			"type", set.Type,
+			"name", set.Name,
+			"value", v,
+			"ttl", set.TTL,
+		)
+	}
+}
+
+// findResourceRecordSet returns the record set with the given fully-qualified
+// name and type.
+func (a *advertiser) findResourceRecordSet(
+	ctx context.Context,
+	name, recordType string,
+) (clouddnsapi.ResourceRecordSet, bool, error) {
+	_, sets, err := a.Client.ListResourceRecordSets(ctx, a.Zone.Name, name, recordType, "")
+	if err != nil {
+		return clouddnsapi.ResourceRecordSet{}, false, fmt.Errorf("unable to list %s records: %w", recordType, err)
+	}
+
+	for _, set := range sets {
+		if strings.EqualFold(set.Name, name) && set.Type == recordType {
+			return set, true, nil
+		}
+	}
+
+	return clouddnsapi.ResourceRecordSet{}, false, nil
+}
+
+// syncResourceRecordSet adds changes to cs that replace the record set with
+// the same name and type as desired, if it differs.
+func (a *advertiser) syncResourceRecordSet(
+	ctx context.Context,
+	desired clouddnsapi.ResourceRecordSet,
+	cs *clouddnsapi.Change,
+) error {
+	current, ok, err := a.findResourceRecordSet(ctx, desired.Name, desired.Type)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		if current.Equal(desired) {
+			return nil
+		}
+
+		cs.Deletions = append(cs.Deletions, current)
+	}
+
+	if len(desired.Rrdatas) != 0 {
+		cs.Additions = append(cs.Additions, desired)
+	}
+
+	return nil
+}
+
+// deleteResourceRecordSet adds a change to cs that deletes the record set with
+// the given name and type, if it exists.
+func (a *advertiser) deleteResourceRecordSet(
+	ctx context.Context,
+	name, recordType string,
+	cs *clouddnsapi.Change,
+) error {
+	current, ok, err := a.findResourceRecordSet(ctx, name, recordType)
+	if ok {
+		cs.Deletions = append(cs.Deletions, current)
+	}
+	return err
+}
+
+func instanceName(inst provider.ServiceInstance) string {
+	return dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, inst.Domain) + "."
+}
+
+func serviceName(inst provider.ServiceInstance) string {
+	return dnssd.InstanceEnumerationDomain(inst.ServiceType, inst.Domain) + "."
+}
+
+func convertRecords[
+	R interface {
+		Header() *dns.RR_Header
+		String() string
+	},
+](records ...R) []string {
+	var result []string
+
+	for _, rec := range records {
+		result = append(
+			result,
+			strings.TrimPrefix(
+				rec.String(),
+				rec.Header().String(),
+			),
+		)
+	}
+
+	return result
+}
@@ -0,0 +1,64 @@
+package clouddnsprovider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/clouddnsprovider/internal/clouddnsapi"
+)
+
+func (a *advertiser) AdvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs, err := a.advertiseBrowseDomains(ctx, d)
+	return cs, rateLimitError(err)
+}
+
+func (a *advertiser) advertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs := &clouddnsapi.Change{}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		desired := clouddnsapi.ResourceRecordSet{
+			Name:    set.Name,
+			Type:    "PTR",
+			Rrdatas: convertRecords(set.Records...),
+		}
+
+		if len(set.Records) != 0 {
+			desired.TTL = int64(set.Records[0].Hdr.Ttl)
+		}
+
+		if err := a.syncResourceRecordSet(ctx, desired, cs); err != nil {
+			return provider.ChangeSet{}, err
+		}
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) UnadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs, err := a.unadvertiseBrowseDomains(ctx, d)
+	return cs, rateLimitError(err)
+}
+
+func (a *advertiser) unadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs := &clouddnsapi.Change{}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		if err := a.deleteResourceRecordSet(ctx, set.Name, "PTR", cs); err != nil {
+			return provider.ChangeSet{}, err
+		}
+	}
+
+	return a.apply(ctx, cs)
+}
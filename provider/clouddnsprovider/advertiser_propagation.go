@@ -0,0 +1,34 @@
+package clouddnsprovider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/proclaim/provider/clouddnsprovider/internal/clouddnsapi"
+)
+
+// IsPropagated returns true if all of the given changes have been applied to
+// the zone's authoritative name servers, that is, their status is "done".
+func (a *advertiser) IsPropagated(ctx context.Context, ids []string) (bool, error) {
+	for _, id := range ids {
+		ch, err := a.Client.GetChange(ctx, a.Zone.Name, id)
+		if err != nil {
+			if clouddnsapi.IsRateLimited(err) {
+				return false, nil
+			}
+
+			// A change that no longer exists can not be pending.
+			if clouddnsapi.IsNotFound(err) {
+				continue
+			}
+
+			return false, fmt.Errorf("unable to get change status: %w", err)
+		}
+
+		if ch.Status != clouddnsapi.ChangeStatusDone {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,130 @@
+package clouddnsprovider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/clouddnsprovider/internal/clouddnsapi"
+	"golang.org/x/exp/slices"
+)
+
+// ptrTTL is the TTL of PTR records that enumerate service instances.
+//
+// Normally we'd use each service's TTL for its respective PTR record, but Cloud
+// DNS stores all records with the same name and type in a single "record set",
+// which means they all share a TTL.
+const ptrTTL = 30 * time.Second
+
+func (a *advertiser) syncPTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *clouddnsapi.Change,
+) error {
+	return a.addToPTRSet(ctx, serviceName(inst), instanceName(inst), cs)
+}
+
+func (a *advertiser) deletePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *clouddnsapi.Change,
+) error {
+	current, ok, err := a.findResourceRecordSet(ctx, serviceName(inst), "PTR")
+	if !ok || err != nil {
+		return err
+	}
+
+	index := indexOf(current, instanceName(inst))
+	if index == -1 {
+		return nil
+	}
+
+	removeFromPTRSet(current, index, cs)
+
+	// If this is the last instance of its service type, the service type
+	// itself is no longer advertised.
+	if len(current.Rrdatas) == 1 {
+		return a.deleteServiceTypePTR(ctx, inst, cs)
+	}
+
+	return nil
+}
+
+// addToPTRSet adds a PTR record that refers to the target name to the shared
+// PTR record set with the given name.
+//
+// The record set is shared with other instances, so it is never modified in
+// place. Instead, the existing set is deleted and a new set containing the
+// additional record is added within the same change. The change fails if any
+// other process has modified the set in the meantime, because the deletion no
+// longer matches the existing set exactly.
+func (a *advertiser) addToPTRSet(
+	ctx context.Context,
+	name, target string,
+	cs *clouddnsapi.Change,
+) error {
+	desired := clouddnsapi.ResourceRecordSet{
+		Name:    name,
+		Type:    "PTR",
+		TTL:     int64(ptrTTL.Seconds()),
+		Rrdatas: []string{target},
+	}
+
+	current, ok, err := a.findResourceRecordSet(ctx, name, "PTR")
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		if indexOf(current, target) != -1 {
+			return nil
+		}
+
+		desired.Rrdatas = append(desired.Rrdatas, current.Rrdatas...)
+		cs.Deletions = append(cs.Deletions, current)
+	}
+
+	cs.Additions = append(cs.Additions, desired)
+
+	return nil
+}
+
+// removeFromPTRSet removes the PTR record at the given index from the given
+// shared PTR record set.
+//
+// See addToPTRSet() for details about how the shared record set is updated.
+func removeFromPTRSet(
+	current clouddnsapi.ResourceRecordSet,
+	index int,
+	cs *clouddnsapi.Change,
+) {
+	cs.Deletions = append(cs.Deletions, current)
+
+	desired := clouddnsapi.ResourceRecordSet{
+		Name: current.Name,
+		Type: "PTR",
+		TTL:  int64(ptrTTL.Seconds()),
+		Rrdatas: slices.Delete(
+			slices.Clone(current.Rrdatas),
+			index,
+			index+1,
+		),
+	}
+
+	if len(desired.Rrdatas) != 0 {
+		cs.Additions = append(cs.Additions, desired)
+	}
+}
+
+// indexOf returns the index of the PTR record that refers to the target name
+// in a PTR record set, or -1 if it is not present.
+func indexOf(set clouddnsapi.ResourceRecordSet, target string) int {
+	for i, v := range set.Rrdatas {
+		if strings.EqualFold(v, target) {
+			return i
+		}
+	}
+
+	return -1
+}
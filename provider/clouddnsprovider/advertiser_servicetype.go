@@ -0,0 +1,48 @@
+package clouddnsprovider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/clouddnsprovider/internal/clouddnsapi"
+)
+
+// syncServiceTypePTR adds the instance's service type to the shared PTR record
+// set that enumerates the service types within the domain.
+func (a *advertiser) syncServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *clouddnsapi.Change,
+) error {
+	return a.addToPTRSet(ctx, typeEnumerationName(inst), serviceName(inst), cs)
+}
+
+// deleteServiceTypePTR removes the instance's service type from the shared PTR
+// record set that enumerates the service types within the domain.
+//
+// It must only be called when the instance is the last instance of its service
+// type. This is safe even when other controllers advertise instances of the
+// same type within the zone, because the change also replaces the instance
+// enumeration PTR record set, which fails if any other instance has been added
+// to (or removed from) that set in the meantime.
+func (a *advertiser) deleteServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *clouddnsapi.Change,
+) error {
+	current, ok, err := a.findResourceRecordSet(ctx, typeEnumerationName(inst), "PTR")
+	if !ok || err != nil {
+		return err
+	}
+
+	if index := indexOf(current, serviceName(inst)); index != -1 {
+		removeFromPTRSet(current, index, cs)
+	}
+
+	return nil
+}
+
+func typeEnumerationName(inst provider.ServiceInstance) string {
+	return dnssd.TypeEnumerationDomain(inst.Domain) + "."
+}
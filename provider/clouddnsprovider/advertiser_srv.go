@@ -0,0 +1,33 @@
+package clouddnsprovider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/clouddnsprovider/internal/clouddnsapi"
+)
+
+func (a *advertiser) syncSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *clouddnsapi.Change,
+) error {
+	return a.syncResourceRecordSet(
+		ctx,
+		clouddnsapi.ResourceRecordSet{
+			Name:    instanceName(inst),
+			Type:    "SRV",
+			TTL:     int64(inst.TTL.Seconds()),
+			Rrdatas: convertRecords(provider.NewSRVRecords(inst)...),
+		},
+		cs,
+	)
+}
+
+func (a *advertiser) deleteSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *clouddnsapi.Change,
+) error {
+	return a.deleteResourceRecordSet(ctx, instanceName(inst), "SRV", cs)
+}
@@ -0,0 +1,107 @@
+package clouddnsprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/clouddnsprovider/internal/clouddnsapi"
+	"golang.org/x/exp/slices"
+)
+
+// findSubtypePTRs returns all of the subtype PTR record sets for the instance's
+// service type, regardless of which subtypes the instance provides.
+func (a *advertiser) findSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) ([]clouddnsapi.ResourceRecordSet, error) {
+	parent := "._sub." + serviceName(inst)
+
+	var sets []clouddnsapi.ResourceRecordSet
+
+	err := clouddnsapi.Each(
+		ctx,
+		// The API only supports filtering by an exact name, so we list all
+		// record sets in the zone and filter them ourselves.
+		func(token string) (string, []clouddnsapi.ResourceRecordSet, error) {
+			next, sets, err := a.Client.ListResourceRecordSets(ctx, a.Zone.Name, "", "", token)
+			if err != nil {
+				return "", nil, fmt.Errorf("unable to list record sets: %w", err)
+			}
+			return next, sets, nil
+		},
+		func(set clouddnsapi.ResourceRecordSet) (bool, error) {
+			if set.Type == "PTR" && hasSuffixFold(set.Name, parent) {
+				sets = append(sets, set)
+			}
+			return true, nil
+		},
+	)
+
+	return sets, err
+}
+
+func (a *advertiser) syncSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *clouddnsapi.Change,
+) error {
+	for _, st := range inst.Subtypes {
+		if err := a.addToPTRSet(ctx, subtypeName(inst, st), instanceName(inst), cs); err != nil {
+			return err
+		}
+	}
+
+	// Remove the instance from any subtypes that it no longer provides.
+	current, err := a.findSubtypePTRs(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for _, set := range current {
+		if slices.ContainsFunc(
+			inst.Subtypes,
+			func(st string) bool {
+				return strings.EqualFold(set.Name, subtypeName(inst, st))
+			},
+		) {
+			continue
+		}
+
+		if index := indexOf(set, instanceName(inst)); index != -1 {
+			removeFromPTRSet(set, index, cs)
+		}
+	}
+
+	return nil
+}
+
+func (a *advertiser) deleteSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *clouddnsapi.Change,
+) error {
+	current, err := a.findSubtypePTRs(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for _, set := range current {
+		if index := indexOf(set, instanceName(inst)); index != -1 {
+			removeFromPTRSet(set, index, cs)
+		}
+	}
+
+	return nil
+}
+
+func subtypeName(inst provider.ServiceInstance, subtype string) string {
+	return dnssd.SelectiveInstanceEnumerationDomain(subtype, inst.ServiceType, inst.Domain) + "."
+}
+
+func hasSuffixFold(s, suffix string) bool {
+	return len(s) >= len(suffix) &&
+		strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
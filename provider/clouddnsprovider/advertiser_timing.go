@@ -0,0 +1,62 @@
+package clouddnsprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/clouddnsprovider/internal/clouddnsapi"
+	"github.com/miekg/dns"
+)
+
+// throttleInterval is the amount of time to wait after a request is rejected
+// because a Cloud DNS rate limit or quota was exceeded.
+//
+// The API does not provide any information about when the limit is reset.
+const throttleInterval = 1 * time.Second
+
+func (a *advertiser) ZoneTiming(ctx context.Context) (provider.ZoneTiming, error) {
+	set, ok, err := a.findResourceRecordSet(ctx, a.Zone.DNSName, "SOA")
+	if err != nil {
+		return provider.ZoneTiming{}, fmt.Errorf("unable to find SOA record: %w", err)
+	}
+
+	if !ok || len(set.Rrdatas) == 0 {
+		return provider.ZoneTiming{}, nil
+	}
+
+	rr, err := dns.NewRR(
+		fmt.Sprintf(
+			"%s %d IN SOA %s",
+			set.Name,
+			set.TTL,
+			set.Rrdatas[0],
+		),
+	)
+	if err != nil {
+		return provider.ZoneTiming{}, fmt.Errorf("unable to parse SOA record: %w", err)
+	}
+
+	soa, ok := rr.(*dns.SOA)
+	if !ok {
+		return provider.ZoneTiming{}, nil
+	}
+
+	return provider.ZoneTiming{
+		NegativeTTL: provider.NegativeTTL(soa),
+	}, nil
+}
+
+// rateLimitError returns a provider.RateLimitError that wraps err if err
+// indicates that a Cloud DNS rate limit or quota was exceeded. Otherwise, it
+// returns err unchanged.
+func rateLimitError(err error) error {
+	if clouddnsapi.IsRateLimited(err) {
+		return provider.RateLimitError{
+			Reset: time.Now().Add(throttleInterval),
+			Cause: err,
+		}
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+package clouddnsprovider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/clouddnsprovider/internal/clouddnsapi"
+)
+
+func (a *advertiser) syncTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *clouddnsapi.Change,
+) error {
+	return a.syncResourceRecordSet(
+		ctx,
+		clouddnsapi.ResourceRecordSet{
+			Name:    instanceName(inst),
+			Type:    "TXT",
+			TTL:     int64(inst.TTL.Seconds()),
+			Rrdatas: convertRecords(provider.NewTXTRecords(inst)...),
+		},
+		cs,
+	)
+}
+
+func (a *advertiser) deleteTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *clouddnsapi.Change,
+) error {
+	return a.deleteResourceRecordSet(ctx, instanceName(inst), "TXT", cs)
+}
@@ -0,0 +1,3 @@
+// Package clouddnsprovider provides a driver implementation that advertises
+// DNS-SD service instances on domain names hosted by Google Cloud DNS.
+package clouddnsprovider
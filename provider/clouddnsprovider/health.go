@@ -0,0 +1,16 @@
+package clouddnsprovider
+
+import (
+	"context"
+	"fmt"
+)
+
+// CheckHealth returns an error if the provider is unable to communicate or
+// authenticate with Cloud DNS.
+func (p *Provider) CheckHealth(ctx context.Context) error {
+	if err := p.client().GetProject(ctx); err != nil {
+		return fmt.Errorf("unable to get project: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package clouddnsapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the base URL of the production Cloud DNS API.
+const DefaultBaseURL = "https://dns.googleapis.com/dns/v1"
+
+// Client is a client for the Cloud DNS v1 API.
+type Client struct {
+	// Project is the ID of the Google Cloud project that owns the managed
+	// zones.
+	Project string
+
+	// BaseURL is the base URL of the API. If it is empty, DefaultBaseURL is
+	// used.
+	BaseURL string
+
+	// HTTPClient is the client used to make HTTP requests. It is expected to
+	// add the appropriate OAuth 2.0 credentials to each request. If it is nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// do performs an API request and unmarshals the response into out, which may
+// be nil.
+func (c *Client) do(
+	ctx context.Context,
+	method, path string,
+	query url.Values,
+	in, out any,
+) error {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+
+	u := strings.TrimSuffix(base, "/") + "/projects/" + url.PathEscape(c.Project) + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	res, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return newError(res)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// Each calls fn for each page of results returned by list, until there are
+// no more pages or fn returns false.
+//
+// list is called with the token of the page to fetch, which is empty for the
+// first page. It returns the token of the next page, which is empty if there
+// are no more pages.
+func Each[T any](
+	ctx context.Context,
+	list func(pageToken string) (string, []T, error),
+	fn func(T) (bool, error),
+) error {
+	token := ""
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		next, items, err := list(token)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range items {
+			ok, err := fn(v)
+			if !ok || err != nil {
+				return err
+			}
+		}
+
+		if next == "" {
+			return nil
+		}
+
+		token = next
+	}
+}
+
+// listQuery returns the query parameters used to request the page of a list
+// with the given token.
+func listQuery(pageToken string) url.Values {
+	q := url.Values{}
+	if pageToken != "" {
+		q.Set("pageToken", pageToken)
+	}
+	return q
+}
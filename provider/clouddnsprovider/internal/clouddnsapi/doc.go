@@ -0,0 +1,6 @@
+// Package clouddnsapi is a minimal client for the Google Cloud DNS v1 REST API.
+//
+// It implements only the managed zone, resource record set and change
+// operations used by the provider. Authentication is the responsibility of the
+// HTTP client.
+package clouddnsapi
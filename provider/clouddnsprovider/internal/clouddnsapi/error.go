@@ -0,0 +1,86 @@
+package clouddnsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error is an unsuccessful response from the API.
+type Error struct {
+	StatusCode int
+	Message    string
+
+	// Reason is a short machine-readable description of the error, such as
+	// "notFound" or "conditionNotMet".
+	Reason string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("cloud DNS API returned HTTP %d", e.StatusCode)
+	}
+
+	if e.Reason == "" {
+		return fmt.Sprintf("cloud DNS API returned HTTP %d: %s", e.StatusCode, e.Message)
+	}
+
+	return fmt.Sprintf("cloud DNS API returned HTTP %d: %s (%s)", e.StatusCode, e.Message, e.Reason)
+}
+
+// HTTPStatusCode returns the HTTP status code of the response.
+func (e *Error) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// newError returns an error that describes an unsuccessful response.
+func newError(res *http.Response) error {
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+
+	e := &Error{StatusCode: res.StatusCode}
+
+	if json.NewDecoder(res.Body).Decode(&body) == nil {
+		e.Message = body.Error.Message
+		if len(body.Error.Errors) != 0 {
+			e.Reason = body.Error.Errors[0].Reason
+		}
+	}
+
+	return e
+}
+
+// IsNotFound returns true if err is an error response from the API that
+// indicates that the requested resource does not exist.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsRateLimited returns true if err is an error response from the API that
+// indicates that a rate limit or quota has been exceeded.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsConflict returns true if err is an error response from the API that
+// indicates that a change could not be applied because the records it
+// deletes or adds have been modified by another client.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict) ||
+		hasStatus(err, http.StatusPreconditionFailed)
+}
+
+func hasStatus(err error, status int) bool {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.StatusCode == status
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package clouddnsapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// ResourceRecordSet is the set of records with the same name and type.
+type ResourceRecordSet struct {
+	// Name is the fully-qualified name of the records, with a trailing dot.
+	Name string `json:"name"`
+
+	// Type is the record type, such as "PTR".
+	Type string `json:"type"`
+
+	// TTL is the time-to-live of the records, in seconds.
+	TTL int64 `json:"ttl"`
+
+	// Rrdatas contains the value of each record in presentation format.
+	Rrdatas []string `json:"rrdatas"`
+}
+
+// Equal returns true if s contains the same records as x, in any order.
+func (s ResourceRecordSet) Equal(x ResourceRecordSet) bool {
+	if !strings.EqualFold(s.Name, x.Name) || s.Type != x.Type || s.TTL != x.TTL {
+		return false
+	}
+
+	if len(s.Rrdatas) != len(x.Rrdatas) {
+		return false
+	}
+
+	for _, v := range s.Rrdatas {
+		if !slices.Contains(x.Rrdatas, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ListResourceRecordSets returns the page of record sets within a managed
+// zone with the given fully-qualified name and type.
+//
+// If name is empty, all record sets in the zone are listed. The type filter
+// is only applied if a name is specified.
+func (c *Client) ListResourceRecordSets(
+	ctx context.Context,
+	zone, name, recordType string,
+	pageToken string,
+) (next string, sets []ResourceRecordSet, err error) {
+	q := listQuery(pageToken)
+	if name != "" {
+		q.Set("name", name)
+		if recordType != "" {
+			q.Set("type", recordType)
+		}
+	}
+
+	var out struct {
+		Rrsets        []ResourceRecordSet `json:"rrsets"`
+		NextPageToken string              `json:"nextPageToken"`
+	}
+
+	err = c.do(ctx, http.MethodGet, zonePath(zone)+"/rrsets", q, nil, &out)
+	return out.NextPageToken, out.Rrsets, err
+}
+
+// Change is an atomic set of record set deletions and additions.
+//
+// Each deletion must exactly match an existing record set, otherwise the
+// entire change is rejected. This makes it possible to update a record set
+// that is shared with other clients without overwriting their modifications.
+type Change struct {
+	ID        string              `json:"id,omitempty"`
+	Status    string              `json:"status,omitempty"`
+	Additions []ResourceRecordSet `json:"additions,omitempty"`
+	Deletions []ResourceRecordSet `json:"deletions,omitempty"`
+}
+
+// ChangeStatusDone is the status of a change that has been applied to all of
+// the zone's authoritative name servers.
+const ChangeStatusDone = "done"
+
+// CreateChange atomically applies a change to a managed zone.
+func (c *Client) CreateChange(
+	ctx context.Context,
+	zone string,
+	ch Change,
+) (Change, error) {
+	var out Change
+	err := c.do(ctx, http.MethodPost, zonePath(zone)+"/changes", nil, ch, &out)
+	return out, err
+}
+
+// GetChange returns the change with the given ID.
+func (c *Client) GetChange(
+	ctx context.Context,
+	zone, id string,
+) (Change, error) {
+	var out Change
+	err := c.do(ctx, http.MethodGet, zonePath(zone)+"/changes/"+url.PathEscape(id), nil, nil, &out)
+	return out, err
+}
@@ -0,0 +1,56 @@
+package clouddnsapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ManagedZone is a DNS zone hosted by Cloud DNS.
+type ManagedZone struct {
+	// Name is the user-assigned name of the zone, which identifies it within
+	// the project.
+	Name string `json:"name"`
+
+	// DNSName is the fully-qualified domain name of the zone, with a trailing
+	// dot.
+	DNSName string `json:"dnsName"`
+
+	// Visibility is either "public" or "private".
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// ListManagedZones returns the page of managed zones with the given DNS name.
+func (c *Client) ListManagedZones(
+	ctx context.Context,
+	dnsName string,
+	pageToken string,
+) (next string, zones []ManagedZone, err error) {
+	q := listQuery(pageToken)
+	q.Set("dnsName", dnsName)
+
+	var out struct {
+		ManagedZones  []ManagedZone `json:"managedZones"`
+		NextPageToken string        `json:"nextPageToken"`
+	}
+
+	err = c.do(ctx, http.MethodGet, "/managedZones", q, nil, &out)
+	return out.NextPageToken, out.ManagedZones, err
+}
+
+// GetManagedZone returns the managed zone with the given name.
+func (c *Client) GetManagedZone(ctx context.Context, name string) (ManagedZone, error) {
+	var z ManagedZone
+	err := c.do(ctx, http.MethodGet, zonePath(name), nil, nil, &z)
+	return z, err
+}
+
+// GetProject returns an error if the project can not be read, for example
+// because the client's credentials are invalid.
+func (c *Client) GetProject(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "", nil, nil, nil)
+}
+
+func zonePath(name string) string {
+	return "/managedZones/" + url.PathEscape(name)
+}
@@ -0,0 +1,166 @@
+package clouddnsprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/clouddnsprovider/internal/clouddnsapi"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+)
+
+// Provider is an implementation of provider.Provider that advertises DNS-SD
+// services on domains hosted by Google Cloud DNS.
+type Provider struct {
+	// Project is the ID of the Google Cloud project that owns the managed
+	// zones.
+	Project string
+
+	// HTTPClient is the client used to make requests to the API. It must add
+	// OAuth 2.0 credentials with the "ndev.clouddns.readwrite" scope to each
+	// request, such as a client returned by google.DefaultClient().
+	HTTPClient *http.Client
+
+	// APIURL is the base URL of the Cloud DNS v1 API. If it is empty, the
+	// production API is used.
+	APIURL string
+
+	// Name distinguishes this provider from other Cloud DNS providers that use
+	// different credentials. It may be empty if there is only one such
+	// provider.
+	Name string
+
+	Logger logr.Logger
+}
+
+// ID returns a short unique identifier for the provider.
+func (p *Provider) ID() string {
+	return provider.NamedID("clouddns."+p.Project, p.Name)
+}
+
+// Describe returns a human-readable description of the provider.
+func (p *Provider) Describe() string {
+	return provider.NamedDescription(
+		fmt.Sprintf("Google Cloud DNS (%s)", p.Project),
+		p.Name,
+	)
+}
+
+// AdvertiserByID returns the Advertiser with the given ID.
+func (p *Provider) AdvertiserByID(
+	ctx context.Context,
+	id map[string]any,
+) (provider.Advertiser, error) {
+	zoneName, err := unmarshalAdvertiserID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	client := p.client()
+
+	zone, err := client.GetManagedZone(ctx, zoneName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get managed zone %q: %w", zoneName, err)
+	}
+
+	return p.newAdvertiser(client, zone), nil
+}
+
+// AdvertiserByDomain returns the Advertiser used to advertise services on the
+// given domain.
+//
+// If the project contains both public and private managed zones for the
+// domain, the public zone is used.
+//
+// ok is false if this provider does not manage the given domain.
+func (p *Provider) AdvertiserByDomain(
+	ctx context.Context,
+	domain string,
+) (provider.Advertiser, bool, error) {
+	client := p.client()
+	dnsName := dns.Fqdn(domain)
+
+	var (
+		zone clouddnsapi.ManagedZone
+		ok   bool
+	)
+
+	if err := clouddnsapi.Each(
+		ctx,
+		func(token string) (string, []clouddnsapi.ManagedZone, error) {
+			next, zones, err := client.ListManagedZones(ctx, dnsName, token)
+			if err != nil {
+				return "", nil, fmt.Errorf("unable to list managed zones: %w", err)
+			}
+			return next, zones, nil
+		},
+		func(z clouddnsapi.ManagedZone) (bool, error) {
+			if !strings.EqualFold(z.DNSName, dnsName) {
+				return true, nil
+			}
+
+			if !ok || z.Visibility != "private" {
+				zone = z
+				ok = true
+			}
+
+			return zone.Visibility == "private", nil
+		},
+	); err != nil {
+		return nil, false, err
+	}
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	return p.newAdvertiser(client, zone), true, nil
+}
+
+// client returns a new API client that uses the provider's configuration.
+func (p *Provider) client() *clouddnsapi.Client {
+	return &clouddnsapi.Client{
+		Project:    p.Project,
+		BaseURL:    p.APIURL,
+		HTTPClient: p.HTTPClient,
+	}
+}
+
+func (p *Provider) newAdvertiser(
+	client *clouddnsapi.Client,
+	zone clouddnsapi.ManagedZone,
+) *advertiser {
+	return &advertiser{
+		client,
+		zone,
+		p.Logger,
+	}
+}
+
+// marshalAdvertiserID returns the ID of the advertiser for the given zone.
+//
+// The project is implied by the provider, and so is not included.
+func marshalAdvertiserID(z clouddnsapi.ManagedZone) map[string]any {
+	return map[string]any{
+		"managedZone": z.Name,
+	}
+}
+
+// unmarshalAdvertiserID parses an advertiser ID into its constituent parts.
+func unmarshalAdvertiserID(id map[string]any) (zoneName string, err error) {
+	zoneNameAny, ok := id["managedZone"]
+	if !ok {
+		return "", errors.New("invalid advertiser ID: missing managedZone key")
+	}
+
+	zoneName, ok = zoneNameAny.(string)
+	if !ok || zoneName == "" {
+		return "", errors.New("invalid advertiser ID: managedZone must be a non-empty string")
+	}
+
+	return zoneName, nil
+}
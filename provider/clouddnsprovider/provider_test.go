@@ -0,0 +1,264 @@
+package clouddnsprovider_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	. "github.com/dogmatiq/proclaim/provider/clouddnsprovider"
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2"
+)
+
+const (
+	project = "proclaim-test"
+	domain  = "proclaim-test.example.org"
+	token   = "<token>"
+)
+
+// newHTTPClient returns an HTTP client that authenticates requests using the
+// given OAuth 2.0 access token.
+func newHTTPClient(ctx context.Context, token string) *http.Client {
+	return oauth2.NewClient(
+		ctx,
+		oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: token},
+		),
+	)
+}
+
+var _ = Describe("type Provider", func() {
+	providertest.DeclareTestSuite(
+		func(ctx context.Context) providertest.TestContext {
+			srv := newServer(project, domain, token)
+			apiURL, port := srv.start()
+
+			return providertest.TestContext{
+				Provider: &Provider{
+					Project:    project,
+					HTTPClient: newHTTPClient(ctx, token),
+					APIURL:     apiURL,
+					Logger:     logr.Discard(),
+				},
+				Domain: domain,
+				NameServers: func(ctx context.Context) ([]string, error) {
+					return []string{"127.0.0.1"}, nil
+				},
+				NameServerPort: port,
+				DeleteRecords: func(ctx context.Context) error {
+					srv.DeleteRecords()
+					return nil
+				},
+			}
+		},
+	)
+
+	var (
+		ctx  context.Context
+		srv  *server
+		port string
+		p    *Provider
+		inst provider.ServiceInstance
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var apiURL string
+		srv = newServer(project, domain, token)
+		apiURL, port = srv.start()
+
+		p = &Provider{
+			Project:    project,
+			HTTPClient: newHTTPClient(ctx, token),
+			APIURL:     apiURL,
+			Logger:     logr.Discard(),
+		}
+
+		inst = provider.ServiceInstance{
+			Name:        "instance",
+			ServiceType: "_proclaim._tcp",
+			Domain:      domain,
+			Targets: []provider.Target{
+				{Host: "host.example.com", Port: 443},
+			},
+			TTL: 5 * time.Second,
+		}
+	})
+
+	Describe("func AdvertiserByDomain()", func() {
+		It("prefers the public managed zone", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(a.ID()).To(Equal(map[string]any{"managedZone": "public-zone"}))
+		})
+	})
+
+	Describe("func AdvertiserByID()", func() {
+		It("returns an advertiser for the managed zone with the given name", func() {
+			a, err := p.AdvertiserByID(ctx, map[string]any{"managedZone": "public-zone"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(a.ID()).To(Equal(map[string]any{"managedZone": "public-zone"}))
+		})
+
+		It("returns an error if the managed zone does not exist", func() {
+			_, err := p.AdvertiserByID(ctx, map[string]any{"managedZone": "unknown-zone"})
+			Expect(err).To(MatchError(ContainSubstring("unable to get managed zone")))
+		})
+
+		It("returns an error if the ID is invalid", func() {
+			_, err := p.AdvertiserByID(ctx, map[string]any{})
+			Expect(err).To(MatchError("invalid advertiser ID: missing managedZone key"))
+		})
+	})
+
+	Describe("func IsPropagated()", func() {
+		It("returns true once the change is done", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			cs, err := a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cs.PendingChanges).To(HaveLen(1))
+
+			pa, ok := a.(provider.PropagationAdvertiser)
+			Expect(ok).To(BeTrue())
+
+			ok, err = pa.IsPropagated(ctx, cs.PendingChanges)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			ok, err = pa.IsPropagated(ctx, cs.PendingChanges)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+
+		It("returns true if the change does not exist", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			ok, err = a.(provider.PropagationAdvertiser).IsPropagated(ctx, []string{"999"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+	})
+
+	When("there are more record sets than fit on a single page", func() {
+		It("finds record sets on subsequent pages", func() {
+			srv.MaxResults = 1
+
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			inst.Subtypes = []string{"_a", "_b"}
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			inst.Subtypes = []string{"_a"}
+
+			cs, err := a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cs.PTR).To(Equal(provider.Deleted))
+
+			_, err = a.Unadvertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			srv.m.Lock()
+			defer srv.m.Unlock()
+			Expect(srv.sets).To(HaveLen(2)) // SOA and NS
+		})
+	})
+
+	When("another client modifies the instance enumeration records", func() {
+		It("does not remove the service type enumeration record", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// Simulate another controller advertising a second instance of the
+			// same service type after the records have been queried, but
+			// before the change is applied.
+			srv.BeforeChange = func() {
+				srv.Insert(rrset{
+					Name: "_proclaim._tcp." + domain + ".",
+					Type: "PTR",
+					TTL:  30,
+					Rrdatas: []string{
+						"instance._proclaim._tcp." + domain + ".",
+						"other._proclaim._tcp." + domain + ".",
+					},
+				})
+			}
+
+			_, err = a.Unadvertise(ctx, inst)
+			Expect(err).To(MatchError(ContainSubstring("modified concurrently")))
+
+			srv.BeforeChange = nil
+
+			_, err = a.Unadvertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			res := &dnssd.UnicastResolver{
+				Config: &dns.ClientConfig{
+					Servers:  []string{"127.0.0.1"},
+					Port:     port,
+					Ndots:    1,
+					Timeout:  1,
+					Attempts: 3,
+				},
+			}
+
+			serviceTypes, err := res.EnumerateServiceTypes(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(serviceTypes).To(ConsistOf("_proclaim._tcp"))
+		})
+	})
+
+	When("the credentials are invalid", func() {
+		BeforeEach(func() {
+			p.HTTPClient = newHTTPClient(ctx, "<invalid>")
+		})
+
+		It("returns an error when finding an advertiser", func() {
+			_, _, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).To(MatchError(ContainSubstring("HTTP 401")))
+		})
+
+		It("fails the health check", func() {
+			err := p.CheckHealth(ctx)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("func ID()", func() {
+		It("includes the project and the provider's name", func() {
+			a := &Provider{Project: project, Name: "a"}
+			b := &Provider{Project: project, Name: "b"}
+
+			Expect(a.ID()).To(Equal("clouddns.proclaim-test:a"))
+			Expect(b.ID()).To(Equal("clouddns.proclaim-test:b"))
+			Expect(a.Describe()).To(Equal("Google Cloud DNS (proclaim-test) [a]"))
+		})
+
+		It("does not include an empty name", func() {
+			p := &Provider{Project: project}
+
+			Expect(p.ID()).To(Equal("clouddns.proclaim-test"))
+			Expect(p.Describe()).To(Equal("Google Cloud DNS (proclaim-test)"))
+		})
+	})
+})
@@ -0,0 +1,386 @@
+package clouddnsprovider_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	"github.com/miekg/dns"
+	"github.com/onsi/ginkgo/v2"
+	"golang.org/x/exp/slices"
+)
+
+// server is a stand-in for Google Cloud DNS. It serves the subset of the v1
+// REST API used by the provider over HTTP, and answers DNS queries for the
+// records in its public zone so that the advertised services can be
+// discovered.
+type server struct {
+	Project string
+	Zone    string
+	Token   string
+
+	// MaxResults is the maximum number of results in each page of a list
+	// response.
+	MaxResults int
+
+	// BeforeChange, if non-nil, is called before each change is applied. It
+	// can be used to simulate changes made by other clients.
+	BeforeChange func()
+
+	m       sync.Mutex
+	sets    []rrset
+	changes map[string]*change
+}
+
+// rrset is a resource record set as represented by the Cloud DNS API.
+type rrset struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     int64    `json:"ttl"`
+	Rrdatas []string `json:"rrdatas"`
+}
+
+type change struct {
+	ID        string  `json:"id"`
+	Status    string  `json:"status"`
+	Additions []rrset `json:"additions,omitempty"`
+	Deletions []rrset `json:"deletions,omitempty"`
+}
+
+const (
+	publicZoneName  = "public-zone"
+	privateZoneName = "private-zone"
+)
+
+// newServer returns a new server for a project that contains a public zone
+// and a private zone with the same DNS name.
+func newServer(project, zone, token string) *server {
+	s := &server{
+		Project:    project,
+		Zone:       dns.Fqdn(zone),
+		Token:      token,
+		MaxResults: 100,
+	}
+
+	s.DeleteRecords()
+
+	return s
+}
+
+// start starts the HTTP and DNS servers on random ports on the loopback
+// interface. It returns the base URL of the API and the DNS port. The servers
+// are stopped when the current test ends.
+func (s *server) start() (apiURL, dnsPort string) {
+	api := httptest.NewServer(http.HandlerFunc(s.ServeHTTP))
+	ginkgo.DeferCleanup(api.Close)
+
+	port := providertest.StartDNSServer(
+		&providertest.Responder{
+			Zone:    s.Zone,
+			Records: s.dnsRecords,
+		},
+		nil,
+	)
+
+	return api.URL + "/dns/v1", port
+}
+
+// DeleteRecords removes all record sets from the public zone other than the
+// SOA and NS record sets at the zone apex.
+func (s *server) DeleteRecords() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.sets = []rrset{
+		{
+			Name: s.Zone,
+			Type: "SOA",
+			TTL:  21600,
+			Rrdatas: []string{
+				"ns-cloud-a1.googledomains.com. cloud-dns-hostmaster.google.com. 1 21600 3600 259200 5",
+			},
+		},
+		{
+			Name:    s.Zone,
+			Type:    "NS",
+			TTL:     21600,
+			Rrdatas: []string{"ns-cloud-a1.googledomains.com."},
+		},
+	}
+}
+
+// Insert adds a record set to the public zone, or replaces an existing set
+// with the same name and type.
+func (s *server) Insert(set rrset) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if i := s.indexOf(set.Name, set.Type); i != -1 {
+		s.sets[i] = set
+	} else {
+		s.sets = append(s.sets, set)
+	}
+}
+
+func (s *server) indexOf(name, recordType string) int {
+	for i, set := range s.sets {
+		if strings.EqualFold(set.Name, name) && set.Type == recordType {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "authError", "Request had invalid authentication credentials.")
+		return
+	}
+
+	projectPath := "/dns/v1/projects/" + s.Project
+	zonesPath := projectPath + "/managedZones"
+	zonePath := zonesPath + "/" + publicZoneName
+	changesPath := zonePath + "/changes"
+
+	if r.Method == http.MethodPost && r.URL.Path == changesPath && s.BeforeChange != nil {
+		s.BeforeChange()
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == projectPath:
+		writeJSON(w, map[string]any{"id": s.Project, "kind": "dns#project"})
+
+	case r.Method == http.MethodGet && r.URL.Path == zonesPath:
+		var zones []any
+		if name := r.URL.Query().Get("dnsName"); name == "" || strings.EqualFold(name, s.Zone) {
+			// The private zone is listed first to verify that the provider
+			// prefers the public zone.
+			zones = append(zones, s.zone(privateZoneName), s.zone(publicZoneName))
+		}
+		s.writePage(w, r, "managedZones", zones)
+
+	case r.Method == http.MethodGet && r.URL.Path == zonePath:
+		writeJSON(w, s.zone(publicZoneName))
+
+	case r.Method == http.MethodGet && r.URL.Path == zonePath+"/rrsets":
+		q := r.URL.Query()
+		var sets []any
+		for _, set := range s.sets {
+			if n := q.Get("name"); n != "" && !strings.EqualFold(n, set.Name) {
+				continue
+			}
+			if t := q.Get("type"); t != "" && t != set.Type {
+				continue
+			}
+			sets = append(sets, set)
+		}
+		s.writePage(w, r, "rrsets", sets)
+
+	case r.Method == http.MethodPost && r.URL.Path == changesPath:
+		var ch change
+		if err := json.NewDecoder(r.Body).Decode(&ch); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid", "Invalid JSON payload received.")
+			return
+		}
+
+		if status, reason, message := s.apply(ch); status != http.StatusOK {
+			writeError(w, status, reason, message)
+			return
+		}
+
+		if s.changes == nil {
+			s.changes = map[string]*change{}
+		}
+
+		ch.ID = strconv.Itoa(len(s.changes) + 1)
+		ch.Status = "pending"
+		s.changes[ch.ID] = &ch
+
+		writeJSON(w, ch)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, changesPath+"/"):
+		ch, ok := s.changes[strings.TrimPrefix(r.URL.Path, changesPath+"/")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "notFound", "The 'parameters.changeId' resource named 'unknown' does not exist.")
+			return
+		}
+
+		// Changes are reported as pending the first time they are returned,
+		// and done thereafter.
+		writeJSON(w, *ch)
+		ch.Status = "done"
+
+	case strings.HasPrefix(r.URL.Path, zonesPath+"/"):
+		writeError(w, http.StatusNotFound, "notFound", "The 'parameters.managedZone' resource does not exist.")
+
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "Not Found")
+	}
+}
+
+// apply atomically applies a change to the public zone. Deletions are applied
+// before additions, and each deletion must match an existing record set
+// exactly.
+func (s *server) apply(ch change) (status int, reason, message string) {
+	sets := slices.Clone(s.sets)
+
+	for _, del := range ch.Deletions {
+		i := -1
+		for j, set := range sets {
+			if strings.EqualFold(set.Name, del.Name) && set.Type == del.Type {
+				i = j
+			}
+		}
+
+		if i == -1 {
+			return http.StatusNotFound, "notFound", fmt.Sprintf("The 'entity.change.deletions[%s][%s]' resource does not exist.", del.Name, del.Type)
+		}
+
+		if !sameRecords(sets[i], del) {
+			return http.StatusPreconditionFailed, "conditionNotMet", fmt.Sprintf("The resource 'entity.change.deletions[%s][%s]' does not match the existing record set.", del.Name, del.Type)
+		}
+
+		sets = slices.Delete(sets, i, i+1)
+	}
+
+	for _, add := range ch.Additions {
+		if !dns.IsSubDomain(s.Zone, add.Name) {
+			return http.StatusBadRequest, "invalid", fmt.Sprintf("The resource 'entity.change.additions[%s]' is not in the zone.", add.Name)
+		}
+
+		for _, set := range sets {
+			if strings.EqualFold(set.Name, add.Name) && set.Type == add.Type {
+				return http.StatusConflict, "alreadyExists", fmt.Sprintf("The resource 'entity.change.additions[%s][%s]' already exists.", add.Name, add.Type)
+			}
+		}
+
+		for _, rr := range toRRs(add) {
+			if rr == nil {
+				return http.StatusBadRequest, "invalid", fmt.Sprintf("The resource 'entity.change.additions[%s][%s]' has invalid data.", add.Name, add.Type)
+			}
+		}
+
+		sets = append(sets, add)
+	}
+
+	s.sets = sets
+
+	return http.StatusOK, "", ""
+}
+
+func sameRecords(a, b rrset) bool {
+	if a.TTL != b.TTL || len(a.Rrdatas) != len(b.Rrdatas) {
+		return false
+	}
+
+	for _, v := range a.Rrdatas {
+		if !slices.Contains(b.Rrdatas, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *server) zone(name string) map[string]any {
+	visibility := "public"
+	if name == privateZoneName {
+		visibility = "private"
+	}
+
+	return map[string]any{
+		"kind":       "dns#managedZone",
+		"name":       name,
+		"dnsName":    s.Zone,
+		"visibility": visibility,
+	}
+}
+
+// writePage writes the requested page of a list of items.
+func (s *server) writePage(w http.ResponseWriter, r *http.Request, key string, items []any) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+
+	end := offset + s.MaxResults
+	if end > len(items) {
+		end = len(items)
+	}
+
+	res := map[string]any{
+		key: append([]any{}, items[offset:end]...),
+	}
+
+	if end < len(items) {
+		res["nextPageToken"] = strconv.Itoa(end)
+	}
+
+	writeJSON(w, res)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, reason, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    status,
+			"message": message,
+			"errors": []any{
+				map[string]any{
+					"domain":  "global",
+					"reason":  reason,
+					"message": message,
+				},
+			},
+		},
+	})
+}
+
+// toRRs converts a record set to its DNS representation. An element is nil
+// if the corresponding record data is invalid.
+func toRRs(set rrset) []dns.RR {
+	var records []dns.RR
+
+	for _, v := range set.Rrdatas {
+		rr, err := dns.NewRR(
+			fmt.Sprintf(
+				"%s %d IN %s %s",
+				set.Name,
+				set.TTL,
+				set.Type,
+				v,
+			),
+		)
+		if err != nil {
+			rr = nil
+		}
+		records = append(records, rr)
+	}
+
+	return records
+}
+
+// dnsRecords returns the DNS representation of the record sets in the public
+// zone.
+func (s *server) dnsRecords() []dns.RR {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var records []dns.RR
+	for _, set := range s.sets {
+		records = append(records, toRRs(set)...)
+	}
+
+	return records
+}
@@ -0,0 +1,175 @@
+package cloudflareprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/cloudflareprovider/internal/cloudflareapi"
+	"github.com/go-logr/logr"
+)
+
+type advertiser struct {
+	Client *cloudflareapi.Client
+	Zone   cloudflareapi.Zone
+	Logger logr.Logger
+}
+
+func (a *advertiser) ID() map[string]any {
+	return marshalAdvertiserID(a.Zone)
+}
+
+func (a *advertiser) Advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs := &changeSet{}
+
+	if err := a.syncServiceTypePTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncPTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncSRV(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncTXT(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) Unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs := &changeSet{}
+
+	if err := a.deletePTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteServiceTypePTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteSRV(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteTXT(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) apply(
+	ctx context.Context,
+	cs *changeSet,
+) (provider.ChangeSet, error) {
+	var result provider.ChangeSet
+
+	for _, rec := range cs.deletes {
+		if err := a.Client.DeleteRecord(ctx, a.Zone.ID, rec.ID); err != nil {
+			return provider.ChangeSet{}, fmt.Errorf("unable to delete %s record: %w", rec.Type, err)
+		}
+
+		switch rec.Type {
+		case "PTR":
+			result.PTR |= provider.Deleted
+		case "SRV":
+			result.SRV |= provider.Deleted
+		case "TXT":
+			result.TXT |= provider.Deleted
+		}
+
+		a.Logger.Info(
+			"DELETE record",
+			"type", rec.Type,
+			"name", rec.Name,
+			"content", rec.Value(),
+			"ttl", rec.TTL,
+		)
+	}
+
+	for _, up := range cs.updates {
+		if _, err := a.Client.UpdateRecord(ctx, a.Zone.ID, up.Before.ID, up.After); err != nil {
+			return provider.ChangeSet{}, fmt.Errorf("unable to update %s record: %w", up.Before.Type, err)
+		}
+
+		switch up.Before.Type {
+		case "PTR":
+			result.PTR |= provider.Updated
+		case "SRV":
+			result.SRV |= provider.Updated
+		case "TXT":
+			result.TXT |= provider.Updated
+		}
+
+		a.Logger.Info(
+			"UPDATE record",
+			"type", up.Before.Type,
+			"name", up.Before.Name,
+			"content_before", up.Before.Value(),
+			"ttl_before", up.Before.TTL,
+			"content_after", up.After.Value(),
+			"ttl_after", up.After.TTL,
+		)
+	}
+
+	for _, rec := range cs.creates {
+		if _, err := a.Client.CreateRecord(ctx, a.Zone.ID, rec); err != nil {
+			return provider.ChangeSet{}, fmt.Errorf("unable to create %s record: %w", rec.Type, err)
+		}
+
+		switch rec.Type {
+		case "PTR":
+			result.PTR |= provider.Created
+		case "SRV":
+			result.SRV |= provider.Created
+		case "TXT":
+			result.TXT |= provider.Created
+		}
+
+		a.Logger.Info(
+			"CREATE record",
+			"type", rec.Type,
+			"name", rec.Name,
+			"content", rec.Value(),
+			"ttl", rec.TTL,
+		)
+	}
+
+	return result, nil
+}
+
+// minTTL is the minimum TTL that Cloudflare accepts for DNS records, other than
+// the special value of 1, which means "automatic".
+const minTTL = 60 * time.Second
+
+// ttl returns the TTL to use for a record that should have a TTL of d, in
+// seconds.
+//
+// TTLs below minTTL are rejected by the Cloudflare API, so they are raised to
+// the minimum.
+func ttl(d time.Duration) int {
+	if d < minTTL {
+		d = minTTL
+	}
+	return int(d.Seconds())
+}
@@ -0,0 +1,79 @@
+package cloudflareprovider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/cloudflareprovider/internal/cloudflareapi"
+	"golang.org/x/exp/slices"
+)
+
+func (a *advertiser) AdvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs := &changeSet{}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		name := strings.TrimSuffix(set.Name, ".")
+
+		current, err := a.findPTRs(ctx, name)
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		var desired []cloudflareapi.Record
+		for _, rr := range set.Records {
+			desired = append(
+				desired,
+				cloudflareapi.Record{
+					Type:    "PTR",
+					Name:    name,
+					Content: strings.TrimSuffix(rr.Ptr, "."),
+					TTL:     ttl(time.Duration(rr.Hdr.Ttl) * time.Second),
+				},
+			)
+		}
+
+	next:
+		for _, c := range current {
+			for i, d := range desired {
+				if strings.EqualFold(c.Content, d.Content) {
+					desired = slices.Delete(desired, i, i+1)
+					cs.Update(c, d)
+					continue next
+				}
+			}
+
+			cs.Delete(c)
+		}
+
+		for _, rec := range desired {
+			cs.Create(rec)
+		}
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) UnadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs := &changeSet{}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		current, err := a.findPTRs(ctx, strings.TrimSuffix(set.Name, "."))
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		for _, c := range current {
+			cs.Delete(c)
+		}
+	}
+
+	return a.apply(ctx, cs)
+}
@@ -0,0 +1,113 @@
+package cloudflareprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/cloudflareprovider/internal/cloudflareapi"
+)
+
+// findPTRs returns the PTR records with the given fully-qualified name.
+func (a *advertiser) findPTRs(
+	ctx context.Context,
+	name string,
+) ([]cloudflareapi.Record, error) {
+	return cloudflareapi.All(
+		ctx,
+		a.listRecords(ctx, "PTR", name),
+	)
+}
+
+// findPTR returns the PTR record with the given fully-qualified name that
+// refers to target.
+func (a *advertiser) findPTR(
+	ctx context.Context,
+	name, target string,
+) (cloudflareapi.Record, bool, error) {
+	return cloudflareapi.First(
+		ctx,
+		a.listRecords(ctx, "PTR", name),
+		func(candidate cloudflareapi.Record) bool {
+			return strings.EqualFold(candidate.Content, target)
+		},
+	)
+}
+
+// listRecords returns a function that lists the records of the given type and
+// fully-qualified name, suitable for use with the cloudflareapi iteration
+// functions.
+//
+// If name is empty, records of the given type are listed regardless of their
+// name.
+func (a *advertiser) listRecords(
+	ctx context.Context,
+	recordType, name string,
+) func(int) (*cloudflareapi.ResultInfo, []cloudflareapi.Record, error) {
+	return func(page int) (*cloudflareapi.ResultInfo, []cloudflareapi.Record, error) {
+		info, records, err := a.Client.ListRecords(
+			ctx,
+			a.Zone.ID,
+			cloudflareapi.RecordFilter{
+				Type: recordType,
+				Name: name,
+			},
+			page,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to list %s records: %w", recordType, err)
+		}
+
+		return info, records, nil
+	}
+}
+
+func (a *advertiser) syncPTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	name := dnssd.InstanceEnumerationDomain(inst.ServiceType, a.Zone.Name)
+	target := dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, a.Zone.Name)
+
+	current, ok, err := a.findPTR(ctx, name, target)
+	if err != nil {
+		return err
+	}
+
+	desired := cloudflareapi.Record{
+		Type:    "PTR",
+		Name:    name,
+		Content: target,
+		TTL:     ttl(inst.TTL),
+	}
+
+	if ok {
+		cs.Update(current, desired)
+	} else {
+		cs.Create(desired)
+	}
+
+	return nil
+}
+
+func (a *advertiser) deletePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, ok, err := a.findPTR(
+		ctx,
+		dnssd.InstanceEnumerationDomain(inst.ServiceType, a.Zone.Name),
+		dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, a.Zone.Name),
+	)
+	if !ok || err != nil {
+		return err
+	}
+
+	cs.Delete(current)
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package cloudflareprovider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/cloudflareprovider/internal/cloudflareapi"
+)
+
+func (a *advertiser) findServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (cloudflareapi.Record, bool, error) {
+	return a.findPTR(
+		ctx,
+		dnssd.TypeEnumerationDomain(a.Zone.Name),
+		dnssd.InstanceEnumerationDomain(inst.ServiceType, a.Zone.Name),
+	)
+}
+
+// hasOtherInstances returns true if there are PTR records for any instances of
+// the same service type other than inst.
+func (a *advertiser) hasOtherInstances(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (bool, error) {
+	target := dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, a.Zone.Name)
+
+	_, ok, err := cloudflareapi.First(
+		ctx,
+		a.listRecords(ctx, "PTR", dnssd.InstanceEnumerationDomain(inst.ServiceType, a.Zone.Name)),
+		func(candidate cloudflareapi.Record) bool {
+			return !strings.EqualFold(candidate.Content, target)
+		},
+	)
+
+	return ok, err
+}
+
+// syncServiceTypePTR adds a PTR record that enumerates the instance's service
+// type within the domain, unless one already exists.
+func (a *advertiser) syncServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	_, ok, err := a.findServiceTypePTR(ctx, inst)
+	if ok || err != nil {
+		return err
+	}
+
+	cs.Create(cloudflareapi.Record{
+		Type:    "PTR",
+		Name:    dnssd.TypeEnumerationDomain(a.Zone.Name),
+		Content: dnssd.InstanceEnumerationDomain(inst.ServiceType, a.Zone.Name),
+		TTL:     ttl(inst.TTL),
+	})
+
+	return nil
+}
+
+// deleteServiceTypePTR removes the PTR record that enumerates the instance's
+// service type, if inst is the last instance of that type.
+//
+// The Cloudflare API does not support atomic changes to multiple records, so
+// if another instance of the same type is advertised concurrently the service
+// type's PTR record may be removed while that instance still exists. It is
+// re-created the next time any instance of that type is advertised.
+func (a *advertiser) deleteServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	ok, err := a.hasOtherInstances(ctx, inst)
+	if ok || err != nil {
+		return err
+	}
+
+	current, ok, err := a.findServiceTypePTR(ctx, inst)
+	if !ok || err != nil {
+		return err
+	}
+
+	cs.Delete(current)
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package cloudflareprovider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/cloudflareprovider/internal/cloudflareapi"
+	"golang.org/x/exp/slices"
+)
+
+func (a *advertiser) findSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) ([]cloudflareapi.Record, error) {
+	return cloudflareapi.All(
+		ctx,
+		a.listRecords(
+			ctx,
+			"SRV",
+			dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, a.Zone.Name),
+		),
+	)
+}
+
+func (a *advertiser) syncSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findSRV(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	var desired []cloudflareapi.Record
+
+	for _, t := range inst.Targets {
+		desired = append(
+			desired,
+			cloudflareapi.Record{
+				Type: "SRV",
+				Name: dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, a.Zone.Name),
+				TTL:  ttl(inst.TTL),
+				// Cloudflare requires SRV records to be specified using
+				// structured data, it derives the record's content from it.
+				Data: &cloudflareapi.SRVData{
+					Priority: t.Priority,
+					Weight:   t.Weight,
+					Port:     t.Port,
+					Target:   t.Host,
+				},
+			},
+		)
+	}
+
+next:
+	for _, c := range current {
+		for i, d := range desired {
+			if c.Data != nil && c.Data.Equal(*d.Data) {
+				// We consider an SRV record with the same data to be the same
+				// record.
+				desired = slices.Delete(desired, i, i+1)
+				cs.Update(c, d)
+				continue next
+			}
+		}
+
+		cs.Delete(c)
+	}
+
+	for _, rec := range desired {
+		cs.Create(rec)
+	}
+
+	return nil
+}
+
+func (a *advertiser) deleteSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findSRV(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range current {
+		cs.Delete(c)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package cloudflareprovider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/cloudflareprovider/internal/cloudflareapi"
+	"golang.org/x/exp/slices"
+)
+
+// findSubtypePTRs returns all of the subtype PTR records that refer to the
+// given instance, regardless of which subtypes the instance provides.
+func (a *advertiser) findSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) ([]cloudflareapi.Record, error) {
+	suffix := "._sub." + dnssd.InstanceEnumerationDomain(inst.ServiceType, a.Zone.Name)
+	target := dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, a.Zone.Name)
+
+	var records []cloudflareapi.Record
+
+	err := cloudflareapi.Each(
+		ctx,
+		// The API only supports exact name matches, so we list all PTR
+		// records in the zone and filter them by name ourselves.
+		a.listRecords(ctx, "PTR", ""),
+		func(rec cloudflareapi.Record) (bool, error) {
+			if strings.HasSuffix(strings.ToLower(rec.Name), strings.ToLower(suffix)) &&
+				strings.EqualFold(rec.Content, target) {
+				records = append(records, rec)
+			}
+			return true, nil
+		},
+	)
+
+	return records, err
+}
+
+func (a *advertiser) syncSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findSubtypePTRs(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	var desired []cloudflareapi.Record
+
+	for _, st := range inst.Subtypes {
+		desired = append(
+			desired,
+			cloudflareapi.Record{
+				Type:    "PTR",
+				Name:    dnssd.SelectiveInstanceEnumerationDomain(st, inst.ServiceType, a.Zone.Name),
+				Content: dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, a.Zone.Name),
+				TTL:     ttl(inst.TTL),
+			},
+		)
+	}
+
+next:
+	for _, c := range current {
+		for i, d := range desired {
+			if strings.EqualFold(c.Name, d.Name) {
+				desired = slices.Delete(desired, i, i+1)
+				cs.Update(c, d)
+				continue next
+			}
+		}
+
+		// The instance no longer provides this subtype.
+		cs.Delete(c)
+	}
+
+	for _, rec := range desired {
+		cs.Create(rec)
+	}
+
+	return nil
+}
+
+func (a *advertiser) deleteSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findSubtypePTRs(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range current {
+		cs.Delete(c)
+	}
+
+	return nil
+}
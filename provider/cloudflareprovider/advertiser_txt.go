@@ -0,0 +1,88 @@
+package cloudflareprovider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/cloudflareprovider/internal/cloudflareapi"
+	"golang.org/x/exp/slices"
+)
+
+func (a *advertiser) findTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) ([]cloudflareapi.Record, error) {
+	return cloudflareapi.All(
+		ctx,
+		a.listRecords(
+			ctx,
+			"TXT",
+			dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, a.Zone.Name),
+		),
+	)
+}
+
+func (a *advertiser) syncTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findTXT(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	var desired []cloudflareapi.Record
+
+	for _, r := range provider.NewTXTRecords(inst) {
+		desired = append(
+			desired,
+			cloudflareapi.Record{
+				Type:    "TXT",
+				Name:    dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, a.Zone.Name),
+				Content: strings.TrimPrefix(r.String(), r.Hdr.String()),
+				TTL:     ttl(inst.TTL),
+			},
+		)
+	}
+
+next:
+	for _, c := range current {
+		for i, d := range desired {
+			if c.Content == d.Content {
+				// We consider a TXT record with the same content to be the same
+				// record.
+				desired = slices.Delete(desired, i, i+1)
+				cs.Update(c, d)
+				continue next
+			}
+		}
+
+		cs.Delete(c)
+	}
+
+	for _, rec := range desired {
+		cs.Create(rec)
+	}
+
+	return nil
+}
+
+func (a *advertiser) deleteTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findTXT(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range current {
+		cs.Delete(c)
+	}
+
+	return nil
+}
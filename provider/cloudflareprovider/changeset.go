@@ -0,0 +1,39 @@
+package cloudflareprovider
+
+import (
+	"github.com/dogmatiq/proclaim/provider/cloudflareprovider/internal/cloudflareapi"
+)
+
+// changeSet encapsulates a set of DNS record changes that must be applied to
+// reconcile the DNS zone with the desired state.
+type changeSet struct {
+	creates []cloudflareapi.Record
+	updates []struct {
+		Before cloudflareapi.Record
+		After  cloudflareapi.Record
+	}
+	deletes []cloudflareapi.Record
+}
+
+func (cs *changeSet) Create(rec cloudflareapi.Record) {
+	cs.creates = append(cs.creates, rec)
+}
+
+func (cs *changeSet) Update(before, after cloudflareapi.Record) {
+	if !before.Equal(after) {
+		cs.updates = append(
+			cs.updates,
+			struct {
+				Before cloudflareapi.Record
+				After  cloudflareapi.Record
+			}{
+				before,
+				after,
+			},
+		)
+	}
+}
+
+func (cs *changeSet) Delete(rec cloudflareapi.Record) {
+	cs.deletes = append(cs.deletes, rec)
+}
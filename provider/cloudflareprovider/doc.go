@@ -0,0 +1,3 @@
+// Package cloudflareprovider provides a driver implementation that advertises
+// DNS-SD service instances on domain names hosted by Cloudflare.
+package cloudflareprovider
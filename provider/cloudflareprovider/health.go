@@ -0,0 +1,16 @@
+package cloudflareprovider
+
+import (
+	"context"
+	"fmt"
+)
+
+// CheckHealth returns an error if the provider is unable to communicate or
+// authenticate with Cloudflare.
+func (p *Provider) CheckHealth(ctx context.Context) error {
+	if err := p.client().VerifyToken(ctx); err != nil {
+		return fmt.Errorf("unable to verify API token: %w", err)
+	}
+
+	return nil
+}
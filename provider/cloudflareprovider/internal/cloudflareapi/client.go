@@ -0,0 +1,126 @@
+package cloudflareapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the base URL of the production Cloudflare v4 API.
+const DefaultBaseURL = "https://api.cloudflare.com/client/v4"
+
+// Client is a client for the Cloudflare v4 API.
+type Client struct {
+	// Token is the API token used to authenticate requests.
+	Token string
+
+	// BaseURL is the base URL of the API. If it is empty, DefaultBaseURL is
+	// used.
+	BaseURL string
+
+	// HTTPClient is the client used to make HTTP requests. If it is nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// ResultInfo contains pagination information about a list response.
+type ResultInfo struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	TotalPages int `json:"total_pages"`
+}
+
+// envelope is the structure that wraps every API response.
+type envelope struct {
+	Success    bool            `json:"success"`
+	Errors     []Message       `json:"errors"`
+	Result     json.RawMessage `json:"result"`
+	ResultInfo *ResultInfo     `json:"result_info"`
+}
+
+// do performs an API request and unmarshals the result into out, which may be
+// nil.
+func (c *Client) do(
+	ctx context.Context,
+	method, path string,
+	query url.Values,
+	in, out any,
+) (*ResultInfo, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+
+	u := strings.TrimSuffix(base, "/") + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var env envelope
+	if err := json.NewDecoder(res.Body).Decode(&env); err != nil {
+		if res.StatusCode >= http.StatusBadRequest {
+			return nil, &Error{StatusCode: res.StatusCode}
+		}
+		return nil, fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	if !env.Success || res.StatusCode >= http.StatusBadRequest {
+		return nil, &Error{
+			StatusCode: res.StatusCode,
+			Messages:   env.Errors,
+		}
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(env.Result, out); err != nil {
+			return nil, fmt.Errorf("unable to decode result: %w", err)
+		}
+	}
+
+	return env.ResultInfo, nil
+}
+
+// listQuery returns the query parameters used to request the given page of a
+// list.
+func listQuery(page int) url.Values {
+	q := url.Values{}
+	q.Set("page", fmt.Sprint(page))
+	q.Set("per_page", "100")
+	return q
+}
@@ -0,0 +1,4 @@
+// Package cloudflareapi is a minimal client for the Cloudflare v4 API.
+//
+// It implements only the zone and DNS record operations used by the provider.
+package cloudflareapi
@@ -0,0 +1,85 @@
+package cloudflareapi
+
+import "context"
+
+// All returns a slice of all the values returned by list.
+//
+// list is called once for each page of results.
+func All[T any](
+	ctx context.Context,
+	list func(page int) (*ResultInfo, []T, error),
+) ([]T, error) {
+	var result []T
+
+	err := Each(
+		ctx,
+		list,
+		func(v T) (bool, error) {
+			result = append(result, v)
+			return true, nil
+		},
+	)
+
+	return result, err
+}
+
+// First returns the first value returned by list for which pred returns true.
+//
+// list is called once for each page of results.
+func First[T any](
+	ctx context.Context,
+	list func(page int) (*ResultInfo, []T, error),
+	pred func(T) bool,
+) (T, bool, error) {
+	var result T
+	var ok bool
+
+	err := Each(
+		ctx,
+		list,
+		func(v T) (bool, error) {
+			if pred(v) {
+				result = v
+				ok = true
+				return false, nil
+			}
+
+			return true, nil
+		},
+	)
+
+	return result, ok, err
+}
+
+// Each calls fn for each value returned by list.
+//
+// list is called once for each page of results.
+//
+// If fn returns false, the iteration stops.
+func Each[T any](
+	ctx context.Context,
+	list func(page int) (*ResultInfo, []T, error),
+	fn func(T) (bool, error),
+) error {
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		info, data, err := list(page)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range data {
+			ok, err := fn(v)
+			if !ok || err != nil {
+				return err
+			}
+		}
+
+		if info == nil || page >= info.TotalPages {
+			return nil
+		}
+	}
+}
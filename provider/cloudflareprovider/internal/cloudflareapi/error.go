@@ -0,0 +1,61 @@
+package cloudflareapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Message is an error or informational message returned by the API.
+type Message struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error is an unsuccessful response from the API.
+type Error struct {
+	StatusCode int
+	Messages   []Message
+}
+
+func (e *Error) Error() string {
+	if len(e.Messages) == 0 {
+		return fmt.Sprintf("cloudflare API returned HTTP %d", e.StatusCode)
+	}
+
+	var details []string
+	for _, m := range e.Messages {
+		details = append(details, fmt.Sprintf("%s (code %d)", m.Message, m.Code))
+	}
+
+	return fmt.Sprintf(
+		"cloudflare API returned HTTP %d: %s",
+		e.StatusCode,
+		strings.Join(details, ", "),
+	)
+}
+
+// HTTPStatusCode returns the HTTP status code of the response.
+func (e *Error) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// IsNotFound returns true if err is an error response from the API that
+// indicates that the requested resource does not exist.
+func IsNotFound(err error) bool {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// IgnoreNotFound returns nil if err is a not-found error, otherwise it returns
+// err unchanged.
+func IgnoreNotFound(err error) error {
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
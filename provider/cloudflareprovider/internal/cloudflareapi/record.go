@@ -0,0 +1,154 @@
+package cloudflareapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Record is a DNS record within a zone.
+type Record struct {
+	ID string `json:"id,omitempty"`
+
+	// Type is the record type, such as "PTR".
+	Type string `json:"type"`
+
+	// Name is the fully-qualified name of the record, without a trailing dot.
+	Name string `json:"name"`
+
+	// Content is the record's value in presentation format.
+	//
+	// It is derived from Data for records that have structured data, such as
+	// SRV records.
+	Content string `json:"content,omitempty"`
+
+	// TTL is the time-to-live of the record, in seconds.
+	TTL int `json:"ttl"`
+
+	// Data is the structured data of an SRV record.
+	Data *SRVData `json:"data,omitempty"`
+}
+
+// SRVData is the structured data of an SRV record.
+//
+// Cloudflare does not accept SRV records as a single content string, the
+// priority, weight, port and target must be provided separately.
+type SRVData struct {
+	Priority uint16 `json:"priority"`
+	Weight   uint16 `json:"weight"`
+	Port     uint16 `json:"port"`
+	Target   string `json:"target"`
+}
+
+// Equal returns true if r has the same type, name, value and TTL as x,
+// ignoring the record ID.
+func (r Record) Equal(x Record) bool {
+	if r.Type != x.Type || !strings.EqualFold(r.Name, x.Name) || r.TTL != x.TTL {
+		return false
+	}
+
+	if r.Data != nil || x.Data != nil {
+		return r.Data != nil && x.Data != nil && r.Data.Equal(*x.Data)
+	}
+
+	return r.Content == x.Content
+}
+
+// Equal returns true if d is equivalent to x.
+func (d SRVData) Equal(x SRVData) bool {
+	return d.Priority == x.Priority &&
+		d.Weight == x.Weight &&
+		d.Port == x.Port &&
+		strings.EqualFold(
+			strings.TrimSuffix(d.Target, "."),
+			strings.TrimSuffix(x.Target, "."),
+		)
+}
+
+// RecordFilter restricts the records returned by ListRecords.
+type RecordFilter struct {
+	// Type, if non-empty, limits results to records of this type.
+	Type string
+
+	// Name, if non-empty, limits results to records with this exact
+	// fully-qualified name.
+	Name string
+}
+
+// ListRecords returns the given page of records within a zone.
+func (c *Client) ListRecords(
+	ctx context.Context,
+	zoneID string,
+	f RecordFilter,
+	page int,
+) (*ResultInfo, []Record, error) {
+	q := listQuery(page)
+	if f.Type != "" {
+		q.Set("type", f.Type)
+	}
+	if f.Name != "" {
+		q.Set("name", f.Name)
+	}
+
+	var records []Record
+	info, err := c.do(ctx, http.MethodGet, recordsPath(zoneID), q, nil, &records)
+	return info, records, err
+}
+
+// CreateRecord creates a new record within a zone.
+func (c *Client) CreateRecord(
+	ctx context.Context,
+	zoneID string,
+	r Record,
+) (Record, error) {
+	r.ID = ""
+	var result Record
+	_, err := c.do(ctx, http.MethodPost, recordsPath(zoneID), nil, r, &result)
+	return result, err
+}
+
+// UpdateRecord replaces the record with the given ID.
+func (c *Client) UpdateRecord(
+	ctx context.Context,
+	zoneID, id string,
+	r Record,
+) (Record, error) {
+	r.ID = ""
+	var result Record
+	_, err := c.do(ctx, http.MethodPut, recordPath(zoneID, id), nil, r, &result)
+	return result, err
+}
+
+// DeleteRecord deletes the record with the given ID.
+func (c *Client) DeleteRecord(
+	ctx context.Context,
+	zoneID, id string,
+) error {
+	_, err := c.do(ctx, http.MethodDelete, recordPath(zoneID, id), nil, nil, nil)
+	return err
+}
+
+func recordsPath(zoneID string) string {
+	return "/zones/" + url.PathEscape(zoneID) + "/dns_records"
+}
+
+func recordPath(zoneID, id string) string {
+	return recordsPath(zoneID) + "/" + url.PathEscape(id)
+}
+
+// Value returns a human-readable representation of the record's value.
+func (r Record) Value() string {
+	if r.Data != nil {
+		return fmt.Sprintf(
+			"%d %d %d %s",
+			r.Data.Priority,
+			r.Data.Weight,
+			r.Data.Port,
+			r.Data.Target,
+		)
+	}
+
+	return r.Content
+}
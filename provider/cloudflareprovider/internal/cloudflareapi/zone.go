@@ -0,0 +1,42 @@
+package cloudflareapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Zone is a DNS zone (a "domain") managed by Cloudflare.
+type Zone struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Status      string   `json:"status"`
+	NameServers []string `json:"name_servers"`
+}
+
+// ListZones returns the given page of zones with the given name.
+func (c *Client) ListZones(
+	ctx context.Context,
+	name string,
+	page int,
+) (*ResultInfo, []Zone, error) {
+	q := listQuery(page)
+	q.Set("name", name)
+
+	var zones []Zone
+	info, err := c.do(ctx, http.MethodGet, "/zones", q, nil, &zones)
+	return info, zones, err
+}
+
+// GetZone returns the zone with the given ID.
+func (c *Client) GetZone(ctx context.Context, id string) (Zone, error) {
+	var z Zone
+	_, err := c.do(ctx, http.MethodGet, "/zones/"+url.PathEscape(id), nil, nil, &z)
+	return z, err
+}
+
+// VerifyToken returns an error if the client's API token is invalid.
+func (c *Client) VerifyToken(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodGet, "/user/tokens/verify", nil, nil, nil)
+	return err
+}
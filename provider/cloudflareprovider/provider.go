@@ -0,0 +1,139 @@
+package cloudflareprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/cloudflareprovider/internal/cloudflareapi"
+	"github.com/go-logr/logr"
+)
+
+// Provider is an implementation of provider.Provider that advertises DNS-SD
+// services on domains hosted by Cloudflare.
+type Provider struct {
+	// APIToken is the Cloudflare API token used to authenticate requests. It
+	// requires the "Zone:Read" and "DNS:Edit" permissions for each zone that
+	// services are advertised on.
+	APIToken string
+
+	// APIURL is the base URL of the Cloudflare v4 API. If it is empty, the
+	// production API is used.
+	APIURL string
+
+	// HTTPClient is the client used to make requests to the API. If it is nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Name distinguishes this provider from other Cloudflare providers that use
+	// different API tokens. It may be empty if there is only one such provider.
+	Name string
+
+	Logger logr.Logger
+}
+
+// ID returns a short unique identifier for the provider.
+func (p *Provider) ID() string {
+	return provider.NamedID("cloudflare", p.Name)
+}
+
+// Describe returns a human-readable description of the provider.
+func (p *Provider) Describe() string {
+	return provider.NamedDescription("Cloudflare", p.Name)
+}
+
+// AdvertiserByID returns the Advertiser with the given ID.
+func (p *Provider) AdvertiserByID(
+	ctx context.Context,
+	id map[string]any,
+) (provider.Advertiser, error) {
+	zoneID, err := unmarshalAdvertiserID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	client := p.client()
+
+	zone, err := client.GetZone(ctx, zoneID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get zone %q: %w", zoneID, err)
+	}
+
+	return p.newAdvertiser(client, zone), nil
+}
+
+// AdvertiserByDomain returns the Advertiser used to advertise services on the
+// given domain.
+//
+// ok is false if this provider does not manage the given domain.
+func (p *Provider) AdvertiserByDomain(
+	ctx context.Context,
+	domain string,
+) (provider.Advertiser, bool, error) {
+	client := p.client()
+	domain = strings.TrimSuffix(domain, ".")
+
+	zone, ok, err := cloudflareapi.First(
+		ctx,
+		func(page int) (*cloudflareapi.ResultInfo, []cloudflareapi.Zone, error) {
+			info, zones, err := client.ListZones(ctx, domain, page)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to list zones: %w", err)
+			}
+			return info, zones, nil
+		},
+		func(z cloudflareapi.Zone) bool {
+			return strings.EqualFold(z.Name, domain)
+		},
+	)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+
+	return p.newAdvertiser(client, zone), true, nil
+}
+
+// client returns a new API client that uses the provider's configuration.
+func (p *Provider) client() *cloudflareapi.Client {
+	return &cloudflareapi.Client{
+		Token:      p.APIToken,
+		BaseURL:    p.APIURL,
+		HTTPClient: p.HTTPClient,
+	}
+}
+
+func (p *Provider) newAdvertiser(
+	client *cloudflareapi.Client,
+	zone cloudflareapi.Zone,
+) *advertiser {
+	return &advertiser{
+		client,
+		zone,
+		p.Logger,
+	}
+}
+
+// marshalAdvertiserID returns the ID of the advertiser for the given zone.
+func marshalAdvertiserID(z cloudflareapi.Zone) map[string]any {
+	return map[string]any{
+		"zoneID": z.ID,
+	}
+}
+
+// unmarshalAdvertiserID parses an advertiser ID into its constituent parts.
+func unmarshalAdvertiserID(id map[string]any) (zoneID string, err error) {
+	zoneIDAny, ok := id["zoneID"]
+	if !ok {
+		return "", errors.New("invalid advertiser ID: missing zoneID key")
+	}
+
+	zoneID, ok = zoneIDAny.(string)
+	if !ok || zoneID == "" {
+		return "", errors.New("invalid advertiser ID: zoneID must be a non-empty string")
+	}
+
+	return zoneID, nil
+}
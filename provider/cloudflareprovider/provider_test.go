@@ -0,0 +1,210 @@
+package cloudflareprovider_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/internal/dnssdx"
+	"github.com/dogmatiq/proclaim/provider"
+	. "github.com/dogmatiq/proclaim/provider/cloudflareprovider"
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	zoneID = "023e105f4ecef8ad9ca31a8372d0c353"
+	domain = "proclaim-test.example.org"
+	token  = "<token>"
+)
+
+var _ = Describe("type Provider", func() {
+	providertest.DeclareTestSuite(
+		func(ctx context.Context) providertest.TestContext {
+			srv := newServer(zoneID, domain, token)
+			apiURL, port := srv.start()
+
+			return providertest.TestContext{
+				Provider: &Provider{
+					APIToken: token,
+					APIURL:   apiURL,
+					Logger:   logr.Discard(),
+				},
+				Domain: domain,
+				NameServers: func(ctx context.Context) ([]string, error) {
+					return []string{"127.0.0.1"}, nil
+				},
+				NameServerPort: port,
+				DeleteRecords: func(ctx context.Context) error {
+					srv.DeleteRecords()
+					return nil
+				},
+				MinTTL: 60 * time.Second,
+			}
+		},
+	)
+
+	var (
+		ctx    context.Context
+		srv    *server
+		apiURL string
+		port   string
+		p      *Provider
+		inst   provider.ServiceInstance
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		srv = newServer(zoneID, domain, token)
+		apiURL, port = srv.start()
+
+		p = &Provider{
+			APIToken: token,
+			APIURL:   apiURL,
+			Logger:   logr.Discard(),
+		}
+
+		inst = provider.ServiceInstance{
+			Name:        "instance",
+			ServiceType: "_proclaim._tcp",
+			Domain:      domain,
+			Targets: []provider.Target{
+				{Host: "host1.example.com", Port: 443, Priority: 10, Weight: 20},
+				{Host: "host2.example.com", Port: 8443, Priority: 20, Weight: 0},
+			},
+			TTL: 5 * time.Second,
+		}
+	})
+
+	Describe("func AdvertiserByID()", func() {
+		It("returns an advertiser for the zone with the given ID", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(a.ID()).To(Equal(map[string]any{"zoneID": zoneID}))
+
+			b, err := p.AdvertiserByID(ctx, a.ID())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(b.ID()).To(Equal(a.ID()))
+		})
+
+		It("returns an error if the zone does not exist", func() {
+			_, err := p.AdvertiserByID(ctx, map[string]any{"zoneID": "<unknown>"})
+			Expect(err).To(MatchError(ContainSubstring("unable to get zone")))
+		})
+
+		It("returns an error if the ID is invalid", func() {
+			_, err := p.AdvertiserByID(ctx, map[string]any{})
+			Expect(err).To(MatchError("invalid advertiser ID: missing zoneID key"))
+		})
+	})
+
+	When("there are more records than fit on a single page", func() {
+		It("finds records on subsequent pages", func() {
+			srv.PerPage = 2
+
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			inst.Subtypes = []string{"_a", "_b", "_c"}
+
+			cs, err := a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cs.IsCreate()).To(BeTrue())
+
+			cs, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cs.IsEmpty()).To(BeTrue())
+
+			_, err = a.Unadvertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			srv.m.Lock()
+			defer srv.m.Unlock()
+			Expect(srv.records).To(BeEmpty())
+		})
+	})
+
+	It("advertises records with the minimum TTL if the instance's TTL is too low", func() {
+		a, ok, err := p.AdvertiserByDomain(ctx, domain)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		_, err = a.Advertise(ctx, inst)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		srv.m.Lock()
+		defer srv.m.Unlock()
+
+		Expect(srv.records).NotTo(BeEmpty())
+		for _, rec := range srv.records {
+			Expect(rec.TTL).To(Equal(60))
+		}
+	})
+
+	It("advertises SRV records using structured data", func() {
+		a, ok, err := p.AdvertiserByDomain(ctx, domain)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		_, err = a.Advertise(ctx, inst)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		res := &dnssd.UnicastResolver{
+			Config: &dns.ClientConfig{
+				Servers:  []string{"127.0.0.1"},
+				Port:     port,
+				Ndots:    1,
+				Timeout:  1,
+				Attempts: 3,
+			},
+		}
+
+		actual, ok, err := dnssdx.LookupInstance(ctx, res, inst.Name, inst.ServiceType, domain)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(actual.Targets).To(ConsistOf(
+			provider.Target{Host: "host1.example.com", Port: 443, Priority: 10, Weight: 20},
+			provider.Target{Host: "host2.example.com", Port: 8443, Priority: 20, Weight: 0},
+		))
+	})
+
+	When("the API token is invalid", func() {
+		BeforeEach(func() {
+			p.APIToken = "<invalid>"
+		})
+
+		It("returns an error when finding an advertiser", func() {
+			_, _, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).To(MatchError(ContainSubstring("Invalid access token (code 9109)")))
+		})
+
+		It("fails the health check", func() {
+			err := p.CheckHealth(ctx)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("func ID()", func() {
+		It("includes the provider's name", func() {
+			a := &Provider{Name: "a"}
+			b := &Provider{Name: "b"}
+
+			Expect(a.ID()).To(Equal("cloudflare:a"))
+			Expect(b.ID()).To(Equal("cloudflare:b"))
+			Expect(a.Describe()).To(Equal("Cloudflare [a]"))
+		})
+
+		It("does not include an empty name", func() {
+			p := &Provider{}
+
+			Expect(p.ID()).To(Equal("cloudflare"))
+			Expect(p.Describe()).To(Equal("Cloudflare"))
+		})
+	})
+})
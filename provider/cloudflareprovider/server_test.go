@@ -0,0 +1,352 @@
+package cloudflareprovider_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	"github.com/miekg/dns"
+	"github.com/onsi/ginkgo/v2"
+)
+
+// server is a stand-in for Cloudflare. It serves the subset of the v4 API
+// used by the provider over HTTP, and answers DNS queries for the records in
+// its zone so that the advertised services can be discovered.
+type server struct {
+	ZoneID string
+	Zone   string
+	Token  string
+
+	// PerPage is the maximum number of results in each page of a list
+	// response.
+	PerPage int
+
+	m       sync.Mutex
+	nextID  int
+	records []record
+}
+
+// record is a DNS record as represented by the Cloudflare API.
+type record struct {
+	ID       string   `json:"id"`
+	Type     string   `json:"type"`
+	Name     string   `json:"name"`
+	Content  string   `json:"content"`
+	Priority *uint16  `json:"priority,omitempty"`
+	TTL      int      `json:"ttl"`
+	Data     *srvData `json:"data,omitempty"`
+}
+
+type srvData struct {
+	Priority uint16 `json:"priority"`
+	Weight   uint16 `json:"weight"`
+	Port     uint16 `json:"port"`
+	Target   string `json:"target"`
+}
+
+// newServer returns a new server that hosts a single zone.
+func newServer(zoneID, zone, token string) *server {
+	return &server{
+		ZoneID:  zoneID,
+		Zone:    zone,
+		Token:   token,
+		PerPage: 100,
+	}
+}
+
+// start starts the HTTP and DNS servers on random ports on the loopback
+// interface. It returns the base URL of the API and the DNS port. The servers
+// are stopped when the current test ends.
+func (s *server) start() (apiURL, dnsPort string) {
+	api := httptest.NewServer(http.HandlerFunc(s.ServeHTTP))
+	ginkgo.DeferCleanup(api.Close)
+
+	port := providertest.StartDNSServer(
+		&providertest.Responder{
+			Zone:    dns.Fqdn(s.Zone),
+			Records: s.dnsRecords,
+		},
+		nil,
+	)
+
+	return api.URL + "/client/v4", port
+}
+
+// DeleteRecords removes all records from the zone.
+func (s *server) DeleteRecords() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.records = nil
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusForbidden, 9109, "Invalid access token")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/client/v4")
+	zonePath := "/zones/" + s.ZoneID
+	recordsPath := zonePath + "/dns_records"
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && path == "/user/tokens/verify":
+		writeResult(w, map[string]string{"status": "active"}, nil)
+
+	case r.Method == http.MethodGet && path == "/zones":
+		var zones []map[string]any
+		if name := r.URL.Query().Get("name"); name == "" || strings.EqualFold(name, s.Zone) {
+			zones = append(zones, s.zone())
+		}
+		s.writePage(w, r, len(zones), func(i int) any { return zones[i] })
+
+	case r.Method == http.MethodGet && path == zonePath:
+		writeResult(w, s.zone(), nil)
+
+	case r.Method == http.MethodGet && path == recordsPath:
+		q := r.URL.Query()
+		var matches []record
+		for _, rec := range s.records {
+			if t := q.Get("type"); t != "" && t != rec.Type {
+				continue
+			}
+			if n := q.Get("name"); n != "" && !strings.EqualFold(n, rec.Name) {
+				continue
+			}
+			matches = append(matches, rec)
+		}
+		s.writePage(w, r, len(matches), func(i int) any { return matches[i] })
+
+	case r.Method == http.MethodPost && path == recordsPath:
+		rec, ok := s.decodeRecord(w, r)
+		if ok {
+			s.nextID++
+			rec.ID = fmt.Sprintf("record-%d", s.nextID)
+			s.records = append(s.records, rec)
+			writeResult(w, rec, nil)
+		}
+
+	case strings.HasPrefix(path, recordsPath+"/"):
+		id := strings.TrimPrefix(path, recordsPath+"/")
+		index := -1
+		for i, rec := range s.records {
+			if rec.ID == id {
+				index = i
+			}
+		}
+
+		if index == -1 {
+			writeError(w, http.StatusNotFound, 81044, "Record does not exist.")
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			rec, ok := s.decodeRecord(w, r)
+			if ok {
+				rec.ID = id
+				s.records[index] = rec
+				writeResult(w, rec, nil)
+			}
+		case http.MethodDelete:
+			s.records = append(s.records[:index], s.records[index+1:]...)
+			writeResult(w, map[string]string{"id": id}, nil)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, 10000, "Method not allowed")
+		}
+
+	case strings.HasPrefix(path, "/zones/"):
+		writeError(w, http.StatusNotFound, 7003, "Could not route to "+path)
+
+	default:
+		writeError(w, http.StatusNotFound, 7000, "No route for that URI")
+	}
+}
+
+func (s *server) zone() map[string]any {
+	return map[string]any{
+		"id":           s.ZoneID,
+		"name":         s.Zone,
+		"status":       "active",
+		"name_servers": []string{"ns.example.com"},
+	}
+}
+
+// decodeRecord decodes and validates a record from the request body. It
+// writes an error response if the record is invalid.
+func (s *server) decodeRecord(w http.ResponseWriter, r *http.Request) (record, bool) {
+	var rec record
+	if err := json.NewDecoder(r.Body).Decode(&rec); err != nil {
+		writeError(w, http.StatusBadRequest, 9207, "Request body is invalid.")
+		return record{}, false
+	}
+
+	// Like Cloudflare, a TTL of 1 means "automatic", otherwise it must be
+	// between 60 seconds and 1 day.
+	if rec.TTL != 1 && (rec.TTL < 60 || rec.TTL > 86400) {
+		writeError(w, http.StatusBadRequest, 9021, "Invalid TTL. Must be between 60 and 86400 seconds, or 1 for Automatic.")
+		return record{}, false
+	}
+
+	if !strings.EqualFold(rec.Name, s.Zone) && !strings.HasSuffix(strings.ToLower(rec.Name), "."+strings.ToLower(s.Zone)) {
+		writeError(w, http.StatusBadRequest, 9005, "Content for record is outside of the zone.")
+		return record{}, false
+	}
+
+	switch rec.Type {
+	case "SRV":
+		// Like Cloudflare, SRV records must be specified using structured
+		// data, from which the priority and content are derived.
+		if rec.Data == nil {
+			writeError(w, http.StatusBadRequest, 9101, "SRV record data is required.")
+			return record{}, false
+		}
+		rec.Priority = &rec.Data.Priority
+		rec.Content = fmt.Sprintf("%d %d %s", rec.Data.Weight, rec.Data.Port, rec.Data.Target)
+	case "PTR", "TXT":
+		rec.Data = nil
+	default:
+		writeError(w, http.StatusBadRequest, 9004, "Unsupported record type.")
+		return record{}, false
+	}
+
+	if _, err := s.toRR(rec); err != nil {
+		writeError(w, http.StatusBadRequest, 9007, err.Error())
+		return record{}, false
+	}
+
+	return rec, true
+}
+
+// writePage writes the requested page of a list of n items.
+func (s *server) writePage(w http.ResponseWriter, r *http.Request, n int, item func(int) any) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > s.PerPage {
+		perPage = s.PerPage
+	}
+
+	result := []any{}
+	for i := (page - 1) * perPage; i < n && i < page*perPage; i++ {
+		result = append(result, item(i))
+	}
+
+	writeResult(w, result, map[string]int{
+		"page":        page,
+		"per_page":    perPage,
+		"count":       len(result),
+		"total_count": n,
+		"total_pages": (n + perPage - 1) / perPage,
+	})
+}
+
+func writeResult(w http.ResponseWriter, result, info any) {
+	env := map[string]any{
+		"success":  true,
+		"errors":   []any{},
+		"messages": []any{},
+		"result":   result,
+	}
+	if info != nil {
+		env["result_info"] = info
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(env)
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"success":  false,
+		"errors":   []any{map[string]any{"code": code, "message": message}},
+		"messages": []any{},
+		"result":   nil,
+	})
+}
+
+// toRR converts a record to its DNS representation.
+func (s *server) toRR(rec record) (dns.RR, error) {
+	value := rec.Content
+	switch rec.Type {
+	case "SRV":
+		value = fmt.Sprintf(
+			"%d %d %d %s",
+			rec.Data.Priority,
+			rec.Data.Weight,
+			rec.Data.Port,
+			dns.Fqdn(rec.Data.Target),
+		)
+	case "PTR":
+		value = dns.Fqdn(value)
+	}
+
+	// An "automatic" TTL is served as 300 seconds.
+	ttl := rec.TTL
+	if ttl == 1 {
+		ttl = 300
+	}
+
+	return dns.NewRR(
+		fmt.Sprintf(
+			"%s %d IN %s %s",
+			dns.Fqdn(rec.Name),
+			ttl,
+			rec.Type,
+			value,
+		),
+	)
+}
+
+func (s *server) soa() dns.RR {
+	zone := dns.Fqdn(s.Zone)
+
+	return &dns.SOA{
+		Hdr: dns.RR_Header{
+			Name:   zone,
+			Rrtype: dns.TypeSOA,
+			Class:  dns.ClassINET,
+			Ttl:    3600,
+		},
+		Ns:      "ns.example.com.",
+		Mbox:    "dns.cloudflare.com.",
+		Serial:  1,
+		Refresh: 10000,
+		Retry:   2400,
+		Expire:  604800,
+		Minttl:  5,
+	}
+}
+
+// dnsRecords returns the DNS representation of the records in the zone. An
+// element is nil if the corresponding record is invalid.
+func (s *server) dnsRecords() []dns.RR {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	records := []dns.RR{s.soa()}
+
+	for _, rec := range s.records {
+		rr, err := s.toRR(rec)
+		if err != nil {
+			rr = nil
+		}
+		records = append(records, rr)
+	}
+
+	return records
+}
@@ -0,0 +1,185 @@
+package corednsprovider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+type advertiser struct {
+	Provider *Provider
+	Zone     string
+	Logger   logr.Logger
+}
+
+func (a *advertiser) ID() map[string]any {
+	return marshalAdvertiserID(a.Zone)
+}
+
+func (a *advertiser) Advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	lease, err := a.Provider.leaseID(ctx)
+	if err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	cs := &changeSet{Lease: lease}
+
+	if err := a.syncServiceTypePTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncPTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncSRV(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncTXT(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) Unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs := &changeSet{}
+
+	if err := a.deletePTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteSRV(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteTXT(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	return a.apply(ctx, cs)
+}
+
+// apply writes all of the changes in cs in a single etcd transaction.
+//
+// etcd applies the transaction atomically; either all of the changes succeed
+// or none of them do.
+func (a *advertiser) apply(
+	ctx context.Context,
+	cs *changeSet,
+) (provider.ChangeSet, error) {
+	if cs.IsEmpty() {
+		return provider.ChangeSet{}, nil
+	}
+
+	var (
+		ops    []clientv3.Op
+		result provider.ChangeSet
+	)
+
+	for _, c := range cs.changes {
+		if c.Value == nil {
+			ops = append(ops, clientv3.OpDelete(c.Key))
+		} else {
+			data, err := json.Marshal(c.Value)
+			if err != nil {
+				return provider.ChangeSet{}, fmt.Errorf("unable to marshal value of %s: %w", c.Key, err)
+			}
+
+			ops = append(ops, clientv3.OpPut(c.Key, string(data), clientv3.WithLease(cs.Lease)))
+		}
+
+		mergeChange(&result, c.Type, c.Change)
+	}
+
+	res, err := a.Provider.Client.
+		Txn(ctx).
+		If(cs.conditions...).
+		Then(ops...).
+		Commit()
+	if err != nil {
+		// If the lease has expired since it was granted, the keep-alive may
+		// not have noticed yet. Discard it so that the next attempt is made
+		// with a new lease.
+		if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			a.Provider.forgetLease(cs.Lease)
+		}
+
+		return provider.ChangeSet{}, fmt.Errorf("unable to apply changes: %w", err)
+	}
+
+	if !res.Succeeded {
+		return provider.ChangeSet{}, errors.New("unable to apply changes: shared records were modified concurrently")
+	}
+
+	for _, c := range cs.changes {
+		a.log(c)
+	}
+
+	return result, nil
+}
+
+func (a *advertiser) log(c change) {
+	if c.Value == nil {
+		a.Logger.Info(
+			"DELETE record",
+			"type", dns.TypeToString[c.Type],
+			"key", c.Key,
+		)
+		return
+	}
+
+	content := c.Value.Host
+	if c.Value.Text != "" {
+		content = c.Value.Text
+	}
+
+	a.Logger.Info(
+		"PUT record",
+		"type", dns.TypeToString[c.Type],
+		"key", c.Key,
+		"content", content,
+		"ttl", c.Value.TTL,
+	)
+}
+
+func instanceName(inst provider.ServiceInstance) string {
+	return dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, inst.Domain) + "."
+}
+
+func typeEnumerationName(inst provider.ServiceInstance) string {
+	return dnssd.TypeEnumerationDomain(inst.Domain) + "."
+}
+
+func serviceName(inst provider.ServiceInstance) string {
+	return dnssd.InstanceEnumerationDomain(inst.ServiceType, inst.Domain) + "."
+}
+
+// subtypesName returns the name beneath which the subtype enumeration records
+// for the instance's service type are stored.
+func subtypesName(inst provider.ServiceInstance) string {
+	return "_sub." + serviceName(inst)
+}
@@ -0,0 +1,86 @@
+package corednsprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+// syncPTR creates the PTR record that enumerates the instance within its
+// service type.
+//
+// CoreDNS can only serve a single PTR record with any given name, so an error
+// is returned if the record already refers to a different instance.
+func (a *advertiser) syncPTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	return a.syncExclusivePTR(ctx, provider.NewPTRRecord(inst), cs)
+}
+
+func (a *advertiser) deletePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	key := a.Provider.key(serviceName(inst))
+
+	current, ok, err := a.get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if !ok || !current.refersTo(instanceName(inst)) {
+		return nil
+	}
+
+	cs.Require(key, current, ok)
+	cs.Delete(dns.TypePTR, provider.Deleted, key)
+
+	// The instance is necessarily the only instance of its service type, so
+	// the service type itself is no longer advertised.
+	return a.deleteServiceTypePTR(ctx, inst, cs)
+}
+
+// syncExclusivePTR writes rr to the key for its name, returning an error if
+// the key already contains a PTR record that refers to a different target.
+func (a *advertiser) syncExclusivePTR(
+	ctx context.Context,
+	rr *dns.PTR,
+	cs *changeSet,
+) error {
+	key := a.Provider.key(rr.Hdr.Name)
+
+	current, ok, err := a.get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if ok && !current.refersTo(rr.Ptr) {
+		return fmt.Errorf(
+			"unable to advertise PTR record for %s: CoreDNS can only serve one PTR record per name, and it already refers to %s",
+			rr.Hdr.Name,
+			current.Value.Host,
+		)
+	}
+
+	// Only write the record if the key has not been modified since we read
+	// it. Otherwise, another controller may have advertised a different
+	// instance in the meantime.
+	cs.Require(key, current, ok)
+	cs.Sync(dns.TypePTR, syncChange(ok), key, current, ok, ptrValue(rr))
+
+	return nil
+}
+
+// ptrValue returns the value that represents rr.
+func ptrValue(rr *dns.PTR) value {
+	return value{
+		Host: strings.TrimSuffix(rr.Ptr, "."),
+		TTL:  rr.Hdr.Ttl,
+	}
+}
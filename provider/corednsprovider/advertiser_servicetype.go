@@ -0,0 +1,66 @@
+package corednsprovider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+// syncServiceTypePTR creates the PTR record that enumerates the instance's
+// service type within its domain.
+//
+// CoreDNS can only serve a single PTR record with any given name, so the
+// service type is only enumerated if no other service type is enumerated
+// already. Unlike the instance's own PTR records, this is not an error, as
+// DNS-SD clients do not need to enumerate service types in order to browse
+// for instances of a specific type.
+func (a *advertiser) syncServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	rr := provider.NewServiceTypePTRRecord(inst)
+	key := a.Provider.key(rr.Hdr.Name)
+
+	current, ok, err := a.get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if ok && !current.refersTo(rr.Ptr) {
+		return nil
+	}
+
+	cs.Require(key, current, ok)
+	cs.Sync(dns.TypePTR, syncChange(ok), key, current, ok, ptrValue(rr))
+
+	return nil
+}
+
+// deleteServiceTypePTR removes the PTR record that enumerates the instance's
+// service type within its domain, if it refers to that service type.
+//
+// It must only be called when the instance is the last instance of its service
+// type. The caller is expected to require that the instance enumeration PTR
+// record is unchanged, such that the transaction fails if another instance of
+// the same service type is advertised concurrently.
+func (a *advertiser) deleteServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	key := a.Provider.key(typeEnumerationName(inst))
+
+	current, ok, err := a.get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if ok && current.refersTo(serviceName(inst)) {
+		cs.Require(key, current, ok)
+		cs.Delete(dns.TypePTR, provider.Deleted, key)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package corednsprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+// findSRV returns the entries that CoreDNS serves as the instance's SRV
+// records.
+//
+// CoreDNS answers SRV queries using the values of all keys beneath the key for
+// the queried name, so each of the instance's targets is stored in a separate
+// key beneath the key for the instance name.
+func (a *advertiser) findSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) ([]entry, error) {
+	entries, err := a.list(ctx, a.Provider.key(instanceName(inst)))
+	if err != nil {
+		return nil, err
+	}
+
+	var srv []entry
+	for _, e := range entries {
+		if e.Value.Text == "" {
+			srv = append(srv, e)
+		}
+	}
+
+	return srv, nil
+}
+
+func (a *advertiser) syncSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findSRV(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	var desired []entry
+	for i, rr := range provider.NewSRVRecords(inst) {
+		desired = append(
+			desired,
+			entry{
+				Key: fmt.Sprintf("%s/srv%d", a.Provider.key(instanceName(inst)), i),
+				Value: value{
+					Host:     strings.TrimSuffix(rr.Target, "."),
+					Port:     int(rr.Port),
+					Priority: int(rr.Priority),
+					Weight:   int(rr.Weight),
+					TTL:      rr.Hdr.Ttl,
+				},
+			},
+		)
+	}
+
+	cs.SyncSet(dns.TypeSRV, current, desired)
+
+	return nil
+}
+
+func (a *advertiser) deleteSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findSRV(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range current {
+		cs.Delete(dns.TypeSRV, provider.Deleted, e.Key)
+	}
+
+	return nil
+}
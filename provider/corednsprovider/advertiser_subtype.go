@@ -0,0 +1,65 @@
+package corednsprovider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+	"golang.org/x/exp/slices"
+)
+
+// syncSubtypePTRs creates the PTR record that enumerates the instance within
+// each of its subtypes, and removes the instance from any subtypes that it no
+// longer provides.
+//
+// As with the instance's PTR record, an error is returned if another instance
+// is already enumerated within any of the subtypes.
+func (a *advertiser) syncSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	var keys []string
+
+	for _, rr := range provider.NewSubtypePTRRecords(inst) {
+		if err := a.syncExclusivePTR(ctx, rr, cs); err != nil {
+			return err
+		}
+
+		keys = append(keys, a.Provider.key(rr.Hdr.Name))
+	}
+
+	current, err := a.list(ctx, a.Provider.key(subtypesName(inst)))
+	if err != nil {
+		return err
+	}
+
+	for _, e := range current {
+		if e.refersTo(instanceName(inst)) && !slices.Contains(keys, e.Key) {
+			cs.Require(e.Key, e, true)
+			cs.Delete(dns.TypePTR, provider.Deleted, e.Key)
+		}
+	}
+
+	return nil
+}
+
+func (a *advertiser) deleteSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.list(ctx, a.Provider.key(subtypesName(inst)))
+	if err != nil {
+		return err
+	}
+
+	for _, e := range current {
+		if e.refersTo(instanceName(inst)) {
+			cs.Require(e.Key, e, true)
+			cs.Delete(dns.TypePTR, provider.Deleted, e.Key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package corednsprovider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+// findTXT returns the entries that CoreDNS serves as the instance's TXT
+// records.
+func (a *advertiser) findTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) ([]entry, error) {
+	entries, err := a.list(ctx, a.Provider.key(instanceName(inst)))
+	if err != nil {
+		return nil, err
+	}
+
+	var txt []entry
+	for _, e := range entries {
+		if e.Value.Text != "" {
+			txt = append(txt, e)
+		}
+	}
+
+	return txt, nil
+}
+
+func (a *advertiser) syncTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findTXT(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	var desired []entry
+	for i, rr := range provider.NewTXTRecords(inst) {
+		if len(rr.Txt) != 1 {
+			return fmt.Errorf(
+				"unable to advertise TXT record for %s: CoreDNS only supports TXT records that contain a single key/value pair",
+				rr.Hdr.Name,
+			)
+		}
+
+		// CoreDNS does not serve empty values, so an instance without any
+		// attributes is given a value that DNS-SD clients must ignore.
+		text := rr.Txt[0]
+		if text == "" {
+			text = "="
+		}
+
+		desired = append(
+			desired,
+			entry{
+				Key: fmt.Sprintf("%s/txt%d", a.Provider.key(instanceName(inst)), i),
+				Value: value{
+					Text: text,
+					TTL:  rr.Hdr.Ttl,
+				},
+			},
+		)
+	}
+
+	cs.SyncSet(dns.TypeTXT, current, desired)
+
+	return nil
+}
+
+func (a *advertiser) deleteTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findTXT(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range current {
+		cs.Delete(dns.TypeTXT, provider.Deleted, e.Key)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package corednsprovider
+
+import (
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"golang.org/x/exp/slices"
+)
+
+// changeSet encapsulates a set of changes to etcd keys that must be applied to
+// reconcile the DNS records with the desired state.
+type changeSet struct {
+	// Lease is the ID of the lease to attach to the keys that are written.
+	Lease clientv3.LeaseID
+
+	conditions []clientv3.Cmp
+	changes    []change
+}
+
+// change is a write to a single etcd key.
+type change struct {
+	Type   uint16
+	Change provider.Change
+	Key    string
+
+	// Value is the value written to the key. It is nil if the key is deleted.
+	Value *value
+}
+
+// Require adds a condition that the key with the given name has not been
+// modified since e was read. If ok is false, the condition is that the key
+// does not exist.
+//
+// etcd rejects the entire transaction if any of its conditions are not met,
+// which allows changes to be made conditional on the current state of keys
+// that are shared with other instances.
+func (cs *changeSet) Require(key string, e entry, ok bool) {
+	if ok {
+		cs.conditions = append(
+			cs.conditions,
+			clientv3.Compare(clientv3.ModRevision(key), "=", e.ModRevision),
+		)
+	} else {
+		cs.conditions = append(
+			cs.conditions,
+			clientv3.Compare(clientv3.CreateRevision(key), "=", 0),
+		)
+	}
+}
+
+// Sync writes the desired value to the given key, unless the key already has
+// that value and is attached to cs.Lease.
+//
+// If ok is false the key is assumed not to exist.
+func (cs *changeSet) Sync(
+	recordType uint16,
+	c provider.Change,
+	key string,
+	current entry,
+	ok bool,
+	desired value,
+) {
+	if ok && current.Value == desired && current.Lease == cs.Lease {
+		return
+	}
+
+	cs.changes = append(
+		cs.changes,
+		change{
+			Type:   recordType,
+			Change: c,
+			Key:    key,
+			Value:  &desired,
+		},
+	)
+}
+
+// SyncSet makes the keys in the current set of entries match the desired
+// entries exactly, writing new or modified values and deleting any keys that
+// are not desired.
+func (cs *changeSet) SyncSet(
+	recordType uint16,
+	current []entry,
+	desired []entry,
+) {
+	c := syncChange(len(current) != 0)
+
+next:
+	for _, d := range desired {
+		for _, e := range current {
+			if e.Key == d.Key {
+				cs.Sync(recordType, c, d.Key, e, true, d.Value)
+				continue next
+			}
+		}
+
+		cs.Sync(recordType, c, d.Key, entry{}, false, d.Value)
+	}
+
+	for _, e := range current {
+		if !slices.ContainsFunc(
+			desired,
+			func(d entry) bool {
+				return d.Key == e.Key
+			},
+		) {
+			cs.Delete(recordType, c, e.Key)
+		}
+	}
+}
+
+// syncChange returns the change made by syncing a record, depending on whether
+// the record already exists.
+func syncChange(exists bool) provider.Change {
+	if exists {
+		return provider.Updated
+	}
+	return provider.Created
+}
+
+// Delete removes the given key.
+func (cs *changeSet) Delete(recordType uint16, c provider.Change, key string) {
+	cs.changes = append(
+		cs.changes,
+		change{
+			Type:   recordType,
+			Change: c,
+			Key:    key,
+		},
+	)
+}
+
+// IsEmpty returns true if the change set does not contain any changes. It does
+// not consider the conditions.
+func (cs *changeSet) IsEmpty() bool {
+	return len(cs.changes) == 0
+}
+
+// mergeChange adds the change c to the change set based on the record type.
+func mergeChange(cs *provider.ChangeSet, recordType uint16, c provider.Change) {
+	switch recordType {
+	case dns.TypePTR:
+		cs.PTR |= c
+	case dns.TypeSRV:
+		cs.SRV |= c
+	case dns.TypeTXT:
+		cs.TXT |= c
+	}
+}
@@ -0,0 +1,26 @@
+// Package corednsprovider provides a driver implementation that advertises
+// DNS-SD service instances on domain names served by CoreDNS's etcd plugin.
+//
+// Records are written to etcd as SkyDNS-format JSON values, under keys that
+// CoreDNS derives from the record's name. Each key is attached to a lease that
+// is kept alive by the provider, such that the records expire if the
+// controller stops running.
+//
+// CoreDNS answers PTR queries using the value of a single key, so it can only
+// serve one PTR record with any given name. Consequently, only one instance of
+// each service type can be advertised within a domain, and an error is
+// returned when advertising a second instance. Likewise, only the first
+// service type to be advertised is included in the domain's service type
+// enumeration.
+//
+// CoreDNS also serves each TXT value as a TXT record that contains a single
+// string, and ignores empty values. An instance's attributes must therefore
+// contain at most one key/value pair per TXT record. Instances without any
+// attributes are advertised with a TXT record containing "=", which DNS-SD
+// clients ignore, as per RFC 6763 section 6.4.
+//
+// Finally, CoreDNS rescales the weights of SRV records such that the weights of
+// the records with the same priority sum to 100, and serves records with a
+// priority of zero with a priority of 10. Instances with targets that do not
+// already conform to these rules are reported as out-of-sync once advertised.
+package corednsprovider
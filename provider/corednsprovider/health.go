@@ -0,0 +1,24 @@
+package corednsprovider
+
+import (
+	"context"
+	"fmt"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// CheckHealth returns an error if the provider is unable to communicate or
+// authenticate with etcd.
+func (p *Provider) CheckHealth(ctx context.Context) error {
+	if _, err := p.Client.Get(
+		ctx,
+		p.prefix()+"/",
+		clientv3.WithPrefix(),
+		clientv3.WithKeysOnly(),
+		clientv3.WithLimit(1),
+	); err != nil {
+		return fmt.Errorf("unable to read from etcd: %w", err)
+	}
+
+	return nil
+}
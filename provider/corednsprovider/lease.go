@@ -0,0 +1,85 @@
+package corednsprovider
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// leaseID returns the ID of the lease to attach to the keys written by the
+// provider, granting a new lease if necessary.
+//
+// The lease is kept alive for as long as the etcd client is open. If it
+// expires, for example because etcd was unreachable for longer than its TTL,
+// the keys attached to it are removed and a new lease is granted the next time
+// this method is called. The records are then restored as each instance is
+// re-advertised.
+func (p *Provider) leaseID(ctx context.Context) (clientv3.LeaseID, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.lease != clientv3.NoLease {
+		return p.lease, nil
+	}
+
+	ttl := p.LeaseTTL
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+
+	res, err := p.Client.Grant(ctx, int64(math.Ceil(ttl.Seconds())))
+	if err != nil {
+		return clientv3.NoLease, fmt.Errorf("unable to grant lease: %w", err)
+	}
+
+	// The keep-alive is deliberately not bound to ctx, which only applies to
+	// the current operation.
+	responses, err := p.Client.KeepAlive(context.Background(), res.ID)
+	if err != nil {
+		return clientv3.NoLease, fmt.Errorf("unable to keep lease alive: %w", err)
+	}
+
+	p.lease = res.ID
+	go p.keepAlive(res.ID, responses)
+
+	p.Logger.Info(
+		"granted lease",
+		"lease", fmt.Sprintf("%x", res.ID),
+		"ttl", res.TTL,
+	)
+
+	return res.ID, nil
+}
+
+// keepAlive consumes the keep-alive responses for the given lease until the
+// lease expires or the client is closed, at which point a new lease is
+// granted the next time one is needed.
+func (p *Provider) keepAlive(
+	id clientv3.LeaseID,
+	responses <-chan *clientv3.LeaseKeepAliveResponse,
+) {
+	for range responses {
+		// The client sends keep-alive requests on our behalf, we only need to
+		// drain the responses so that it does not block.
+	}
+
+	p.forgetLease(id)
+
+	p.Logger.Info(
+		"lease is no longer being kept alive",
+		"lease", fmt.Sprintf("%x", id),
+	)
+}
+
+// forgetLease discards the given lease if it is the current lease, such that a
+// new lease is granted the next time one is needed.
+func (p *Provider) forgetLease(id clientv3.LeaseID) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.lease == id {
+		p.lease = clientv3.NoLease
+	}
+}
@@ -0,0 +1,167 @@
+package corednsprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// DefaultPrefix is the default etcd key prefix under which records are
+	// stored. It matches the default "path" option of CoreDNS's etcd plugin.
+	DefaultPrefix = "/skydns"
+
+	// DefaultLeaseTTL is the default TTL of the etcd lease attached to each
+	// record.
+	DefaultLeaseTTL = 60 * time.Second
+)
+
+// Provider is an implementation of provider.Provider that advertises DNS-SD
+// services on domains served by CoreDNS's etcd plugin.
+type Provider struct {
+	// Client is the client used to read and write records in etcd.
+	Client *clientv3.Client
+
+	// Prefix is the etcd key prefix under which CoreDNS looks for records, as
+	// configured by the "path" option of the etcd plugin. If it is empty,
+	// DefaultPrefix is used.
+	Prefix string
+
+	// Domains is the list of domains that CoreDNS serves from etcd. The
+	// provider only advertises services on these domains.
+	Domains []string
+
+	// LeaseTTL is the TTL of the etcd lease attached to each record. The lease
+	// is kept alive while the provider is running, such that the records are
+	// removed within this duration if the controller stops. If it is zero,
+	// DefaultLeaseTTL is used.
+	LeaseTTL time.Duration
+
+	// Name distinguishes this provider from other CoreDNS providers. It may be
+	// empty if there is only one such provider.
+	Name string
+
+	Logger logr.Logger
+
+	m     sync.Mutex
+	lease clientv3.LeaseID
+}
+
+// ID returns a short unique identifier for the provider.
+func (p *Provider) ID() string {
+	return provider.NamedID("coredns", p.Name)
+}
+
+// Describe returns a human-readable description of the provider.
+func (p *Provider) Describe() string {
+	return provider.NamedDescription(
+		fmt.Sprintf("CoreDNS (%s)", p.prefix()),
+		p.Name,
+	)
+}
+
+// AdvertiserByID returns the Advertiser with the given ID.
+func (p *Provider) AdvertiserByID(
+	ctx context.Context,
+	id map[string]any,
+) (provider.Advertiser, error) {
+	zone, err := unmarshalAdvertiserID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.isDomain(zone) {
+		return nil, fmt.Errorf("CoreDNS is not configured to serve the %q zone", zone)
+	}
+
+	return &advertiser{
+		p,
+		zone,
+		p.Logger,
+	}, nil
+}
+
+// AdvertiserByDomain returns the Advertiser used to advertise services on
+// the given domain.
+//
+// ok is false if this provider does not manage the given domain.
+func (p *Provider) AdvertiserByDomain(
+	ctx context.Context,
+	domain string,
+) (provider.Advertiser, bool, error) {
+	if !p.isDomain(domain) {
+		return nil, false, nil
+	}
+
+	return &advertiser{
+		p,
+		domain,
+		p.Logger,
+	}, true, nil
+}
+
+// isDomain returns true if domain is one of the domains in p.Domains.
+func (p *Provider) isDomain(domain string) bool {
+	return slices.ContainsFunc(
+		p.Domains,
+		func(d string) bool {
+			return strings.EqualFold(dns.Fqdn(d), dns.Fqdn(domain))
+		},
+	)
+}
+
+// prefix returns the etcd key prefix under which records are stored.
+func (p *Provider) prefix() string {
+	if p.Prefix == "" {
+		return DefaultPrefix
+	}
+	return path.Join("/", p.Prefix)
+}
+
+// key returns the etcd key that CoreDNS reads when answering queries for the
+// given domain name.
+//
+// The key consists of the labels of the name in reverse order, such that the
+// keys for all names within a domain share a common prefix.
+func (p *Provider) key(name string) string {
+	labels := dns.SplitDomainName(strings.ToLower(name))
+
+	elems := []string{p.prefix()}
+	for i := len(labels) - 1; i >= 0; i-- {
+		elems = append(elems, labels[i])
+	}
+
+	return path.Join(elems...)
+}
+
+// marshalAdvertiserID returns the ID of the advertiser for the given zone.
+func marshalAdvertiserID(zone string) map[string]any {
+	return map[string]any{
+		"zone": zone,
+	}
+}
+
+// unmarshalAdvertiserID parses an advertiser ID into its constituent parts.
+func unmarshalAdvertiserID(id map[string]any) (zone string, err error) {
+	zoneAny, ok := id["zone"]
+	if !ok {
+		return "", errors.New("invalid advertiser ID: missing zone key")
+	}
+
+	zone, ok = zoneAny.(string)
+	if !ok || zone == "" {
+		return "", errors.New("invalid advertiser ID: zone must be a non-empty string")
+	}
+
+	return zone, nil
+}
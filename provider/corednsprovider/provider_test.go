@@ -0,0 +1,298 @@
+package corednsprovider_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	. "github.com/dogmatiq/proclaim/provider/corednsprovider"
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const domain = "proclaim-test.example.org"
+
+var _ = Describe("type Provider", func() {
+	providertest.DeclareTestSuite(
+		func(ctx context.Context) providertest.TestContext {
+			srv := newServer(domain, DefaultPrefix)
+			client, port := srv.start()
+
+			return providertest.TestContext{
+				Provider: &Provider{
+					Client:  client,
+					Domains: []string{domain},
+					Logger:  logr.Discard(),
+				},
+				Domain: domain,
+				NameServers: func(ctx context.Context) ([]string, error) {
+					return []string{"127.0.0.1"}, nil
+				},
+				NameServerPort: port,
+				DeleteRecords: func(ctx context.Context) error {
+					srv.DeleteRecords()
+					return nil
+				},
+				OnePTRPerName: true,
+			}
+		},
+	)
+
+	var (
+		ctx  context.Context
+		inst provider.ServiceInstance
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		DeferCleanup(cancel)
+
+		inst = provider.ServiceInstance{
+			Name:        "instance",
+			ServiceType: "_proclaim._tcp",
+			Domain:      domain,
+			Subtypes:    []string{"_printer"},
+			Targets: []provider.Target{
+				{Host: "host1.example.com", Port: 443, Priority: 10, Weight: 20},
+				{Host: "host2.example.com", Port: 443, Priority: 10, Weight: 80},
+			},
+			Attributes: []dnssd.Attributes{
+				dnssd.NewAttributes().WithPair("key", []byte("value")),
+			},
+			TTL: 5 * time.Second,
+		}
+	})
+
+	It("stores SkyDNS records beneath the configured prefix", func() {
+		srv := newServer(domain, "/custom")
+		client, _ := srv.start()
+
+		p := &Provider{
+			Client:  client,
+			Prefix:  "custom",
+			Domains: []string{domain},
+			Logger:  logr.Discard(),
+		}
+
+		a, ok, err := p.AdvertiserByDomain(ctx, domain)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		_, err = a.Advertise(ctx, inst)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(srv.Keys()).To(HaveLen(6))
+		Expect(srv.Keys()).To(HaveKey("/custom/org/example/proclaim-test/_udp/_dns-sd/_services"))
+		Expect(srv.Keys()).To(HaveKey("/custom/org/example/proclaim-test/_tcp/_proclaim"))
+		Expect(srv.Keys()).To(HaveKey("/custom/org/example/proclaim-test/_tcp/_proclaim/_sub/_printer"))
+		Expect(srv.Keys()).To(HaveKey("/custom/org/example/proclaim-test/_tcp/_proclaim/instance/srv0"))
+		Expect(srv.Keys()).To(HaveKey("/custom/org/example/proclaim-test/_tcp/_proclaim/instance/srv1"))
+		Expect(srv.Keys()).To(HaveKey("/custom/org/example/proclaim-test/_tcp/_proclaim/instance/txt0"))
+	})
+
+	It("removes the instance from subtypes that it no longer provides", func() {
+		srv := newServer(domain, DefaultPrefix)
+		client, _ := srv.start()
+
+		p := &Provider{
+			Client:  client,
+			Domains: []string{domain},
+			Logger:  logr.Discard(),
+		}
+
+		a, _, err := p.AdvertiserByDomain(ctx, domain)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = a.Advertise(ctx, inst)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		inst.Subtypes = []string{"_scanner"}
+
+		_, err = a.Advertise(ctx, inst)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(srv.Keys()).To(HaveKey("/skydns/org/example/proclaim-test/_tcp/_proclaim/_sub/_scanner"))
+		Expect(srv.Keys()).NotTo(HaveKey("/skydns/org/example/proclaim-test/_tcp/_proclaim/_sub/_printer"))
+	})
+
+	When("another instance of the same service type is advertised", func() {
+		It("returns an error", func() {
+			srv := newServer(domain, DefaultPrefix)
+			client, _ := srv.start()
+
+			p := &Provider{
+				Client:  client,
+				Domains: []string{domain},
+				Logger:  logr.Discard(),
+			}
+
+			a, _, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			other := inst
+			other.Name = "other"
+
+			_, err = a.Advertise(ctx, other)
+			Expect(err).To(MatchError(ContainSubstring("CoreDNS can only serve one PTR record per name")))
+
+			// The first instance must be unaffected.
+			Expect(srv.Keys()).To(HaveLen(6))
+
+			// Unadvertising the other instance must not remove the records
+			// of the first instance.
+			_, err = a.Unadvertise(ctx, other)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(srv.Keys()).To(HaveLen(6))
+		})
+	})
+
+	When("an instance has a TXT record with multiple key/value pairs", func() {
+		It("returns an error", func() {
+			srv := newServer(domain, DefaultPrefix)
+			client, _ := srv.start()
+
+			p := &Provider{
+				Client:  client,
+				Domains: []string{domain},
+				Logger:  logr.Discard(),
+			}
+
+			a, _, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			inst.Attributes = []dnssd.Attributes{
+				dnssd.
+					NewAttributes().
+					WithPair("key1", []byte("value1")).
+					WithPair("key2", []byte("value2")),
+			}
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).To(MatchError(ContainSubstring("single key/value pair")))
+			Expect(srv.Keys()).To(BeEmpty())
+		})
+	})
+
+	When("the controller stops", func() {
+		It("removes the records once the lease expires", func() {
+			srv := newServer(domain, DefaultPrefix)
+			client, _ := srv.start()
+
+			p := &Provider{
+				Client:   client,
+				Domains:  []string{domain},
+				LeaseTTL: 1 * time.Second,
+				Logger:   logr.Discard(),
+			}
+
+			a, _, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// The records must outlive the lease's TTL while the lease is
+			// being kept alive.
+			Consistently(srv.Keys, 2*time.Second).Should(HaveLen(6))
+
+			// Closing the client stops the keep-alive.
+			Expect(client.Close()).To(Succeed())
+
+			Eventually(srv.Keys, 5*time.Second).Should(BeEmpty())
+		})
+	})
+
+	When("the lease expires while the controller is running", func() {
+		It("restores the records when the instance is re-advertised", func() {
+			srv := newServer(domain, DefaultPrefix)
+			client, _ := srv.start()
+
+			p := &Provider{
+				Client:   client,
+				Domains:  []string{domain},
+				LeaseTTL: 3 * time.Second,
+				Logger:   logr.Discard(),
+			}
+
+			a, _, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			srv.ExpireLeases()
+			Expect(srv.Keys()).To(BeEmpty())
+
+			Eventually(
+				func() bool {
+					cs, err := a.Advertise(ctx, inst)
+					return err == nil && cs.IsCreate()
+				},
+				5*time.Second,
+			).Should(BeTrue())
+
+			Expect(srv.Keys()).To(HaveLen(6))
+		})
+	})
+
+	When("the records were written by another controller", func() {
+		It("attaches them to its own lease", func() {
+			srv := newServer(domain, DefaultPrefix)
+			client, _ := srv.start()
+
+			previous := &Provider{
+				Client:  client,
+				Domains: []string{domain},
+				Logger:  logr.Discard(),
+			}
+
+			a, _, err := previous.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			before := srv.Keys()
+
+			p := &Provider{
+				Client:  client,
+				Domains: []string{domain},
+				Logger:  logr.Discard(),
+			}
+
+			a, _, err = p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cs, err := a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cs.IsCreate()).To(BeFalse())
+			Expect(cs.IsEmpty()).To(BeFalse())
+
+			after := srv.Keys()
+			Expect(after).To(HaveLen(len(before)))
+
+			for k, lease := range after {
+				Expect(lease).NotTo(Equal(before[k]), k)
+			}
+		})
+	})
+
+	Describe("func AdvertiserByID()", func() {
+		It("returns an error if the zone is not one of the configured domains", func() {
+			p := &Provider{
+				Domains: []string{domain},
+				Logger:  logr.Discard(),
+			}
+
+			_, err := p.AdvertiserByID(ctx, map[string]any{"zone": "other.example.org"})
+			Expect(err).To(MatchError(`CoreDNS is not configured to serve the "other.example.org" zone`))
+		})
+	})
+})
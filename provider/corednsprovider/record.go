@@ -0,0 +1,101 @@
+package corednsprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// value is the JSON representation of a DNS record within etcd, in the SkyDNS
+// format understood by CoreDNS's etcd plugin.
+//
+// Whether the value is served as a PTR, SRV or TXT record depends on the type
+// of the query. Values with a non-empty Text field are only served as TXT
+// records.
+type value struct {
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Priority int    `json:"priority,omitempty"`
+	Weight   int    `json:"weight,omitempty"`
+	Text     string `json:"text,omitempty"`
+	TTL      uint32 `json:"ttl,omitempty"`
+}
+
+// entry is a key/value pair that exists within etcd.
+type entry struct {
+	Key         string
+	Value       value
+	Lease       clientv3.LeaseID
+	ModRevision int64
+}
+
+// refersTo returns true if the entry is a PTR record that refers to the given
+// target name.
+func (e entry) refersTo(target string) bool {
+	return strings.EqualFold(
+		strings.TrimSuffix(e.Value.Host, "."),
+		strings.TrimSuffix(target, "."),
+	)
+}
+
+// get returns the entry with the given key, if it exists.
+func (a *advertiser) get(ctx context.Context, key string) (entry, bool, error) {
+	res, err := a.Provider.Client.Get(ctx, key)
+	if err != nil {
+		return entry{}, false, fmt.Errorf("unable to get %s: %w", key, err)
+	}
+
+	if len(res.Kvs) == 0 {
+		return entry{}, false, nil
+	}
+
+	e, err := unmarshalEntry(res.Kvs[0].Key, res.Kvs[0].Value)
+	if err != nil {
+		return entry{}, false, err
+	}
+
+	e.Lease = clientv3.LeaseID(res.Kvs[0].Lease)
+	e.ModRevision = res.Kvs[0].ModRevision
+
+	return e, true, nil
+}
+
+// list returns the entries with keys that are beneath the given key, that is,
+// the records for the subdomains of the name that the key represents.
+func (a *advertiser) list(ctx context.Context, key string) ([]entry, error) {
+	res, err := a.Provider.Client.Get(ctx, key+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %s: %w", key, err)
+	}
+
+	var entries []entry
+
+	for _, kv := range res.Kvs {
+		e, err := unmarshalEntry(kv.Key, kv.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		e.Lease = clientv3.LeaseID(kv.Lease)
+		e.ModRevision = kv.ModRevision
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func unmarshalEntry(k, v []byte) (entry, error) {
+	e := entry{
+		Key: string(k),
+	}
+
+	if err := json.Unmarshal(v, &e.Value); err != nil {
+		return entry{}, fmt.Errorf("unable to parse value of %s: %w", e.Key, err)
+	}
+
+	return e, nil
+}
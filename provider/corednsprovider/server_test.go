@@ -0,0 +1,593 @@
+package corednsprovider_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	"github.com/miekg/dns"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// server is a stand-in for etcd and CoreDNS. It serves the subset of the etcd
+// KV and lease APIs used by the provider over gRPC, and answers DNS queries for
+// the records in its zone by interpreting the stored values in the same way as
+// CoreDNS's etcd plugin.
+//
+// Unlike CoreDNS, it serves SRV records with the priority and weight exactly
+// as stored.
+type server struct {
+	pb.UnimplementedKVServer
+	pb.UnimplementedLeaseServer
+
+	Zone   string
+	Prefix string
+
+	m         sync.Mutex
+	revision  int64
+	keys      map[string]*mvccpb.KeyValue
+	leases    map[int64]*lease
+	nextLease int64
+}
+
+type lease struct {
+	TTL     int64
+	Expires time.Time
+}
+
+// newServer returns a new server that serves a single zone from keys beneath
+// the given prefix.
+func newServer(zone, prefix string) *server {
+	return &server{
+		Zone:     dns.Fqdn(zone),
+		Prefix:   prefix,
+		revision: 1,
+		keys:     map[string]*mvccpb.KeyValue{},
+		leases:   map[int64]*lease{},
+	}
+}
+
+// start starts the etcd and DNS servers on random ports on the loopback
+// interface. It returns a client connected to the etcd server, and the DNS
+// port. The servers are stopped and the client is closed when the current test
+// ends.
+func (s *server) start() (*clientv3.Client, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+	g := grpc.NewServer()
+	pb.RegisterKVServer(g, s)
+	pb.RegisterLeaseServer(g, s)
+
+	go g.Serve(listener) //nolint:errcheck
+	ginkgo.DeferCleanup(g.Stop)
+
+	client, err := clientv3.New(
+		clientv3.Config{
+			Endpoints:   []string{listener.Addr().String()},
+			DialTimeout: 5 * time.Second,
+			DialOptions: []grpc.DialOption{
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			},
+		},
+	)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	ginkgo.DeferCleanup(func() {
+		_ = client.Close()
+	})
+
+	port := providertest.StartDNSServer(
+		&providertest.Responder{
+			Zone:    s.Zone,
+			Records: s.dnsRecords,
+		},
+		nil,
+	)
+
+	return client, port
+}
+
+// DeleteRecords removes all keys.
+func (s *server) DeleteRecords() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.keys = map[string]*mvccpb.KeyValue{}
+}
+
+// ExpireLeases expires all leases, deleting the keys attached to them.
+func (s *server) ExpireLeases() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for id := range s.leases {
+		s.revoke(id)
+	}
+}
+
+// Keys returns the keys that exist, and the ID of the lease attached to each.
+func (s *server) Keys() map[string]int64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.expire()
+
+	keys := map[string]int64{}
+	for k, kv := range s.keys {
+		keys[k] = kv.Lease
+	}
+
+	return keys
+}
+
+func (s *server) Range(ctx context.Context, req *pb.RangeRequest) (*pb.RangeResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.expire()
+
+	return s.rangeKeys(req), nil
+}
+
+func (s *server) Put(ctx context.Context, req *pb.PutRequest) (*pb.PutResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.expire()
+
+	if err := s.checkPut(req); err != nil {
+		return nil, err
+	}
+
+	s.revision++
+	s.put(req)
+
+	return &pb.PutResponse{Header: s.header()}, nil
+}
+
+func (s *server) DeleteRange(ctx context.Context, req *pb.DeleteRangeRequest) (*pb.DeleteRangeResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.expire()
+	s.revision++
+
+	return s.deleteRange(req), nil
+}
+
+func (s *server) Txn(ctx context.Context, req *pb.TxnRequest) (*pb.TxnResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.expire()
+
+	succeeded := true
+	for _, c := range req.Compare {
+		if !s.compare(c) {
+			succeeded = false
+			break
+		}
+	}
+
+	ops := req.Success
+	if !succeeded {
+		ops = req.Failure
+	}
+
+	// Validate the entire transaction before applying any of it, so that it
+	// is applied atomically.
+	for _, op := range ops {
+		if put := op.GetRequestPut(); put != nil {
+			if err := s.checkPut(put); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.revision++
+
+	res := &pb.TxnResponse{Succeeded: succeeded}
+
+	for _, op := range ops {
+		switch r := op.Request.(type) {
+		case *pb.RequestOp_RequestRange:
+			res.Responses = append(res.Responses, &pb.ResponseOp{
+				Response: &pb.ResponseOp_ResponseRange{ResponseRange: s.rangeKeys(r.RequestRange)},
+			})
+		case *pb.RequestOp_RequestPut:
+			s.put(r.RequestPut)
+			res.Responses = append(res.Responses, &pb.ResponseOp{
+				Response: &pb.ResponseOp_ResponsePut{ResponsePut: &pb.PutResponse{Header: s.header()}},
+			})
+		case *pb.RequestOp_RequestDeleteRange:
+			res.Responses = append(res.Responses, &pb.ResponseOp{
+				Response: &pb.ResponseOp_ResponseDeleteRange{ResponseDeleteRange: s.deleteRange(r.RequestDeleteRange)},
+			})
+		default:
+			return nil, rpctypes.ErrGRPCNotCapable
+		}
+	}
+
+	res.Header = s.header()
+
+	return res, nil
+}
+
+func (s *server) LeaseGrant(ctx context.Context, req *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.nextLease++
+	s.leases[s.nextLease] = &lease{
+		TTL:     req.TTL,
+		Expires: time.Now().Add(time.Duration(req.TTL) * time.Second),
+	}
+
+	return &pb.LeaseGrantResponse{
+		Header: s.header(),
+		ID:     s.nextLease,
+		TTL:    req.TTL,
+	}, nil
+}
+
+func (s *server) LeaseRevoke(ctx context.Context, req *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.expire()
+
+	if _, ok := s.leases[req.ID]; !ok {
+		return nil, rpctypes.ErrGRPCLeaseNotFound
+	}
+
+	s.revoke(req.ID)
+
+	return &pb.LeaseRevokeResponse{Header: s.header()}, nil
+}
+
+func (s *server) LeaseKeepAlive(stream pb.Lease_LeaseKeepAliveServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		res := s.keepAlive(req.ID)
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *server) keepAlive(id int64) *pb.LeaseKeepAliveResponse {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.expire()
+
+	res := &pb.LeaseKeepAliveResponse{
+		Header: s.header(),
+		ID:     id,
+	}
+
+	// A TTL of zero indicates to the client that the lease has expired.
+	if l, ok := s.leases[id]; ok {
+		l.Expires = time.Now().Add(time.Duration(l.TTL) * time.Second)
+		res.TTL = l.TTL
+	}
+
+	return res
+}
+
+func (s *server) header() *pb.ResponseHeader {
+	return &pb.ResponseHeader{Revision: s.revision}
+}
+
+// expire revokes any leases that have not been kept alive.
+func (s *server) expire() {
+	now := time.Now()
+
+	for id, l := range s.leases {
+		if now.After(l.Expires) {
+			s.revoke(id)
+		}
+	}
+}
+
+func (s *server) revoke(id int64) {
+	delete(s.leases, id)
+
+	for k, kv := range s.keys {
+		if kv.Lease == id {
+			delete(s.keys, k)
+		}
+	}
+}
+
+func (s *server) checkPut(req *pb.PutRequest) error {
+	if req.Lease == 0 {
+		return nil
+	}
+
+	if _, ok := s.leases[req.Lease]; !ok {
+		return rpctypes.ErrGRPCLeaseNotFound
+	}
+
+	return nil
+}
+
+func (s *server) put(req *pb.PutRequest) {
+	kv, ok := s.keys[string(req.Key)]
+	if !ok {
+		kv = &mvccpb.KeyValue{
+			Key:            req.Key,
+			CreateRevision: s.revision,
+		}
+		s.keys[string(req.Key)] = kv
+	}
+
+	kv.Value = req.Value
+	kv.Lease = req.Lease
+	kv.ModRevision = s.revision
+	kv.Version++
+}
+
+func (s *server) rangeKeys(req *pb.RangeRequest) *pb.RangeResponse {
+	res := &pb.RangeResponse{Header: s.header()}
+
+	for _, k := range s.match(req.Key, req.RangeEnd) {
+		res.Count++
+
+		if req.CountOnly || (req.Limit > 0 && int64(len(res.Kvs)) >= req.Limit) {
+			res.More = !req.CountOnly
+			continue
+		}
+
+		kv := *s.keys[k]
+		if req.KeysOnly {
+			kv.Value = nil
+		}
+
+		res.Kvs = append(res.Kvs, &kv)
+	}
+
+	return res
+}
+
+func (s *server) deleteRange(req *pb.DeleteRangeRequest) *pb.DeleteRangeResponse {
+	res := &pb.DeleteRangeResponse{Header: s.header()}
+
+	for _, k := range s.match(req.Key, req.RangeEnd) {
+		delete(s.keys, k)
+		res.Deleted++
+	}
+
+	return res
+}
+
+// match returns the existing keys within the given range, in order.
+func (s *server) match(key, end []byte) []string {
+	var keys []string
+
+	for k := range s.keys {
+		switch {
+		case len(end) == 0:
+			if k != string(key) {
+				continue
+			}
+		case bytes.Equal(end, []byte{0}):
+			if k < string(key) {
+				continue
+			}
+		default:
+			if k < string(key) || k >= string(end) {
+				continue
+			}
+		}
+
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (s *server) compare(c *pb.Compare) bool {
+	kv, ok := s.keys[string(c.Key)]
+	if !ok {
+		kv = &mvccpb.KeyValue{}
+	}
+
+	var n int
+
+	switch c.Target {
+	case pb.Compare_VERSION:
+		n = compareInt(kv.Version, c.GetVersion())
+	case pb.Compare_CREATE:
+		n = compareInt(kv.CreateRevision, c.GetCreateRevision())
+	case pb.Compare_MOD:
+		n = compareInt(kv.ModRevision, c.GetModRevision())
+	case pb.Compare_VALUE:
+		if !ok {
+			return false
+		}
+		n = bytes.Compare(kv.Value, c.GetValue())
+	case pb.Compare_LEASE:
+		n = compareInt(kv.Lease, c.GetLease())
+	}
+
+	switch c.Result {
+	case pb.Compare_EQUAL:
+		return n == 0
+	case pb.Compare_NOT_EQUAL:
+		return n != 0
+	case pb.Compare_GREATER:
+		return n > 0
+	case pb.Compare_LESS:
+		return n < 0
+	}
+
+	return false
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// skyDNSValue is the SkyDNS representation of a record, as read by CoreDNS.
+type skyDNSValue struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Priority int    `json:"priority"`
+	Weight   int    `json:"weight"`
+	Text     string `json:"text"`
+	TTL      uint32 `json:"ttl"`
+}
+
+// dnsRecords returns the DNS records that CoreDNS would serve for the keys
+// within the zone. An element is nil if the corresponding key is invalid.
+//
+// CoreDNS answers PTR queries using the value of the key for the queried name,
+// and answers SRV and TXT queries using the values of that key and all keys
+// beneath it.
+func (s *server) dnsRecords() []dns.RR {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.expire()
+
+	records := []dns.RR{s.soa()}
+	texts := map[string]bool{}
+
+	for k, kv := range s.keys {
+		name, ok := s.nameOf(k)
+		if !ok {
+			continue
+		}
+
+		var v skyDNSValue
+		if err := json.Unmarshal(kv.Value, &v); err != nil {
+			records = append(records, nil)
+			continue
+		}
+
+		if v.Host != "" && v.Text == "" {
+			records = append(
+				records,
+				&dns.PTR{
+					Hdr: header(name, dns.TypePTR, v.TTL),
+					Ptr: dns.Fqdn(v.Host),
+				},
+			)
+		}
+
+		for _, owner := range s.ancestors(name) {
+			switch {
+			case v.Text != "":
+				// CoreDNS ignores duplicate TXT values.
+				if texts[owner+v.Text] {
+					continue
+				}
+				texts[owner+v.Text] = true
+
+				records = append(
+					records,
+					&dns.TXT{
+						Hdr: header(owner, dns.TypeTXT, v.TTL),
+						Txt: []string{v.Text},
+					},
+				)
+			case v.Host != "":
+				records = append(
+					records,
+					&dns.SRV{
+						Hdr:      header(owner, dns.TypeSRV, v.TTL),
+						Priority: uint16(v.Priority),
+						Weight:   uint16(v.Weight),
+						Port:     uint16(v.Port),
+						Target:   dns.Fqdn(v.Host),
+					},
+				)
+			}
+		}
+	}
+
+	return records
+}
+
+// nameOf returns the domain name that is represented by the given key.
+func (s *server) nameOf(key string) (string, bool) {
+	prefix := path.Join("/", s.Prefix) + "/"
+	if !strings.HasPrefix(key, prefix) {
+		return "", false
+	}
+
+	labels := strings.Split(strings.TrimPrefix(key, prefix), "/")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+
+	name := dns.Fqdn(strings.Join(labels, "."))
+
+	return name, dns.IsSubDomain(s.Zone, name)
+}
+
+// ancestors returns name and each of its parent domains within the zone.
+func (s *server) ancestors(name string) []string {
+	var names []string
+
+	for {
+		names = append(names, name)
+
+		if strings.EqualFold(name, s.Zone) {
+			return names
+		}
+
+		i, _ := dns.NextLabel(name, 0)
+		name = name[i:]
+	}
+}
+
+func (s *server) soa() dns.RR {
+	return &dns.SOA{
+		Hdr:     header(s.Zone, dns.TypeSOA, 300),
+		Ns:      "ns.dns." + s.Zone,
+		Mbox:    "hostmaster." + s.Zone,
+		Serial:  uint32(s.revision),
+		Refresh: 7200,
+		Retry:   1800,
+		Expire:  86400,
+		Minttl:  30,
+	}
+}
+
+func header(name string, recordType uint16, ttl uint32) dns.RR_Header {
+	return dns.RR_Header{
+		Name:   name,
+		Rrtype: recordType,
+		Class:  dns.ClassINET,
+		Ttl:    ttl,
+	}
+}
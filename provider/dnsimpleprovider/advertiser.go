@@ -2,12 +2,13 @@ package dnsimpleprovider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 
 	"github.com/dnsimple/dnsimple-go/dnsimple"
-	"github.com/dogmatiq/dissolve/dnssd"
 	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/dnsimpleprovider/internal/dnsimplex"
 	"github.com/go-logr/logr"
 )
 
@@ -23,14 +24,30 @@ func (a *advertiser) ID() map[string]any {
 
 func (a *advertiser) Advertise(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs, err := a.advertise(ctx, inst)
+	return cs, apiError(err)
+}
+
+func (a *advertiser) advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
 ) (provider.ChangeSet, error) {
 	cs := &changeSet{}
 
+	if err := a.syncServiceTypePTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
 	if err := a.syncPTR(ctx, inst, cs); err != nil {
 		return provider.ChangeSet{}, err
 	}
 
+	if err := a.syncSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
 	if err := a.syncSRV(ctx, inst, cs); err != nil {
 		return provider.ChangeSet{}, err
 	}
@@ -39,12 +56,31 @@ func (a *advertiser) Advertise(
 		return provider.ChangeSet{}, err
 	}
 
-	return a.apply(ctx, cs)
+	result, err := a.apply(ctx, cs)
+	if err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if result.PTR&provider.Created != 0 {
+		if err := a.restoreServiceTypePTR(ctx, inst, &result); err != nil {
+			return provider.ChangeSet{}, err
+		}
+	}
+
+	return result, nil
 }
 
 func (a *advertiser) Unadvertise(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs, err := a.unadvertise(ctx, inst)
+	return cs, apiError(err)
+}
+
+func (a *advertiser) unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
 ) (provider.ChangeSet, error) {
 	cs := &changeSet{}
 
@@ -52,6 +88,10 @@ func (a *advertiser) Unadvertise(
 		return provider.ChangeSet{}, err
 	}
 
+	if err := a.deleteSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
 	if err := a.deleteSRV(ctx, inst, cs); err != nil {
 		return provider.ChangeSet{}, err
 	}
@@ -60,7 +100,16 @@ func (a *advertiser) Unadvertise(
 		return provider.ChangeSet{}, err
 	}
 
-	return a.apply(ctx, cs)
+	result, err := a.apply(ctx, cs)
+	if err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteServiceTypePTR(ctx, inst, &result); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	return result, nil
 }
 
 func (a *advertiser) apply(
@@ -147,3 +196,36 @@ func (a *advertiser) apply(
 
 	return result, nil
 }
+
+// merge adds the changes described by x to cs.
+func merge(cs *provider.ChangeSet, x provider.ChangeSet) {
+	cs.PTR |= x.PTR
+	cs.SRV |= x.SRV
+	cs.TXT |= x.TXT
+}
+
+// apiError returns err in a form that the reconciler can classify.
+//
+// It returns a provider.RateLimitError if err is an error response from
+// dnsimple.com that indicates that the API rate limit has been exceeded, in
+// which case the reset time is taken from the X-RateLimit-Reset header of the
+// response. It returns a provider.HTTPError if err is any other error
+// response. Otherwise, it returns err unchanged.
+func apiError(err error) error {
+	var res *dnsimple.ErrorResponse
+	if !errors.As(err, &res) {
+		return err
+	}
+
+	if reset, ok := dnsimplex.RateLimitReset(err); ok {
+		return provider.RateLimitError{
+			Reset: reset,
+			Cause: err,
+		}
+	}
+
+	return provider.HTTPError{
+		StatusCode: res.HTTPResponse.StatusCode,
+		Cause:      err,
+	}
+}
@@ -0,0 +1,132 @@
+package dnsimpleprovider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/dnsimpleprovider/internal/dnsimplex"
+	"github.com/miekg/dns"
+	"golang.org/x/exp/slices"
+)
+
+func (a *advertiser) AdvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs, err := a.advertiseBrowseDomains(ctx, d)
+	return cs, apiError(err)
+}
+
+func (a *advertiser) advertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs := &changeSet{}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		name := a.relativeName(set.Name)
+
+		current, err := a.findBrowseDomainPTRs(ctx, name)
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		var desired []dnsimple.ZoneRecordAttributes
+		for _, rr := range set.Records {
+			desired = append(
+				desired,
+				dnsimple.ZoneRecordAttributes{
+					ZoneID:  a.Zone.Name,
+					Type:    "PTR",
+					Name:    dnsimple.String(name),
+					Content: strings.TrimSuffix(rr.Ptr, "."),
+					TTL:     int(rr.Hdr.Ttl),
+				},
+			)
+		}
+
+	next:
+		for _, c := range current {
+			for i, d := range desired {
+				if strings.EqualFold(c.Content, d.Content) {
+					desired = slices.Delete(desired, i, i+1)
+					cs.Update(c, d)
+					continue next
+				}
+			}
+
+			cs.Delete(c)
+		}
+
+		for _, attr := range desired {
+			cs.Create(attr)
+		}
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) UnadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs, err := a.unadvertiseBrowseDomains(ctx, d)
+	return cs, apiError(err)
+}
+
+func (a *advertiser) unadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs := &changeSet{}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		current, err := a.findBrowseDomainPTRs(ctx, a.relativeName(set.Name))
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		for _, c := range current {
+			cs.Delete(c)
+		}
+	}
+
+	return a.apply(ctx, cs)
+}
+
+// findBrowseDomainPTRs returns the PTR records with the given name, which is
+// relative to the zone.
+func (a *advertiser) findBrowseDomainPTRs(
+	ctx context.Context,
+	name string,
+) ([]dnsimple.ZoneRecord, error) {
+	return dnsimplex.All(
+		ctx,
+		func(opts dnsimple.ListOptions) (*dnsimple.Pagination, []dnsimple.ZoneRecord, error) {
+			res, err := a.Client.ListRecords(
+				ctx,
+				strconv.FormatInt(a.Zone.AccountID, 10),
+				a.Zone.Name,
+				&dnsimple.ZoneRecordListOptions{
+					ListOptions: opts,
+					Name:        dnsimple.String(name),
+					Type:        dnsimple.String("PTR"),
+				},
+			)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to list PTR records: %w", err)
+			}
+
+			return res.Pagination, res.Data, nil
+		},
+	)
+}
+
+// relativeName returns a fully-qualified name relative to the zone.
+func (a *advertiser) relativeName(name string) string {
+	return strings.TrimSuffix(name, "."+dns.Fqdn(a.Zone.Name))
+}
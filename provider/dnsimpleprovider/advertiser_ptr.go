@@ -7,12 +7,13 @@ import (
 
 	"github.com/dnsimple/dnsimple-go/dnsimple"
 	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
 	"github.com/dogmatiq/proclaim/provider/dnsimpleprovider/internal/dnsimplex"
 )
 
 func (a *advertiser) findPTR(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 ) (dnsimple.ZoneRecord, bool, error) {
 	return dnsimplex.First(
 		ctx,
@@ -41,7 +42,7 @@ func (a *advertiser) findPTR(
 
 func (a *advertiser) syncPTR(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 	cs *changeSet,
 ) error {
 	current, ok, err := a.findPTR(ctx, inst)
@@ -68,7 +69,7 @@ func (a *advertiser) syncPTR(
 
 func (a *advertiser) deletePTR(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 	cs *changeSet,
 ) error {
 	current, ok, err := a.findPTR(ctx, inst)
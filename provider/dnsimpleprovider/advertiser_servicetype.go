@@ -0,0 +1,173 @@
+package dnsimpleprovider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/dnsimpleprovider/internal/dnsimplex"
+)
+
+// typeEnumerationName is the name of the PTR records that enumerate the
+// service types within a domain, relative to the zone.
+const typeEnumerationName = "_services._dns-sd._udp"
+
+func (a *advertiser) findServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (dnsimple.ZoneRecord, bool, error) {
+	return dnsimplex.First(
+		ctx,
+		func(opts dnsimple.ListOptions) (*dnsimple.Pagination, []dnsimple.ZoneRecord, error) {
+			res, err := a.Client.ListRecords(
+				ctx,
+				strconv.FormatInt(a.Zone.AccountID, 10),
+				a.Zone.Name,
+				&dnsimple.ZoneRecordListOptions{
+					ListOptions: opts,
+					Name:        dnsimple.String(typeEnumerationName),
+					Type:        dnsimple.String("PTR"),
+				},
+			)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to list PTR records: %w", err)
+			}
+
+			return res.Pagination, res.Data, nil
+		},
+		func(candidate dnsimple.ZoneRecord) bool {
+			return candidate.Content == dnssd.InstanceEnumerationDomain(inst.ServiceType, inst.Domain)
+		},
+	)
+}
+
+// hasOtherInstances returns true if there are PTR records for any instances of
+// the same service type other than inst.
+func (a *advertiser) hasOtherInstances(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (bool, error) {
+	_, ok, err := dnsimplex.First(
+		ctx,
+		func(opts dnsimple.ListOptions) (*dnsimple.Pagination, []dnsimple.ZoneRecord, error) {
+			res, err := a.Client.ListRecords(
+				ctx,
+				strconv.FormatInt(a.Zone.AccountID, 10),
+				a.Zone.Name,
+				&dnsimple.ZoneRecordListOptions{
+					ListOptions: opts,
+					Name:        dnsimple.String(inst.ServiceType),
+					Type:        dnsimple.String("PTR"),
+				},
+			)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to list PTR records: %w", err)
+			}
+
+			return res.Pagination, res.Data, nil
+		},
+		func(candidate dnsimple.ZoneRecord) bool {
+			return candidate.Content != dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, inst.Domain)
+		},
+	)
+
+	return ok, err
+}
+
+// syncServiceTypePTR adds a PTR record that enumerates the instance's service
+// type within the domain, unless one already exists.
+func (a *advertiser) syncServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	_, ok, err := a.findServiceTypePTR(ctx, inst)
+	if ok || err != nil {
+		return err
+	}
+
+	cs.Create(dnsimple.ZoneRecordAttributes{
+		ZoneID:  a.Zone.Name,
+		Type:    "PTR",
+		Name:    dnsimple.String(typeEnumerationName),
+		Content: dnssd.InstanceEnumerationDomain(inst.ServiceType, inst.Domain),
+		TTL:     int(inst.TTL.Seconds()),
+	})
+
+	return nil
+}
+
+// restoreServiceTypePTR re-creates the PTR record that enumerates the
+// instance's service type if it was removed by another controller while inst
+// was being advertised.
+//
+// It must be called after the instance's own PTR record has been created. The
+// DNSimple API does not support atomic changes to multiple records, so another
+// controller may remove the service type's PTR record after syncServiceTypePTR
+// has found it, but before the instance's PTR record exists. Either this check
+// observes the removal, or the other controller observes the instance's PTR
+// record and restores the service type's PTR record itself.
+func (a *advertiser) restoreServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	result *provider.ChangeSet,
+) error {
+	cs := &changeSet{}
+
+	if err := a.syncServiceTypePTR(ctx, inst, cs); err != nil {
+		return err
+	}
+
+	r, err := a.apply(ctx, cs)
+	if err != nil {
+		return err
+	}
+
+	merge(result, r)
+
+	return nil
+}
+
+// deleteServiceTypePTR removes the PTR record that enumerates the instance's
+// service type, if inst is the last instance of that type.
+//
+// It must be called after the instance's own PTR record has been deleted. The
+// other instances of the type are listed again after the service type's PTR
+// record is removed. If another controller has advertised an instance of the
+// same type in the meantime, the record is restored. See also
+// restoreServiceTypePTR, which performs the equivalent check when advertising.
+func (a *advertiser) deleteServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	result *provider.ChangeSet,
+) error {
+	ok, err := a.hasOtherInstances(ctx, inst)
+	if ok || err != nil {
+		return err
+	}
+
+	current, ok, err := a.findServiceTypePTR(ctx, inst)
+	if !ok || err != nil {
+		return err
+	}
+
+	cs := &changeSet{}
+	cs.Delete(current)
+
+	r, err := a.apply(ctx, cs)
+	if err != nil {
+		return err
+	}
+
+	merge(result, r)
+
+	ok, err = a.hasOtherInstances(ctx, inst)
+	if !ok || err != nil {
+		return err
+	}
+
+	return a.restoreServiceTypePTR(ctx, inst, result)
+}
@@ -7,14 +7,16 @@ import (
 
 	"github.com/dnsimple/dnsimple-go/dnsimple"
 	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
 	"github.com/dogmatiq/proclaim/provider/dnsimpleprovider/internal/dnsimplex"
+	"golang.org/x/exp/slices"
 )
 
 func (a *advertiser) findSRV(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
-) (dnsimple.ZoneRecord, bool, error) {
-	return dnsimplex.One(
+	inst provider.ServiceInstance,
+) ([]dnsimple.ZoneRecord, error) {
+	return dnsimplex.All(
 		ctx,
 		func(opts dnsimple.ListOptions) (*dnsimple.Pagination, []dnsimple.ZoneRecord, error) {
 			res, err := a.Client.ListRecords(
@@ -40,34 +42,54 @@ func (a *advertiser) findSRV(
 
 func (a *advertiser) syncSRV(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 	cs *changeSet,
 ) error {
-	current, ok, err := a.findSRV(ctx, inst)
+	current, err := a.findSRV(ctx, inst)
 	if err != nil {
 		return err
 	}
 
-	desired := dnsimple.ZoneRecordAttributes{
-		ZoneID: a.Zone.Name,
-		Type:   "SRV",
-		Name: dnsimple.String(
-			dnssd.EscapeInstance(inst.Name) + "." + inst.ServiceType,
-		),
-		Content: fmt.Sprintf(
-			"%d %d %s",
-			inst.Weight,
-			inst.TargetPort,
-			inst.TargetHost,
-		),
-		TTL:      int(inst.TTL.Seconds()),
-		Priority: int(inst.Priority),
+	var desired []dnsimple.ZoneRecordAttributes
+
+	for _, t := range inst.Targets {
+		desired = append(
+			desired,
+			dnsimple.ZoneRecordAttributes{
+				ZoneID: a.Zone.Name,
+				Type:   "SRV",
+				Name: dnsimple.String(
+					dnssd.EscapeInstance(inst.Name) + "." + inst.ServiceType,
+				),
+				Content: fmt.Sprintf(
+					"%d %d %s",
+					t.Weight,
+					t.Port,
+					t.Host,
+				),
+				TTL:      int(inst.TTL.Seconds()),
+				Priority: int(t.Priority),
+			},
+		)
 	}
 
-	if ok {
-		cs.Update(current, desired)
-	} else {
-		cs.Create(desired)
+next:
+	for _, c := range current {
+		for i, d := range desired {
+			if c.Content == d.Content && c.Priority == d.Priority {
+				// We consider an SRV record with the same content and priority
+				// to be the same record.
+				desired = slices.Delete(desired, i, i+1)
+				cs.Update(c, d)
+				continue next
+			}
+		}
+
+		cs.Delete(c)
+	}
+
+	for _, attr := range desired {
+		cs.Create(attr)
 	}
 
 	return nil
@@ -75,15 +97,17 @@ func (a *advertiser) syncSRV(
 
 func (a *advertiser) deleteSRV(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 	cs *changeSet,
 ) error {
-	current, ok, err := a.findSRV(ctx, inst)
-	if !ok || err != nil {
+	current, err := a.findSRV(ctx, inst)
+	if err != nil {
 		return err
 	}
 
-	cs.Delete(current)
+	for _, c := range current {
+		cs.Delete(c)
+	}
 
 	return nil
 }
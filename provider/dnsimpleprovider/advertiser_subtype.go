@@ -0,0 +1,121 @@
+package dnsimpleprovider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/dnsimpleprovider/internal/dnsimplex"
+	"golang.org/x/exp/slices"
+)
+
+// findSubtypePTRs returns all of the subtype PTR records that refer to the
+// given instance, regardless of which subtypes the instance provides.
+func (a *advertiser) findSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) ([]dnsimple.ZoneRecord, error) {
+	suffix := "._sub." + inst.ServiceType
+	target := dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, inst.Domain)
+
+	var records []dnsimple.ZoneRecord
+
+	err := dnsimplex.Each(
+		ctx,
+		func(opts dnsimple.ListOptions) (*dnsimple.Pagination, []dnsimple.ZoneRecord, error) {
+			res, err := a.Client.ListRecords(
+				ctx,
+				strconv.FormatInt(a.Zone.AccountID, 10),
+				a.Zone.Name,
+				&dnsimple.ZoneRecordListOptions{
+					ListOptions: opts,
+					NameLike:    dnsimple.String(suffix),
+					Type:        dnsimple.String("PTR"),
+				},
+			)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to list PTR records: %w", err)
+			}
+
+			return res.Pagination, res.Data, nil
+		},
+		func(rec dnsimple.ZoneRecord) (bool, error) {
+			// The "name_like" filter matches anywhere within the name, so we
+			// need to filter out records with names that merely contain the
+			// suffix.
+			if strings.HasSuffix(rec.Name, suffix) && rec.Content == target {
+				records = append(records, rec)
+			}
+			return true, nil
+		},
+	)
+
+	return records, err
+}
+
+func (a *advertiser) syncSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findSubtypePTRs(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	var desired []dnsimple.ZoneRecordAttributes
+
+	for _, st := range inst.Subtypes {
+		desired = append(
+			desired,
+			dnsimple.ZoneRecordAttributes{
+				ZoneID:  a.Zone.Name,
+				Type:    "PTR",
+				Name:    dnsimple.String(st + "._sub." + inst.ServiceType),
+				Content: dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, inst.Domain),
+				TTL:     int(inst.TTL.Seconds()),
+			},
+		)
+	}
+
+next:
+	for _, c := range current {
+		for i, d := range desired {
+			if c.Name == *d.Name {
+				desired = slices.Delete(desired, i, i+1)
+				cs.Update(c, d)
+				continue next
+			}
+		}
+
+		// The instance no longer provides this subtype.
+		cs.Delete(c)
+	}
+
+	for _, attr := range desired {
+		cs.Create(attr)
+	}
+
+	return nil
+}
+
+func (a *advertiser) deleteSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findSubtypePTRs(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range current {
+		cs.Delete(c)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package dnsimpleprovider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+func (a *advertiser) ZoneTiming(ctx context.Context) (provider.ZoneTiming, error) {
+	res, err := a.Client.ListRecords(
+		ctx,
+		strconv.FormatInt(a.Zone.AccountID, 10),
+		a.Zone.Name,
+		&dnsimple.ZoneRecordListOptions{
+			Name: dnsimple.String(""),
+			Type: dnsimple.String("SOA"),
+		},
+	)
+	if err != nil {
+		return provider.ZoneTiming{}, fmt.Errorf("unable to list SOA records: %w", err)
+	}
+
+	var t provider.ZoneTiming
+
+	for _, rec := range res.Data {
+		rr, err := dns.NewRR(
+			fmt.Sprintf(
+				"%s %d IN SOA %s",
+				dns.Fqdn(a.Zone.Name),
+				rec.TTL,
+				rec.Content,
+			),
+		)
+		if err != nil {
+			return provider.ZoneTiming{}, fmt.Errorf("unable to parse SOA record: %w", err)
+		}
+
+		if soa, ok := rr.(*dns.SOA); ok {
+			t.NegativeTTL = provider.NegativeTTL(soa)
+		}
+	}
+
+	return t, nil
+}
@@ -8,13 +8,14 @@ import (
 
 	"github.com/dnsimple/dnsimple-go/dnsimple"
 	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
 	"github.com/dogmatiq/proclaim/provider/dnsimpleprovider/internal/dnsimplex"
 	"golang.org/x/exp/slices"
 )
 
 func (a *advertiser) findTXT(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 ) ([]dnsimple.ZoneRecord, error) {
 	return dnsimplex.All(
 		ctx,
@@ -42,7 +43,7 @@ func (a *advertiser) findTXT(
 
 func (a *advertiser) syncTXT(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 	cs *changeSet,
 ) error {
 	current, err := a.findTXT(ctx, inst)
@@ -52,7 +53,7 @@ func (a *advertiser) syncTXT(
 
 	var desired []dnsimple.ZoneRecordAttributes
 
-	for _, r := range dnssd.NewTXTRecords(inst) {
+	for _, r := range provider.NewTXTRecords(inst) {
 		desired = append(
 			desired,
 			dnsimple.ZoneRecordAttributes{
@@ -91,7 +92,7 @@ next:
 
 func (a *advertiser) deleteTXT(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 	cs *changeSet,
 ) error {
 	current, err := a.findTXT(ctx, inst)
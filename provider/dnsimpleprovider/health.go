@@ -0,0 +1,16 @@
+package dnsimpleprovider
+
+import (
+	"context"
+	"fmt"
+)
+
+// CheckHealth returns an error if the provider is unable to communicate or
+// authenticate with DNSimple.
+func (p *Provider) CheckHealth(ctx context.Context) error {
+	if _, err := p.Client.Identity.Whoami(ctx); err != nil {
+		return fmt.Errorf("unable to query identity: %w", err)
+	}
+
+	return nil
+}
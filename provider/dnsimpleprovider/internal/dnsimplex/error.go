@@ -3,6 +3,7 @@ package dnsimplex
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/dnsimple/dnsimple-go/dnsimple"
 )
@@ -28,3 +29,30 @@ func IgnoreNotFound(err error) error {
 
 	return err
 }
+
+// IsRateLimited returns true if err is an error response from dnsimple.com that
+// indicates that the API rate limit has been exceeded.
+func IsRateLimited(err error) bool {
+	var res *dnsimple.ErrorResponse
+
+	if errors.As(err, &res) {
+		return res.HTTPResponse.StatusCode == http.StatusTooManyRequests
+	}
+
+	return false
+}
+
+// RateLimitReset returns the time at which the API rate limit is reset, based
+// on the headers of the error response in err.
+//
+// ok is false if err is not an error response from dnsimple.com, or does not
+// indicate that the rate limit has been exceeded.
+func RateLimitReset(err error) (_ time.Time, ok bool) {
+	var res *dnsimple.ErrorResponse
+
+	if errors.As(err, &res) && IsRateLimited(err) {
+		return res.RateLimitReset(), true
+	}
+
+	return time.Time{}, false
+}
@@ -18,15 +18,21 @@ import (
 // services on domains hosted by dnsimple.com.
 type Provider struct {
 	Client *dnsimple.Client
+
+	// Name distinguishes this provider from other DNSimple providers that use
+	// different API tokens. It may be empty if there is only one such provider.
+	Name string
+
 	Logger logr.Logger
 }
 
 // ID returns a short unique identifier for the provider.
 func (p *Provider) ID() string {
+	id := "dnsimple"
 	if env := p.environment(); env != "production" {
-		return fmt.Sprintf("dnsimple.%s", env)
+		id = fmt.Sprintf("dnsimple.%s", env)
 	}
-	return "dnsimple"
+	return provider.NamedID(id, p.Name)
 }
 
 func (p *Provider) environment() string {
@@ -46,10 +52,11 @@ func (p *Provider) environment() string {
 
 // Describe returns a human-readable description of the provider.
 func (p *Provider) Describe() string {
+	desc := "DNSimple"
 	if env := p.environment(); env != "production" {
-		return fmt.Sprintf("DNSimple (%s)", env)
+		desc = fmt.Sprintf("DNSimple (%s)", env)
 	}
-	return "DNSimple"
+	return provider.NamedDescription(desc, p.Name)
 }
 
 // AdvertiserByID returns the Advertiser with the given ID.
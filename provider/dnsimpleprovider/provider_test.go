@@ -10,6 +10,7 @@ import (
 	"github.com/dogmatiq/proclaim/provider/internal/providertest"
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
 )
 
 const (
@@ -102,4 +103,25 @@ var _ = Describe("type Provider", func() {
 			}
 		},
 	)
+
+	Describe("func ID()", func() {
+		It("includes the provider's name", func() {
+			client := dnsimple.NewClient(nil)
+			client.BaseURL = "https://api.sandbox.dnsimple.com"
+
+			a := &Provider{Client: client, Name: "a"}
+			b := &Provider{Client: client, Name: "b"}
+
+			Expect(a.ID()).To(Equal("dnsimple.sandbox:a"))
+			Expect(b.ID()).To(Equal("dnsimple.sandbox:b"))
+			Expect(a.Describe()).To(Equal("DNSimple (sandbox) [a]"))
+		})
+
+		It("does not include an empty name", func() {
+			p := &Provider{Client: dnsimple.NewClient(nil)}
+
+			Expect(p.ID()).To(Equal("dnsimple"))
+			Expect(p.Describe()).To(Equal("DNSimple"))
+		})
+	})
 })
@@ -0,0 +1,29 @@
+package provider
+
+// HTTPError is an error that describes an unsuccessful response from a
+// provider's HTTP API.
+//
+// The reconciler uses the status code to classify errors in its metrics. Any
+// error with an HTTPStatusCode() method is classified in the same way, which
+// includes the errors returned by the AWS SDK. Advertisers that use an API
+// client with other error types may wrap them in an HTTPError.
+type HTTPError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Cause is the error returned by the API client.
+	Cause error
+}
+
+func (e HTTPError) Error() string {
+	return e.Cause.Error()
+}
+
+func (e HTTPError) Unwrap() error {
+	return e.Cause
+}
+
+// HTTPStatusCode returns the HTTP status code of the response.
+func (e HTTPError) HTTPStatusCode() int {
+	return e.StatusCode
+}
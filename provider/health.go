@@ -0,0 +1,15 @@
+package provider
+
+import "context"
+
+// HealthChecker is a Provider that can verify that it is able to communicate
+// with the underlying DNS hosting provider.
+//
+// It is an optional interface that may be implemented by any Provider.
+type HealthChecker interface {
+	Provider
+
+	// CheckHealth returns an error if the provider is unable to communicate
+	// or authenticate with the DNS hosting provider.
+	CheckHealth(ctx context.Context) error
+}
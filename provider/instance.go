@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/miekg/dns"
+	"golang.org/x/exp/slices"
+)
+
+// ServiceInstance is a DNS-SD service instance that is advertised by a
+// provider.
+//
+// It is equivalent to dnssd.ServiceInstance, except that it may have multiple
+// targets, each of which is advertised as a separate SRV record.
+type ServiceInstance struct {
+	// Name is the service instance's unqualified name.
+	Name string
+
+	// ServiceType is the type of service that the instance provides.
+	ServiceType string
+
+	// Domain is the domain under which the instance is advertised.
+	Domain string
+
+	// Subtypes is the set of service subtypes that the instance provides, for
+	// example "_printer".
+	//
+	// See https://www.rfc-editor.org/rfc/rfc6763#section-7.1.
+	Subtypes []string
+
+	// Targets is the set of addresses at which the service can be reached.
+	Targets []Target
+
+	// Attributes contains the attributes encoded in the instance's TXT
+	// records. Each element in the slice corresponds to a single TXT record.
+	Attributes []dnssd.Attributes
+
+	// TTL is the time-to-live of the instance's DNS records.
+	TTL time.Duration
+}
+
+// Target is an address at which a service instance can be reached.
+type Target struct {
+	// Host is the fully-qualified hostname of the machine that hosts the
+	// service.
+	Host string
+
+	// Port is TCP or UDP port on which the service is provided.
+	Port uint16
+
+	// Priority is the priority of the target, lower values are contacted
+	// first.
+	Priority uint16
+
+	// Weight is the weight of the target relative to other targets with the
+	// same priority. Higher values are more likely to be chosen.
+	Weight uint16
+}
+
+// Equal returns true if i and inst are equal.
+//
+// The targets are compared without regard to their order.
+func (i ServiceInstance) Equal(inst ServiceInstance) bool {
+	return i.Name == inst.Name &&
+		i.ServiceType == inst.ServiceType &&
+		i.Domain == inst.Domain &&
+		subtypeSetsEqual(i.Subtypes, inst.Subtypes) &&
+		targetSetsEqual(i.Targets, inst.Targets) &&
+		dnssd.AttributeCollectionsEqual(i.Attributes, inst.Attributes) &&
+		i.TTL == inst.TTL
+}
+
+// NewPTRRecord returns the PTR record that enumerates the given instance.
+func NewPTRRecord(inst ServiceInstance) *dns.PTR {
+	return dnssd.NewPTRRecord(inst.dissolve(Target{}))
+}
+
+// NewServiceTypePTRRecord returns the PTR record that enumerates the given
+// instance's service type within its domain.
+func NewServiceTypePTRRecord(inst ServiceInstance) *dns.PTR {
+	return dnssd.NewServiceTypePTRRecord(inst.ServiceType, inst.Domain, inst.TTL)
+}
+
+// NewSubtypePTRRecords returns the PTR records that enumerate the given
+// instance within each of its subtypes.
+func NewSubtypePTRRecords(inst ServiceInstance) []*dns.PTR {
+	var records []*dns.PTR
+
+	for _, st := range inst.Subtypes {
+		records = append(records, dnssd.NewServiceSubTypePTRRecord(inst.dissolve(Target{}), st))
+	}
+
+	return records
+}
+
+// NewSRVRecords returns the SRV records for the given instance, one per
+// target.
+func NewSRVRecords(inst ServiceInstance) []*dns.SRV {
+	var records []*dns.SRV
+
+	for _, t := range inst.Targets {
+		records = append(records, dnssd.NewSRVRecord(inst.dissolve(t)))
+	}
+
+	return records
+}
+
+// NewTXTRecords returns the TXT records for the given instance.
+func NewTXTRecords(inst ServiceInstance) []*dns.TXT {
+	return dnssd.NewTXTRecords(inst.dissolve(Target{}))
+}
+
+// dissolve returns the Dissolve representation of the instance, using t as the
+// instance's only target.
+func (i ServiceInstance) dissolve(t Target) dnssd.ServiceInstance {
+	return dnssd.ServiceInstance{
+		Name:        i.Name,
+		ServiceType: i.ServiceType,
+		Domain:      i.Domain,
+		TargetHost:  t.Host,
+		TargetPort:  t.Port,
+		Priority:    t.Priority,
+		Weight:      t.Weight,
+		Attributes:  i.Attributes,
+		TTL:         i.TTL,
+	}
+}
+
+// subtypeSetsEqual returns true if a and b contain the same subtypes,
+// regardless of order.
+func subtypeSetsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, x := range a {
+		if !slices.Contains(b, x) {
+			return false
+		}
+	}
+
+	for _, x := range b {
+		if !slices.Contains(a, x) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// targetSetsEqual returns true if a and b contain the same targets, regardless
+// of order.
+func targetSetsEqual(a, b []Target) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	matched := make([]bool, len(b))
+
+next:
+	for _, x := range a {
+		for j, y := range b {
+			if !matched[j] && targetsEqual(x, y) {
+				matched[j] = true
+				continue next
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// targetsEqual returns true if a and b are equal. The host names are compared
+// case-insensitively, and without regard to a trailing dot.
+func targetsEqual(a, b Target) bool {
+	return strings.EqualFold(dns.Fqdn(a.Host), dns.Fqdn(b.Host)) &&
+		a.Port == b.Port &&
+		a.Priority == b.Priority &&
+		a.Weight == b.Weight
+}
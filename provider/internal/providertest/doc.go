@@ -1,3 +1,3 @@
 // Package providertest contains a standard test suite for provider
-// implementations.
+// implementations, and a DNS responder for use by fake DNS hosting APIs.
 package providertest
@@ -2,9 +2,13 @@ package providertest
 
 import (
 	"context"
+	"net"
 	"time"
 
 	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/internal/dnssdx"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
 	"golang.org/x/exp/slices"
@@ -14,7 +18,36 @@ func expectInstanceListToEventuallyEqual(
 	ctx context.Context,
 	res *dnssd.UnicastResolver,
 	service, domain string,
-	expect ...dnssd.ServiceInstance,
+	expect ...provider.ServiceInstance,
+) {
+	expectBrowseResultToEventuallyEqual(
+		ctx,
+		func(ctx context.Context) ([]string, error) {
+			return res.EnumerateInstances(ctx, service, domain)
+		},
+		expect,
+	)
+}
+
+func expectSubtypeInstanceListToEventuallyEqual(
+	ctx context.Context,
+	res *dnssd.UnicastResolver,
+	subtype, service, domain string,
+	expect ...provider.ServiceInstance,
+) {
+	expectBrowseResultToEventuallyEqual(
+		ctx,
+		func(ctx context.Context) ([]string, error) {
+			return res.EnumerateInstancesBySubType(ctx, subtype, service, domain)
+		},
+		expect,
+	)
+}
+
+func expectBrowseResultToEventuallyEqual(
+	ctx context.Context,
+	browse func(context.Context) ([]string, error),
+	expect []provider.ServiceInstance,
 ) {
 	ctx, cancel := context.WithTimeout(ctx, convergeTimeout)
 	defer cancel()
@@ -29,17 +62,17 @@ func expectInstanceListToEventuallyEqual(
 	var previous []string
 
 	for {
-		instances, err := res.EnumerateInstances(ctx, service, domain)
+		instances, err := browse(ctx)
 		switch err {
 		case context.DeadlineExceeded:
 			if err == ctx.Err() {
-				gomega.ExpectWithOffset(1, previous).To(
+				gomega.ExpectWithOffset(2, previous).To(
 					gomega.ConsistOf(names),
 					"timed-out waiting for instance list to converge",
 				)
 			}
 		default:
-			gomega.ExpectWithOffset(1, err).ShouldNot(gomega.HaveOccurred())
+			gomega.ExpectWithOffset(2, err).ShouldNot(gomega.HaveOccurred())
 		case nil:
 			slices.Sort(instances)
 			if slices.Equal(instances, names) {
@@ -55,16 +88,17 @@ func expectInstanceListToEventuallyEqual(
 func expectInstanceToEventuallyEqual(
 	ctx context.Context,
 	res *dnssd.UnicastResolver,
-	expect dnssd.ServiceInstance,
+	expect provider.ServiceInstance,
 ) {
 	ctx, cancel := context.WithTimeout(ctx, convergeTimeout)
 	defer cancel()
 
-	var previous dnssd.ServiceInstance
+	var previous provider.ServiceInstance
 
 	for {
-		actual, ok, err := res.LookupInstance(
+		actual, ok, err := dnssdx.LookupInstance(
 			ctx,
+			res,
 			expect.Name,
 			expect.ServiceType,
 			expect.Domain,
@@ -80,6 +114,10 @@ func expectInstanceToEventuallyEqual(
 		default:
 			gomega.ExpectWithOffset(1, err).ShouldNot(gomega.HaveOccurred())
 		case nil:
+			// Subtypes are not discoverable from the instance's own records,
+			// they are checked by browsing each subtype instead.
+			actual.Subtypes = expect.Subtypes
+
 			if ok && actual.Equal(expect) {
 				return
 			}
@@ -93,14 +131,15 @@ func expectInstanceToEventuallyEqual(
 func expectInstanceToEventuallyNotExist(
 	ctx context.Context,
 	res *dnssd.UnicastResolver,
-	expect dnssd.ServiceInstance,
+	expect provider.ServiceInstance,
 ) {
 	ctx, cancel := context.WithTimeout(ctx, convergeTimeout)
 	defer cancel()
 
 	for {
-		_, ok, err := res.LookupInstance(
+		_, ok, err := dnssdx.LookupInstance(
 			ctx,
+			res,
 			expect.Name,
 			expect.ServiceType,
 			expect.Domain,
@@ -121,3 +160,87 @@ func expectInstanceToEventuallyNotExist(
 		time.Sleep(10 * time.Millisecond)
 	}
 }
+
+func expectServiceTypeToEventuallyBeEnumerated(
+	ctx context.Context,
+	res *dnssd.UnicastResolver,
+	service, domain string,
+	expect bool,
+) {
+	ctx, cancel := context.WithTimeout(ctx, convergeTimeout)
+	defer cancel()
+
+	for {
+		serviceTypes, err := res.EnumerateServiceTypes(ctx, domain)
+		switch err {
+		case context.DeadlineExceeded:
+			if err == ctx.Err() {
+				gomega.ExpectWithOffset(1, slices.Contains(serviceTypes, service)).To(
+					gomega.Equal(expect),
+					"timed-out waiting for service type enumeration to converge",
+				)
+			}
+		default:
+			gomega.ExpectWithOffset(1, err).ShouldNot(gomega.HaveOccurred())
+		case nil:
+			if slices.Contains(serviceTypes, service) == expect {
+				return
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func expectPTRTargetsToEventuallyEqual(
+	ctx context.Context,
+	res *dnssd.UnicastResolver,
+	name string,
+	expect ...string,
+) {
+	ctx, cancel := context.WithTimeout(ctx, convergeTimeout)
+	defer cancel()
+
+	for i, target := range expect {
+		expect[i] = dns.Fqdn(target)
+	}
+
+	slices.Sort(expect)
+
+	var (
+		client   dns.Client
+		previous []string
+	)
+
+	for {
+		req := &dns.Msg{}
+		req.SetQuestion(dns.Fqdn(name), dns.TypePTR)
+
+		server := net.JoinHostPort(res.Config.Servers[0], res.Config.Port)
+		r, _, err := client.ExchangeContext(ctx, req, server)
+
+		switch {
+		case ctx.Err() != nil:
+			gomega.ExpectWithOffset(1, previous).To(
+				gomega.ConsistOf(expect),
+				"timed-out waiting for PTR records to converge",
+			)
+			return
+		case err == nil:
+			var targets []string
+			for _, rr := range r.Answer {
+				if ptr, ok := rr.(*dns.PTR); ok {
+					targets = append(targets, ptr.Ptr)
+				}
+			}
+
+			slices.Sort(targets)
+			if slices.Equal(targets, expect) {
+				return
+			}
+			previous = targets
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,160 @@
+package providertest
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/miekg/dns"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+// Responder is a dns.Handler that answers queries authoritatively for the
+// records in a single zone.
+//
+// It allows fake implementations of DNS hosting APIs to serve the records they
+// hold, so that the provider test suite can discover the advertised services.
+type Responder struct {
+	// Zone is the fully-qualified name of the zone. If it is empty, all
+	// queries are refused.
+	Zone string
+
+	// Records returns the records in the zone, including the SOA record at
+	// the zone apex. Nil elements are ignored.
+	//
+	// It is called once for each query, and must be safe for concurrent use.
+	Records func() []dns.RR
+}
+
+// ServeDNS answers the query in req.
+//
+// Responses sent over UDP are truncated to the maximum message size supported
+// by the client.
+func (r *Responder) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	res := r.Respond(req)
+
+	if w.RemoteAddr().Network() == "udp" {
+		size := dns.MinMsgSize
+
+		if opt := req.IsEdns0(); opt != nil {
+			size = int(opt.UDPSize())
+			res.SetEdns0(opt.UDPSize(), false)
+		}
+
+		res.Truncate(size)
+	}
+
+	_ = w.WriteMsg(res)
+}
+
+// Respond returns the response to the query in req.
+func (r *Responder) Respond(req *dns.Msg) *dns.Msg {
+	res := &dns.Msg{}
+	res.SetReply(req)
+
+	if req.Opcode != dns.OpcodeQuery || len(req.Question) != 1 {
+		res.Rcode = dns.RcodeNotImplemented
+		return res
+	}
+
+	q := req.Question[0]
+
+	if r.Zone == "" || !dns.IsSubDomain(r.Zone, q.Name) {
+		res.Rcode = dns.RcodeRefused
+		return res
+	}
+
+	res.Authoritative = true
+
+	exists := false
+	var soa dns.RR
+
+	for _, rr := range r.Records() {
+		if rr == nil {
+			continue
+		}
+
+		h := rr.Header()
+
+		if h.Rrtype == dns.TypeSOA {
+			soa = rr
+		}
+
+		if strings.EqualFold(h.Name, q.Name) {
+			exists = true
+			if h.Rrtype == q.Qtype || q.Qtype == dns.TypeANY {
+				res.Answer = append(res.Answer, dns.Copy(rr))
+			}
+		}
+	}
+
+	if len(res.Answer) == 0 {
+		if soa != nil {
+			res.Ns = append(res.Ns, dns.Copy(soa))
+		}
+
+		if !exists {
+			res.Rcode = dns.RcodeNameError
+		}
+	}
+
+	return res
+}
+
+// StartDNSServer starts UDP and TCP DNS servers that use h to answer queries.
+//
+// Both servers listen on the same random port on the loopback interface, which
+// is returned. If configure is non-nil it is called with each server before it
+// is started. The servers are stopped when the current test ends.
+func StartDNSServer(h dns.Handler, configure func(*dns.Server)) (port string) {
+	listener, conn := listenOnSamePort()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+	for _, srv := range []*dns.Server{
+		{PacketConn: conn},
+		{Listener: listener},
+	} {
+		started := make(chan struct{})
+
+		srv.Handler = h
+		srv.NotifyStartedFunc = func() { close(started) }
+
+		if configure != nil {
+			configure(srv)
+		}
+
+		go srv.ActivateAndServe() //nolint:errcheck
+		<-started
+
+		ginkgo.DeferCleanup(srv.Shutdown)
+	}
+
+	return port
+}
+
+// listenOnSamePort returns a TCP listener and a UDP connection bound to the
+// same random port on the loopback interface.
+//
+// The TCP port is chosen by the operating system. It may already be in use by
+// some other UDP socket, in which case another port is tried.
+func listenOnSamePort() (net.Listener, net.PacketConn) {
+	for attempt := 0; ; attempt++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+		conn, err := net.ListenPacket("udp", listener.Addr().String())
+		if err == nil {
+			return listener, conn
+		}
+
+		listener.Close()
+
+		if !errors.Is(err, syscall.EADDRINUSE) || attempt == 10 {
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		}
+	}
+}
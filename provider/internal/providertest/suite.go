@@ -24,6 +24,28 @@ type TestContext struct {
 	Domain        string
 	NameServers   func(ctx context.Context) ([]string, error)
 	DeleteRecords func(ctx context.Context) error
+
+	// NameServerPort is the port used to query the servers returned by
+	// NameServers. If it is empty, the standard DNS port (53) is used.
+	NameServerPort string
+
+	// MinTTL is the minimum TTL supported by the provider. Instances with a
+	// lower TTL are expected to be advertised with this TTL instead.
+	MinTTL time.Duration
+
+	// OnePTRPerName indicates that the provider can only advertise a single
+	// PTR record with any given name, and hence only one instance of each
+	// service type within a domain.
+	OnePTRPerName bool
+}
+
+// advertised returns inst as it is expected to be resolved once it has been
+// advertised by the provider.
+func (c TestContext) advertised(inst provider.ServiceInstance) provider.ServiceInstance {
+	if inst.TTL < c.MinTTL {
+		inst.TTL = c.MinTTL
+	}
+	return inst
 }
 
 // DeclareTestSuite declares a Ginkgo test suite for a provider implementation.
@@ -55,9 +77,14 @@ func DeclareTestSuite(
 			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
 			gomega.Expect(servers).ShouldNot(gomega.BeEmpty())
 
+			port := tctx.NameServerPort
+			if port == "" {
+				port = "53"
+			}
+
 			resolver = &dnssd.UnicastResolver{
 				Config: &dns.ClientConfig{
-					Port:     "53",
+					Port:     port,
 					Ndots:    1,
 					Timeout:  5,
 					Attempts: 10,
@@ -69,6 +96,18 @@ func DeclareTestSuite(
 			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
 		})
 
+		ginkgo.Describe("func CheckHealth()", func() {
+			ginkgo.It("returns nil when the provider is reachable", func() {
+				p, ok := tctx.Provider.(provider.HealthChecker)
+				if !ok {
+					ginkgo.Skip("provider does not support health checks")
+				}
+
+				err := p.CheckHealth(ctx)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			})
+		})
+
 		ginkgo.When("the provider can not advertise on the domain", func() {
 			ginkgo.Describe("func AdvertiserByDomain()", func() {
 				ginkgo.It("returns false", func() {
@@ -93,42 +132,55 @@ func DeclareTestSuite(
 			})
 
 			ginkgo.It("can advertise and unadvertise instances", func() {
-				expect := []dnssd.ServiceInstance{
+				expect := []provider.ServiceInstance{
 					{
 						Name:        "instance-1",
 						ServiceType: service,
 						Domain:      tctx.Domain,
-						TargetHost:  "host1.example.com",
-						TargetPort:  1000,
-						Priority:    100,
-						Weight:      10,
-						TTL:         1 * time.Second,
+						Targets: []provider.Target{
+							{
+								Host:     "host1.example.com",
+								Port:     1000,
+								Priority: 100,
+								Weight:   10,
+							},
+						},
+						TTL: 1 * time.Second,
 					},
 					{
 						Name:        "instance-2",
 						ServiceType: service,
 						Domain:      tctx.Domain,
-						TargetHost:  "host2.example.com",
-						TargetPort:  2000,
-						Priority:    200,
-						Weight:      20,
-						TTL:         2 * time.Second,
+						Targets: []provider.Target{
+							{
+								Host:     "host2.example.com",
+								Port:     2000,
+								Priority: 200,
+								Weight:   20,
+							},
+						},
+						TTL: 2 * time.Second,
 					},
 				}
 
+				if tctx.OnePTRPerName {
+					expect = expect[:1]
+				}
+
 				for i, inst := range expect {
 					cs, err := advertiser.Advertise(ctx, inst)
 					gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
 					gomega.Expect(cs.IsCreate()).To(gomega.BeTrue())
 
-					expectInstanceToEventuallyEqual(ctx, resolver, inst)
+					expectInstanceToEventuallyEqual(ctx, resolver, tctx.advertised(inst))
 					expectInstanceListToEventuallyEqual(ctx, resolver, service, tctx.Domain, expect[:i+1]...)
+					expectServiceTypeToEventuallyBeEnumerated(ctx, resolver, service, tctx.Domain, true)
 				}
 
 				// Check that all instances still exist after they have all the
 				// advertise calls.
 				for _, inst := range expect {
-					expectInstanceToEventuallyEqual(ctx, resolver, inst)
+					expectInstanceToEventuallyEqual(ctx, resolver, tctx.advertised(inst))
 				}
 
 				expectInstanceListToEventuallyEqual(ctx, resolver, service, tctx.Domain, expect...)
@@ -138,23 +190,31 @@ func DeclareTestSuite(
 					gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
 					gomega.Expect(cs.IsEmpty()).To(gomega.BeFalse())
 
-					expectInstanceToEventuallyEqual(ctx, resolver, inst)
+					expectInstanceToEventuallyNotExist(ctx, resolver, inst)
 					expectInstanceListToEventuallyEqual(ctx, resolver, service, tctx.Domain, expect[i+1:]...)
+
+					// The service type is only removed from the service type
+					// enumeration once its last instance is unadvertised.
+					expectServiceTypeToEventuallyBeEnumerated(ctx, resolver, service, tctx.Domain, i+1 < len(expect))
 				}
 
 				expectInstanceListToEventuallyEqual(ctx, resolver, service, tctx.Domain)
 			})
 
 			ginkgo.It("can update an existing instance", func() {
-				before := dnssd.ServiceInstance{
+				before := provider.ServiceInstance{
 					Name:        "instance",
 					ServiceType: service,
 					Domain:      tctx.Domain,
-					TargetHost:  "host.example.com",
-					TargetPort:  443,
-					Priority:    10,
-					Weight:      20,
-					TTL:         5 * time.Second,
+					Targets: []provider.Target{
+						{
+							Host:     "host.example.com",
+							Port:     443,
+							Priority: 10,
+							Weight:   20,
+						},
+					},
+					TTL: 5 * time.Second,
 					Attributes: []dnssd.Attributes{
 						dnssd.
 							NewAttributes().
@@ -165,15 +225,19 @@ func DeclareTestSuite(
 				_, err := advertiser.Advertise(ctx, before)
 				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
 
-				after := dnssd.ServiceInstance{
+				after := provider.ServiceInstance{
 					Name:        "instance",
 					ServiceType: service,
 					Domain:      tctx.Domain,
-					TargetHost:  "updated.example.com",
-					TargetPort:  444,
-					Priority:    11,
-					Weight:      21,
-					TTL:         6 * time.Second,
+					Targets: []provider.Target{
+						{
+							Host:     "updated.example.com",
+							Port:     444,
+							Priority: 11,
+							Weight:   21,
+						},
+					},
+					TTL: 6 * time.Second,
 					Attributes: []dnssd.Attributes{
 						dnssd.
 							NewAttributes().
@@ -186,19 +250,173 @@ func DeclareTestSuite(
 				gomega.Expect(cs.IsCreate()).To(gomega.BeFalse())
 				gomega.Expect(cs.IsEmpty()).To(gomega.BeFalse())
 
-				expectInstanceToEventuallyEqual(ctx, resolver, after)
+				expectInstanceToEventuallyEqual(ctx, resolver, tctx.advertised(after))
+			})
+
+			ginkgo.It("can advertise an instance with multiple targets", func() {
+				inst := provider.ServiceInstance{
+					Name:        "instance",
+					ServiceType: service,
+					Domain:      tctx.Domain,
+					Targets: []provider.Target{
+						{
+							Host:     "host1.example.com",
+							Port:     443,
+							Priority: 10,
+							Weight:   20,
+						},
+						{
+							Host:     "host2.example.com",
+							Port:     443,
+							Priority: 10,
+							Weight:   30,
+						},
+						{
+							Host:     "host3.example.com",
+							Port:     8443,
+							Priority: 20,
+							Weight:   0,
+						},
+					},
+					TTL: 5 * time.Second,
+				}
+
+				cs, err := advertiser.Advertise(ctx, inst)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(cs.IsCreate()).To(gomega.BeTrue())
+
+				expectInstanceToEventuallyEqual(ctx, resolver, tctx.advertised(inst))
+
+				// Remove a target and re-advertise.
+				inst.Targets = inst.Targets[1:]
+
+				cs, err = advertiser.Advertise(ctx, inst)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(cs.IsCreate()).To(gomega.BeFalse())
+				gomega.Expect(cs.IsEmpty()).To(gomega.BeFalse())
+
+				expectInstanceToEventuallyEqual(ctx, resolver, tctx.advertised(inst))
+
+				cs, err = advertiser.Advertise(ctx, inst)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(cs.IsEmpty()).To(gomega.BeTrue())
+
+				_, err = advertiser.Unadvertise(ctx, inst)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+				expectInstanceToEventuallyNotExist(ctx, resolver, inst)
+			})
+
+			ginkgo.It("can advertise an instance with subtypes", func() {
+				inst := provider.ServiceInstance{
+					Name:        "instance",
+					ServiceType: service,
+					Domain:      tctx.Domain,
+					Subtypes:    []string{"_printer", "_color"},
+					Targets: []provider.Target{
+						{
+							Host:     "host.example.com",
+							Port:     443,
+							Priority: 10,
+							Weight:   20,
+						},
+					},
+					TTL: 5 * time.Second,
+				}
+
+				cs, err := advertiser.Advertise(ctx, inst)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(cs.IsCreate()).To(gomega.BeTrue())
+
+				expectInstanceToEventuallyEqual(ctx, resolver, tctx.advertised(inst))
+				expectSubtypeInstanceListToEventuallyEqual(ctx, resolver, "_printer", service, tctx.Domain, inst)
+				expectSubtypeInstanceListToEventuallyEqual(ctx, resolver, "_color", service, tctx.Domain, inst)
+
+				cs, err = advertiser.Advertise(ctx, inst)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(cs.IsEmpty()).To(gomega.BeTrue())
+
+				_, err = advertiser.Unadvertise(ctx, inst)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+				expectInstanceToEventuallyNotExist(ctx, resolver, inst)
+				expectSubtypeInstanceListToEventuallyEqual(ctx, resolver, "_printer", service, tctx.Domain)
+				expectSubtypeInstanceListToEventuallyEqual(ctx, resolver, "_color", service, tctx.Domain)
+			})
+
+			ginkgo.It("can advertise and unadvertise browse domains", func() {
+				a, ok := advertiser.(provider.BrowseDomainAdvertiser)
+				if !ok {
+					ginkgo.Skip("provider does not support browse domains")
+				}
+
+				d := provider.BrowseDomains{
+					Domain:        tctx.Domain,
+					Browse:        []string{"a." + tctx.Domain, "b." + tctx.Domain},
+					DefaultBrowse: "a." + tctx.Domain,
+					Registration:  []string{"a." + tctx.Domain},
+					TTL:           5 * time.Second,
+				}
+
+				cs, err := a.AdvertiseBrowseDomains(ctx, d)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(cs.IsCreate()).To(gomega.BeTrue())
+
+				expectPTRTargetsToEventuallyEqual(ctx, resolver, "b._dns-sd._udp."+tctx.Domain, "a."+tctx.Domain, "b."+tctx.Domain)
+				expectPTRTargetsToEventuallyEqual(ctx, resolver, "db._dns-sd._udp."+tctx.Domain, "a."+tctx.Domain)
+				expectPTRTargetsToEventuallyEqual(ctx, resolver, "r._dns-sd._udp."+tctx.Domain, "a."+tctx.Domain)
+				expectPTRTargetsToEventuallyEqual(ctx, resolver, "lb._dns-sd._udp."+tctx.Domain)
+
+				cs, err = a.AdvertiseBrowseDomains(ctx, d)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(cs.IsEmpty()).To(gomega.BeTrue())
+
+				d.Browse = []string{"b." + tctx.Domain}
+				d.DefaultBrowse = ""
+				d.LegacyBrowse = []string{"b." + tctx.Domain}
+
+				cs, err = a.AdvertiseBrowseDomains(ctx, d)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(cs.IsEmpty()).To(gomega.BeFalse())
+
+				expectPTRTargetsToEventuallyEqual(ctx, resolver, "b._dns-sd._udp."+tctx.Domain, "b."+tctx.Domain)
+				expectPTRTargetsToEventuallyEqual(ctx, resolver, "db._dns-sd._udp."+tctx.Domain)
+				expectPTRTargetsToEventuallyEqual(ctx, resolver, "lb._dns-sd._udp."+tctx.Domain, "b."+tctx.Domain)
+
+				cs, err = a.UnadvertiseBrowseDomains(ctx, d)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(cs.IsEmpty()).To(gomega.BeFalse())
+
+				expectPTRTargetsToEventuallyEqual(ctx, resolver, "b._dns-sd._udp."+tctx.Domain)
+				expectPTRTargetsToEventuallyEqual(ctx, resolver, "r._dns-sd._udp."+tctx.Domain)
+				expectPTRTargetsToEventuallyEqual(ctx, resolver, "lb._dns-sd._udp."+tctx.Domain)
+			})
+
+			ginkgo.It("reports the zone's negative TTL", func() {
+				a, ok := advertiser.(provider.ZoneTimingAdvertiser)
+				if !ok {
+					ginkgo.Skip("provider does not report zone timing information")
+				}
+
+				t, err := a.ZoneTiming(ctx)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(t.NegativeTTL).To(gomega.BeNumerically(">", 0))
 			})
 
 			ginkgo.It("ignores an existing identical instance", func() {
-				expect := dnssd.ServiceInstance{
+				expect := provider.ServiceInstance{
 					Name:        "instance",
 					ServiceType: service,
 					Domain:      tctx.Domain,
-					TargetHost:  "host.example.com",
-					TargetPort:  443,
-					Priority:    10,
-					Weight:      20,
-					TTL:         5 * time.Second,
+					Targets: []provider.Target{
+						{
+							Host:     "host.example.com",
+							Port:     443,
+							Priority: 10,
+							Weight:   20,
+						},
+					},
+					TTL: 5 * time.Second,
 					Attributes: []dnssd.Attributes{
 						dnssd.
 							NewAttributes().
@@ -213,19 +431,23 @@ func DeclareTestSuite(
 				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
 				gomega.Expect(cs.IsEmpty()).To(gomega.BeTrue())
 
-				expectInstanceToEventuallyEqual(ctx, resolver, expect)
+				expectInstanceToEventuallyEqual(ctx, resolver, tctx.advertised(expect))
 			})
 
 			ginkgo.It("does not fail when unadvertising a non-existent instance", func() {
-				inst := dnssd.ServiceInstance{
+				inst := provider.ServiceInstance{
 					Name:        "instance",
 					ServiceType: service,
 					Domain:      tctx.Domain,
-					TargetHost:  "host.example.com",
-					TargetPort:  443,
-					Priority:    10,
-					Weight:      20,
-					TTL:         5 * time.Second,
+					Targets: []provider.Target{
+						{
+							Host:     "host.example.com",
+							Port:     443,
+							Priority: 10,
+							Weight:   20,
+						},
+					},
+					TTL: 5 * time.Second,
 					Attributes: []dnssd.Attributes{
 						dnssd.
 							NewAttributes().
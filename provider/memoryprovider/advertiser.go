@@ -0,0 +1,256 @@
+package memoryprovider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	"golang.org/x/exp/slices"
+)
+
+type advertiser struct {
+	Provider *Provider
+	Domain   string
+	Logger   logr.Logger
+}
+
+func (a *advertiser) ID() map[string]any {
+	return marshalAdvertiserID(a.Domain)
+}
+
+func (a *advertiser) Advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	a.Provider.m.Lock()
+	defer a.Provider.m.Unlock()
+
+	var srv, txt []dns.RR
+
+	for _, rr := range provider.NewSRVRecords(inst) {
+		srv = append(srv, rr)
+	}
+	for _, rr := range provider.NewTXTRecords(inst) {
+		txt = append(txt, rr)
+	}
+
+	ptr := a.add(typeEnumerationName(inst), provider.NewServiceTypePTRRecord(inst))
+	ptr |= a.add(serviceName(inst), provider.NewPTRRecord(inst))
+
+	for _, rr := range provider.NewSubtypePTRRecords(inst) {
+		ptr |= a.add(rr.Hdr.Name, rr)
+	}
+
+	// Remove the instance from any subtypes that it no longer provides.
+	for _, name := range a.subtypeNames(inst) {
+		if !slices.ContainsFunc(
+			inst.Subtypes,
+			func(st string) bool {
+				return name == subtypeName(inst, st)
+			},
+		) {
+			ptr |= a.remove(name, subtypePTRRecord(inst, name))
+		}
+	}
+
+	return provider.ChangeSet{
+		PTR: ptr,
+		SRV: a.replace(instanceName(inst), dns.TypeSRV, srv),
+		TXT: a.replace(instanceName(inst), dns.TypeTXT, txt),
+	}, nil
+}
+
+func (a *advertiser) Unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	a.Provider.m.Lock()
+	defer a.Provider.m.Unlock()
+
+	ptr := a.remove(serviceName(inst), provider.NewPTRRecord(inst))
+
+	// If this was the last instance of its service type, the service type
+	// itself is no longer advertised.
+	if _, ok := a.Provider.records[recordKey{serviceName(inst), dns.TypePTR}]; !ok {
+		ptr |= a.remove(typeEnumerationName(inst), provider.NewServiceTypePTRRecord(inst))
+	}
+
+	for _, name := range a.subtypeNames(inst) {
+		ptr |= a.remove(name, subtypePTRRecord(inst, name))
+	}
+
+	return provider.ChangeSet{
+		PTR: ptr,
+		SRV: a.replace(instanceName(inst), dns.TypeSRV, nil),
+		TXT: a.replace(instanceName(inst), dns.TypeTXT, nil),
+	}, nil
+}
+
+// add adds rr to the record set with the given name, unless an equivalent
+// record is already present.
+//
+// The record set may be shared with other service instances, so its other
+// records are left untouched.
+func (a *advertiser) add(name string, rr dns.RR) provider.Change {
+	k := recordKey{strings.ToLower(name), rr.Header().Rrtype}
+	current := a.Provider.records[k]
+
+	for _, x := range current {
+		if dns.IsDuplicate(x, rr) {
+			return provider.NoChange
+		}
+	}
+
+	if a.Provider.records == nil {
+		a.Provider.records = map[recordKey][]dns.RR{}
+	}
+
+	a.Provider.records[k] = append(current, rr)
+	a.log("CREATE record", rr)
+
+	return provider.Created
+}
+
+// remove removes rr from the record set with the given name, leaving any other
+// records in the set untouched.
+func (a *advertiser) remove(name string, rr dns.RR) provider.Change {
+	k := recordKey{strings.ToLower(name), rr.Header().Rrtype}
+	current := a.Provider.records[k]
+
+	for i, x := range current {
+		if dns.IsDuplicate(x, rr) {
+			a.log("DELETE record", x)
+
+			if len(current) == 1 {
+				delete(a.Provider.records, k)
+			} else {
+				a.Provider.records[k] = append(current[:i:i], current[i+1:]...)
+			}
+
+			return provider.Deleted
+		}
+	}
+
+	return provider.NoChange
+}
+
+// replace replaces the entire record set with the given name and type with
+// the desired records. If desired is empty, the record set is deleted.
+func (a *advertiser) replace(
+	name string,
+	recordType uint16,
+	desired []dns.RR,
+) provider.Change {
+	k := recordKey{strings.ToLower(name), recordType}
+	current := a.Provider.records[k]
+
+	if recordSetsEqual(current, desired) {
+		return provider.NoChange
+	}
+
+	for _, rr := range current {
+		a.log("DELETE record", rr)
+	}
+
+	for _, rr := range desired {
+		a.log("CREATE record", rr)
+	}
+
+	if len(desired) == 0 {
+		delete(a.Provider.records, k)
+		return provider.Deleted
+	}
+
+	if a.Provider.records == nil {
+		a.Provider.records = map[recordKey][]dns.RR{}
+	}
+
+	a.Provider.records[k] = desired
+
+	if len(current) == 0 {
+		return provider.Created
+	}
+
+	return provider.Updated
+}
+
+// subtypeNames returns the names of all of the subtype PTR record sets for the
+// instance's service type, regardless of which subtypes the instance provides.
+func (a *advertiser) subtypeNames(inst provider.ServiceInstance) []string {
+	suffix := "._sub." + serviceName(inst)
+
+	var names []string
+	for k := range a.Provider.records {
+		if k.Type == dns.TypePTR && strings.HasSuffix(k.Name, suffix) {
+			names = append(names, k.Name)
+		}
+	}
+
+	return names
+}
+
+// subtypePTRRecord returns the PTR record that advertises inst within the
+// subtype PTR record set with the given name.
+func subtypePTRRecord(inst provider.ServiceInstance, name string) *dns.PTR {
+	rr := provider.NewPTRRecord(inst)
+	rr.Hdr.Name = name
+	return rr
+}
+
+func (a *advertiser) log(msg string, rr dns.RR) {
+	a.Logger.Info(
+		msg,
+		"type", dns.TypeToString[rr.Header().Rrtype],
+		"name", rr.Header().Name,
+		"content", strings.TrimPrefix(rr.String(), rr.Header().String()),
+		"ttl", rr.Header().Ttl,
+	)
+}
+
+// recordSetsEqual returns true if a and b contain the same records with the
+// same TTLs, regardless of order.
+func recordSetsEqual(a, b []dns.RR) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+next:
+	for _, x := range a {
+		for _, y := range b {
+			if dns.IsDuplicate(x, y) && x.Header().Ttl == y.Header().Ttl {
+				continue next
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func instanceName(inst provider.ServiceInstance) string {
+	return strings.ToLower(
+		dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, inst.Domain) + ".",
+	)
+}
+
+func subtypeName(inst provider.ServiceInstance, subtype string) string {
+	return strings.ToLower(
+		dnssd.SelectiveInstanceEnumerationDomain(subtype, inst.ServiceType, inst.Domain) + ".",
+	)
+}
+
+func typeEnumerationName(inst provider.ServiceInstance) string {
+	return strings.ToLower(
+		dnssd.TypeEnumerationDomain(inst.Domain) + ".",
+	)
+}
+
+func serviceName(inst provider.ServiceInstance) string {
+	return strings.ToLower(
+		dnssd.InstanceEnumerationDomain(inst.ServiceType, inst.Domain) + ".",
+	)
+}
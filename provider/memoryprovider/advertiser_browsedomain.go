@@ -0,0 +1,45 @@
+package memoryprovider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+func (a *advertiser) AdvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	a.Provider.m.Lock()
+	defer a.Provider.m.Unlock()
+
+	var ptr provider.Change
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		var desired []dns.RR
+		for _, rr := range set.Records {
+			desired = append(desired, rr)
+		}
+
+		ptr |= a.replace(set.Name, dns.TypePTR, desired)
+	}
+
+	return provider.ChangeSet{PTR: ptr}, nil
+}
+
+func (a *advertiser) UnadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	a.Provider.m.Lock()
+	defer a.Provider.m.Unlock()
+
+	var ptr provider.Change
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		ptr |= a.replace(set.Name, dns.TypePTR, nil)
+	}
+
+	return provider.ChangeSet{PTR: ptr}, nil
+}
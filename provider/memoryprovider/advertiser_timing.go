@@ -0,0 +1,16 @@
+package memoryprovider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+func (a *advertiser) ZoneTiming(ctx context.Context) (provider.ZoneTiming, error) {
+	return provider.ZoneTiming{
+		NegativeTTL: provider.NegativeTTL(
+			soa(dns.Fqdn(a.Domain)),
+		),
+	}, nil
+}
@@ -0,0 +1,7 @@
+// Package memoryprovider provides a driver implementation that advertises
+// DNS-SD service instances on domain names hosted entirely in memory.
+//
+// The records are served by an embedded DNS server, allowing them to be
+// discovered using regular DNS-SD queries. It is intended for use in tests and
+// during local development.
+package memoryprovider
@@ -0,0 +1,8 @@
+package memoryprovider
+
+import "context"
+
+// CheckHealth always returns nil, the in-memory provider is always reachable.
+func (p *Provider) CheckHealth(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,138 @@
+package memoryprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	"golang.org/x/exp/slices"
+)
+
+// Provider is an implementation of provider.Provider that advertises DNS-SD
+// services on domains that are hosted in memory.
+type Provider struct {
+	// Domains is the list of domains that are hosted by the provider. Each
+	// domain is the apex of its own zone.
+	Domains []string
+
+	Logger logr.Logger
+
+	m       sync.RWMutex
+	records map[recordKey][]dns.RR
+}
+
+// recordKey identifies a record set.
+type recordKey struct {
+	Name string
+	Type uint16
+}
+
+// ID returns a short unique identifier for the provider.
+func (p *Provider) ID() string {
+	return "memory"
+}
+
+// Describe returns a human-readable description of the provider.
+func (p *Provider) Describe() string {
+	return "In-memory"
+}
+
+// AdvertiserByID returns the Advertiser with the given ID.
+func (p *Provider) AdvertiserByID(
+	ctx context.Context,
+	id map[string]any,
+) (provider.Advertiser, error) {
+	domain, err := unmarshalAdvertiserID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.hostsDomain(domain) {
+		return nil, fmt.Errorf("the %q domain is not hosted by this provider", domain)
+	}
+
+	return &advertiser{
+		p,
+		domain,
+		p.Logger,
+	}, nil
+}
+
+// AdvertiserByDomain returns the Advertiser used to advertise services on
+// the given domain.
+//
+// ok is false if this provider does not manage the given domain.
+func (p *Provider) AdvertiserByDomain(
+	ctx context.Context,
+	domain string,
+) (provider.Advertiser, bool, error) {
+	if !p.hostsDomain(domain) {
+		return nil, false, nil
+	}
+
+	return &advertiser{
+		p,
+		domain,
+		p.Logger,
+	}, true, nil
+}
+
+// DeleteRecords removes all records from all of the provider's domains.
+func (p *Provider) DeleteRecords() {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.records = nil
+}
+
+// hostsDomain returns true if the given domain is the apex of one of the
+// provider's zones.
+func (p *Provider) hostsDomain(domain string) bool {
+	return slices.ContainsFunc(
+		p.Domains,
+		func(d string) bool {
+			return strings.EqualFold(dns.Fqdn(d), dns.Fqdn(domain))
+		},
+	)
+}
+
+// zoneFor returns the apex of the zone that contains the given name.
+//
+// ok is false if the name is not within any of the provider's zones.
+func (p *Provider) zoneFor(name string) (zone string, ok bool) {
+	for _, d := range p.Domains {
+		d = dns.Fqdn(d)
+		if dns.IsSubDomain(d, name) && len(d) > len(zone) {
+			zone, ok = d, true
+		}
+	}
+
+	return zone, ok
+}
+
+// marshalAdvertiserID returns the ID of the advertiser for the given domain.
+func marshalAdvertiserID(domain string) map[string]any {
+	return map[string]any{
+		"domain": domain,
+	}
+}
+
+// unmarshalAdvertiserID parses an advertiser ID into its constituent parts.
+func unmarshalAdvertiserID(id map[string]any) (domain string, err error) {
+	domainAny, ok := id["domain"]
+	if !ok {
+		return "", errors.New("invalid advertiser ID: missing domain key")
+	}
+
+	domain, ok = domainAny.(string)
+	if !ok || domain == "" {
+		return "", errors.New("invalid advertiser ID: domain must be a non-empty string")
+	}
+
+	return domain, nil
+}
@@ -0,0 +1,46 @@
+package memoryprovider_test
+
+import (
+	"context"
+	"net"
+
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	. "github.com/dogmatiq/proclaim/provider/memoryprovider"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const domain = "proclaim-test.example.org"
+
+var _ = Describe("type Provider", func() {
+	providertest.DeclareTestSuite(
+		func(ctx context.Context) providertest.TestContext {
+			p := &Provider{
+				Domains: []string{domain},
+				Logger:  logr.Discard(),
+			}
+
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			go p.Serve(ctx, conn) //nolint:errcheck
+
+			_, port, err := net.SplitHostPort(conn.LocalAddr().String())
+			Expect(err).ShouldNot(HaveOccurred())
+
+			return providertest.TestContext{
+				Provider: p,
+				Domain:   domain,
+				NameServers: func(ctx context.Context) ([]string, error) {
+					return []string{"127.0.0.1"}, nil
+				},
+				NameServerPort: port,
+				DeleteRecords: func(ctx context.Context) error {
+					p.DeleteRecords()
+					return nil
+				},
+			}
+		},
+	)
+})
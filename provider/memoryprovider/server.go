@@ -0,0 +1,89 @@
+package memoryprovider
+
+import (
+	"context"
+	"net"
+
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	"github.com/miekg/dns"
+)
+
+// negativeTTL is the TTL used for negative responses, as per the "minimum"
+// field of each zone's SOA record.
+const negativeTTL = 1
+
+// Serve answers DNS queries for the provider's zones using conn until ctx is
+// canceled.
+func (p *Provider) Serve(ctx context.Context, conn net.PacketConn) error {
+	started := make(chan struct{})
+	result := make(chan error, 1)
+
+	srv := &dns.Server{
+		PacketConn:        conn,
+		Handler:           p,
+		NotifyStartedFunc: func() { close(started) },
+	}
+
+	go func() {
+		result <- srv.ActivateAndServe()
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-started:
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		if err := srv.Shutdown(); err != nil {
+			return err
+		}
+		<-result
+		return ctx.Err()
+	}
+}
+
+// ServeDNS answers a DNS query.
+func (p *Provider) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	r := &providertest.Responder{}
+
+	if len(req.Question) == 1 {
+		r.Zone, _ = p.zoneFor(req.Question[0].Name)
+	}
+
+	r.Records = func() []dns.RR {
+		p.m.RLock()
+		defer p.m.RUnlock()
+
+		records := []dns.RR{soa(r.Zone)}
+		for _, rr := range p.records {
+			records = append(records, rr...)
+		}
+
+		return records
+	}
+
+	r.ServeDNS(w, req)
+}
+
+// soa returns the SOA record for the given zone.
+func soa(zone string) *dns.SOA {
+	return &dns.SOA{
+		Hdr: dns.RR_Header{
+			Name:   zone,
+			Rrtype: dns.TypeSOA,
+			Class:  dns.ClassINET,
+			Ttl:    negativeTTL,
+		},
+		Ns:      "ns." + zone,
+		Mbox:    "hostmaster." + zone,
+		Serial:  1,
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		Minttl:  negativeTTL,
+	}
+}
@@ -0,0 +1,29 @@
+package provider
+
+import "fmt"
+
+// NamedID returns the ID of a provider instance with the given name.
+//
+// id is the ID derived from the provider's type and configuration. The name
+// distinguishes between multiple instances of the same provider type that
+// would otherwise have the same ID, such as those that use different accounts
+// with the same DNS service.
+//
+// It returns id unchanged if name is empty.
+func NamedID(id, name string) string {
+	if name == "" {
+		return id
+	}
+	return id + ":" + name
+}
+
+// NamedDescription returns the human-readable description of a provider
+// instance with the given name.
+//
+// It returns desc unchanged if name is empty.
+func NamedDescription(desc, name string) string {
+	if name == "" {
+		return desc
+	}
+	return fmt.Sprintf("%s [%s]", desc, name)
+}
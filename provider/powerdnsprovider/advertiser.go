@@ -0,0 +1,221 @@
+package powerdnsprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/powerdnsprovider/internal/powerdnsapi"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+)
+
+// advertiser is an implementation of provider.Advertiser that advertises
+// DNS-SD service instances on a single PowerDNS zone.
+//
+// Each operation fetches a snapshot of all of the zone's RRsets, computes the
+// changes necessary to reach the desired state, then applies them in a single
+// atomic PATCH request. Fetching the entire zone is necessary to find the
+// subtype PTR RRsets that refer to the instance.
+//
+// The PowerDNS API does not support conditional updates, so the PTR RRsets
+// that are shared between service instances may be modified by another
+// controller between fetching the snapshot and applying the changes. To
+// account for this, each operation fetches a new snapshot after applying its
+// changes, and applies any changes that were overwritten again.
+//
+// In particular, this makes removing the last instance of a service type safe
+// when another controller concurrently advertises an instance of the same
+// type. Each controller checks the other's RRset only after applying its own
+// changes, so at least one of them sees both changes and restores the service
+// type's PTR record.
+type advertiser struct {
+	Client *powerdnsapi.Client
+	Zone   powerdnsapi.Zone
+	Logger logr.Logger
+}
+
+func (a *advertiser) ID() map[string]any {
+	return marshalAdvertiserID(a.Zone)
+}
+
+func (a *advertiser) Advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	return a.reconcile(
+		ctx,
+		func(cs *changeSet) {
+			a.syncServiceTypePTR(inst, cs)
+			a.syncPTR(inst, cs)
+			a.syncSubtypePTRs(inst, cs)
+			a.syncSRV(inst, cs)
+			a.syncTXT(inst, cs)
+		},
+	)
+}
+
+func (a *advertiser) Unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	return a.reconcile(
+		ctx,
+		func(cs *changeSet) {
+			a.deletePTR(inst, cs)
+			a.deleteSubtypePTRs(inst, cs)
+			a.deleteSRV(inst, cs)
+			a.deleteTXT(inst, cs)
+			a.restoreServiceTypePTR(inst, cs)
+		},
+	)
+}
+
+// maxAttempts is the maximum number of times that an operation applies its
+// changes before giving up on reaching a state that is not immediately
+// overwritten by another controller.
+const maxAttempts = 5
+
+// reconcile calls fn to make changes to a snapshot of the zone, then applies
+// those changes.
+//
+// It repeats this process with a new snapshot until fn makes no further
+// changes, such that any changes that were overwritten by a concurrent update
+// are applied again.
+func (a *advertiser) reconcile(
+	ctx context.Context,
+	fn func(cs *changeSet),
+) (provider.ChangeSet, error) {
+	var result provider.ChangeSet
+
+	for attempt := 1; ; attempt++ {
+		cs, err := a.snapshot(ctx)
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		fn(cs)
+
+		r, err := a.apply(ctx, cs)
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		if r.IsEmpty() {
+			return result, nil
+		}
+
+		if attempt == maxAttempts {
+			return provider.ChangeSet{}, fmt.Errorf(
+				"changes were overwritten by concurrent updates %d times in a row",
+				maxAttempts,
+			)
+		}
+
+		result.PTR |= r.PTR
+		result.SRV |= r.SRV
+		result.TXT |= r.TXT
+	}
+}
+
+// snapshot returns an empty change set for the zone's current RRsets.
+func (a *advertiser) snapshot(ctx context.Context) (*changeSet, error) {
+	zone, err := a.Client.GetZone(ctx, a.Zone.ID, true)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get zone: %w", err)
+	}
+
+	return newChangeSet(zone.RRsets), nil
+}
+
+func (a *advertiser) apply(
+	ctx context.Context,
+	cs *changeSet,
+) (provider.ChangeSet, error) {
+	var (
+		result provider.ChangeSet
+		rrsets []powerdnsapi.RRset
+	)
+
+	cs.Each(func(change provider.Change, before, after powerdnsapi.RRset) {
+		switch before.Type + after.Type {
+		case "PTR", "PTRPTR":
+			result.PTR |= change
+		case "SRV", "SRVSRV":
+			result.SRV |= change
+		case "TXT", "TXTTXT":
+			result.TXT |= change
+		}
+
+		if change == provider.Deleted {
+			rrsets = append(rrsets, powerdnsapi.RRset{
+				Name:       before.Name,
+				Type:       before.Type,
+				ChangeType: powerdnsapi.ChangeTypeDelete,
+			})
+		} else {
+			after.ChangeType = powerdnsapi.ChangeTypeReplace
+			rrsets = append(rrsets, after)
+		}
+	})
+
+	if len(rrsets) == 0 {
+		return provider.ChangeSet{}, nil
+	}
+
+	if err := a.Client.PatchZone(ctx, a.Zone.ID, rrsets); err != nil {
+		return provider.ChangeSet{}, fmt.Errorf("unable to patch zone: %w", err)
+	}
+
+	cs.Each(func(_ provider.Change, before, after powerdnsapi.RRset) {
+		a.log("DELETE", before)
+		a.log("CREATE", after)
+	})
+
+	return result, nil
+}
+
+func (a *advertiser) log(action string, set powerdnsapi.RRset) {
+	for _, r := range set.Records {
+		a.Logger.Info(
+			action+" record",
+			"type", set.Type,
+			"name", set.Name,
+			"value", r.Content,
+			"ttl", set.TTL,
+		)
+	}
+}
+
+func instanceName(inst provider.ServiceInstance) string {
+	return dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, inst.Domain) + "."
+}
+
+func serviceName(inst provider.ServiceInstance) string {
+	return dnssd.InstanceEnumerationDomain(inst.ServiceType, inst.Domain) + "."
+}
+
+func convertRecords[
+	R interface {
+		Header() *dns.RR_Header
+		String() string
+	},
+](records ...R) []powerdnsapi.Record {
+	var result []powerdnsapi.Record
+
+	for _, rec := range records {
+		result = append(
+			result,
+			powerdnsapi.Record{
+				Content: strings.TrimPrefix(
+					rec.String(),
+					rec.Header().String(),
+				),
+			},
+		)
+	}
+
+	return result
+}
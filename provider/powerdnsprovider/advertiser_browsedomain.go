@@ -0,0 +1,50 @@
+package powerdnsprovider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/powerdnsprovider/internal/powerdnsapi"
+)
+
+func (a *advertiser) AdvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs, err := a.snapshot(ctx)
+	if err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		desired := powerdnsapi.RRset{
+			Name:    set.Name,
+			Type:    "PTR",
+			Records: convertRecords(set.Records...),
+		}
+
+		if len(set.Records) != 0 {
+			desired.TTL = set.Records[0].Hdr.Ttl
+		}
+
+		cs.Replace(desired)
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) UnadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs, err := a.snapshot(ctx)
+	if err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		cs.Delete(set.Name, "PTR")
+	}
+
+	return a.apply(ctx, cs)
+}
@@ -0,0 +1,101 @@
+package powerdnsprovider
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/powerdnsprovider/internal/powerdnsapi"
+	"golang.org/x/exp/slices"
+)
+
+// ptrTTL is the TTL of PTR records that enumerate service instances.
+//
+// Normally we'd use each service's TTL for its respective PTR record, but
+// PowerDNS stores all records with the same name and type in a single RRset,
+// which means they all share a TTL.
+const ptrTTL = 30 * time.Second
+
+func (a *advertiser) syncPTR(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) {
+	addToPTRSet(serviceName(inst), instanceName(inst), cs)
+}
+
+func (a *advertiser) deletePTR(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) {
+	current, ok := cs.Find(serviceName(inst), "PTR")
+	if !ok {
+		return
+	}
+
+	index := indexOf(current, instanceName(inst))
+	if index == -1 {
+		return
+	}
+
+	removeFromPTRSet(current, index, cs)
+
+	// If this is the last instance of its service type, the service type
+	// itself is no longer advertised.
+	if len(current.Records) == 1 {
+		a.deleteServiceTypePTR(inst, cs)
+	}
+}
+
+// addToPTRSet adds a PTR record that refers to the target name to the shared
+// PTR RRset with the given name.
+func addToPTRSet(name, target string, cs *changeSet) {
+	desired := powerdnsapi.RRset{
+		Name: name,
+		Type: "PTR",
+		TTL:  uint32(ptrTTL.Seconds()),
+		Records: []powerdnsapi.Record{
+			{Content: target},
+		},
+	}
+
+	if current, ok := cs.Find(name, "PTR"); ok {
+		if indexOf(current, target) != -1 {
+			return
+		}
+
+		desired.Records = append(desired.Records, current.Records...)
+	}
+
+	cs.Replace(desired)
+}
+
+// removeFromPTRSet removes the PTR record at the given index from the given
+// shared PTR RRset.
+func removeFromPTRSet(
+	current powerdnsapi.RRset,
+	index int,
+	cs *changeSet,
+) {
+	cs.Replace(powerdnsapi.RRset{
+		Name: current.Name,
+		Type: "PTR",
+		TTL:  uint32(ptrTTL.Seconds()),
+		Records: slices.Delete(
+			slices.Clone(current.Records),
+			index,
+			index+1,
+		),
+	})
+}
+
+// indexOf returns the index of the PTR record that refers to the target name
+// in a PTR RRset, or -1 if it is not present.
+func indexOf(set powerdnsapi.RRset, target string) int {
+	for i, r := range set.Records {
+		if strings.EqualFold(r.Content, target) {
+			return i
+		}
+	}
+
+	return -1
+}
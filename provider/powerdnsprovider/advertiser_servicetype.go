@@ -0,0 +1,55 @@
+package powerdnsprovider
+
+import (
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+)
+
+// syncServiceTypePTR adds the instance's service type to the shared PTR RRset
+// that enumerates the service types within the domain.
+func (a *advertiser) syncServiceTypePTR(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) {
+	addToPTRSet(typeEnumerationName(inst), serviceName(inst), cs)
+}
+
+// deleteServiceTypePTR removes the instance's service type from the shared PTR
+// RRset that enumerates the service types within the domain.
+//
+// It must only be called when the instance is the last instance of its service
+// type.
+func (a *advertiser) deleteServiceTypePTR(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) {
+	current, ok := cs.Find(typeEnumerationName(inst), "PTR")
+	if !ok {
+		return
+	}
+
+	if index := indexOf(current, serviceName(inst)); index != -1 {
+		removeFromPTRSet(current, index, cs)
+	}
+}
+
+// restoreServiceTypePTR adds the instance's service type to the shared PTR
+// RRset that enumerates the service types within the domain if any other
+// instances of the same service type are still advertised.
+//
+// It is called after removing the instance so that if another controller
+// advertises an instance of the same service type while the last instance is
+// being removed, the service type is restored when the advertiser applies its
+// changes again.
+func (a *advertiser) restoreServiceTypePTR(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) {
+	if _, ok := cs.Find(serviceName(inst), "PTR"); ok {
+		a.syncServiceTypePTR(inst, cs)
+	}
+}
+
+func typeEnumerationName(inst provider.ServiceInstance) string {
+	return dnssd.TypeEnumerationDomain(inst.Domain) + "."
+}
@@ -0,0 +1,25 @@
+package powerdnsprovider
+
+import (
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/powerdnsprovider/internal/powerdnsapi"
+)
+
+func (a *advertiser) syncSRV(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) {
+	cs.Replace(powerdnsapi.RRset{
+		Name:    instanceName(inst),
+		Type:    "SRV",
+		TTL:     uint32(inst.TTL.Seconds()),
+		Records: convertRecords(provider.NewSRVRecords(inst)...),
+	})
+}
+
+func (a *advertiser) deleteSRV(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) {
+	cs.Delete(instanceName(inst), "SRV")
+}
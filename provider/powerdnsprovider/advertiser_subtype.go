@@ -0,0 +1,71 @@
+package powerdnsprovider
+
+import (
+	"strings"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/powerdnsprovider/internal/powerdnsapi"
+	"golang.org/x/exp/slices"
+)
+
+// findSubtypePTRs returns all of the subtype PTR RRsets for the instance's
+// service type, regardless of which subtypes the instance provides.
+func findSubtypePTRs(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) []powerdnsapi.RRset {
+	parent := "._sub." + serviceName(inst)
+
+	return cs.FindAll(
+		"PTR",
+		func(name string) bool {
+			return hasSuffixFold(name, parent)
+		},
+	)
+}
+
+func (a *advertiser) syncSubtypePTRs(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) {
+	for _, st := range inst.Subtypes {
+		addToPTRSet(subtypeName(inst, st), instanceName(inst), cs)
+	}
+
+	// Remove the instance from any subtypes that it no longer provides.
+	for _, set := range findSubtypePTRs(inst, cs) {
+		if slices.ContainsFunc(
+			inst.Subtypes,
+			func(st string) bool {
+				return strings.EqualFold(set.Name, subtypeName(inst, st))
+			},
+		) {
+			continue
+		}
+
+		if index := indexOf(set, instanceName(inst)); index != -1 {
+			removeFromPTRSet(set, index, cs)
+		}
+	}
+}
+
+func (a *advertiser) deleteSubtypePTRs(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) {
+	for _, set := range findSubtypePTRs(inst, cs) {
+		if index := indexOf(set, instanceName(inst)); index != -1 {
+			removeFromPTRSet(set, index, cs)
+		}
+	}
+}
+
+func subtypeName(inst provider.ServiceInstance, subtype string) string {
+	return dnssd.SelectiveInstanceEnumerationDomain(subtype, inst.ServiceType, inst.Domain) + "."
+}
+
+func hasSuffixFold(s, suffix string) bool {
+	return len(s) >= len(suffix) &&
+		strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
@@ -0,0 +1,42 @@
+package powerdnsprovider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+func (a *advertiser) ZoneTiming(ctx context.Context) (provider.ZoneTiming, error) {
+	cs, err := a.snapshot(ctx)
+	if err != nil {
+		return provider.ZoneTiming{}, err
+	}
+
+	set, ok := cs.Find(a.Zone.Name, "SOA")
+	if !ok || len(set.Records) == 0 {
+		return provider.ZoneTiming{}, nil
+	}
+
+	rr, err := dns.NewRR(
+		fmt.Sprintf(
+			"%s %d IN SOA %s",
+			set.Name,
+			set.TTL,
+			set.Records[0].Content,
+		),
+	)
+	if err != nil {
+		return provider.ZoneTiming{}, fmt.Errorf("unable to parse SOA record: %w", err)
+	}
+
+	soa, ok := rr.(*dns.SOA)
+	if !ok {
+		return provider.ZoneTiming{}, nil
+	}
+
+	return provider.ZoneTiming{
+		NegativeTTL: provider.NegativeTTL(soa),
+	}, nil
+}
@@ -0,0 +1,25 @@
+package powerdnsprovider
+
+import (
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/powerdnsprovider/internal/powerdnsapi"
+)
+
+func (a *advertiser) syncTXT(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) {
+	cs.Replace(powerdnsapi.RRset{
+		Name:    instanceName(inst),
+		Type:    "TXT",
+		TTL:     uint32(inst.TTL.Seconds()),
+		Records: convertRecords(provider.NewTXTRecords(inst)...),
+	})
+}
+
+func (a *advertiser) deleteTXT(
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) {
+	cs.Delete(instanceName(inst), "TXT")
+}
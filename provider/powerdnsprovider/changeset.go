@@ -0,0 +1,138 @@
+package powerdnsprovider
+
+import (
+	"strings"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/powerdnsprovider/internal/powerdnsapi"
+	"golang.org/x/exp/slices"
+)
+
+// changeSet encapsulates a set of RRset changes that must be applied to
+// reconcile the DNS zone with the desired state.
+//
+// Changes are made against a snapshot of the zone's RRsets, such that several
+// changes to the same RRset are combined into a single change.
+type changeSet struct {
+	original map[rrsetKey]powerdnsapi.RRset
+	current  map[rrsetKey]powerdnsapi.RRset
+	changed  []rrsetKey
+}
+
+// rrsetKey uniquely identifies an RRset within a zone.
+type rrsetKey struct {
+	Name, Type string
+}
+
+func keyOf(name, recordType string) rrsetKey {
+	return rrsetKey{strings.ToLower(name), recordType}
+}
+
+// newChangeSet returns an empty change set for a zone that contains the given
+// RRsets.
+func newChangeSet(rrsets []powerdnsapi.RRset) *changeSet {
+	cs := &changeSet{
+		original: map[rrsetKey]powerdnsapi.RRset{},
+		current:  map[rrsetKey]powerdnsapi.RRset{},
+	}
+
+	for _, set := range rrsets {
+		k := keyOf(set.Name, set.Type)
+		cs.original[k] = set
+		cs.current[k] = set
+	}
+
+	return cs
+}
+
+// Find returns the RRset with the given fully-qualified name and type,
+// including any changes already made within the change set.
+func (cs *changeSet) Find(name, recordType string) (powerdnsapi.RRset, bool) {
+	set, ok := cs.current[keyOf(name, recordType)]
+	return set, ok
+}
+
+// FindAll returns the RRsets of the given type with names that satisfy the
+// given predicate, including any changes already made within the change set.
+func (cs *changeSet) FindAll(
+	recordType string,
+	pred func(name string) bool,
+) []powerdnsapi.RRset {
+	var sets []powerdnsapi.RRset
+
+	for k, set := range cs.current {
+		if k.Type == recordType && pred(set.Name) {
+			sets = append(sets, set)
+		}
+	}
+
+	slices.SortFunc(
+		sets,
+		func(a, b powerdnsapi.RRset) bool {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		},
+	)
+
+	return sets
+}
+
+// Replace replaces the RRset with the same name and type as set. The RRset is
+// deleted if set does not contain any records.
+func (cs *changeSet) Replace(set powerdnsapi.RRset) {
+	k := keyOf(set.Name, set.Type)
+
+	if len(set.Records) == 0 {
+		if _, ok := cs.current[k]; !ok {
+			return
+		}
+		delete(cs.current, k)
+	} else {
+		if current, ok := cs.current[k]; ok && current.Equal(set) {
+			return
+		}
+		cs.current[k] = set
+	}
+
+	for _, x := range cs.changed {
+		if x == k {
+			return
+		}
+	}
+
+	cs.changed = append(cs.changed, k)
+}
+
+// Delete deletes the RRset with the given fully-qualified name and type, if it
+// exists.
+func (cs *changeSet) Delete(name, recordType string) {
+	cs.Replace(powerdnsapi.RRset{
+		Name: name,
+		Type: recordType,
+	})
+}
+
+// Each calls fn for each RRset that has been changed, in the order that they
+// were first changed.
+//
+// before and after are the RRset's original and new state, respectively. Either
+// may be empty if the RRset has been created or deleted. RRsets that have been
+// restored to their original state are skipped.
+func (cs *changeSet) Each(
+	fn func(change provider.Change, before, after powerdnsapi.RRset),
+) {
+	for _, k := range cs.changed {
+		before, existed := cs.original[k]
+		after, exists := cs.current[k]
+
+		switch {
+		case existed && exists:
+			if !before.Equal(after) {
+				fn(provider.Updated, before, after)
+			}
+		case existed:
+			fn(provider.Deleted, before, after)
+		case exists:
+			fn(provider.Created, before, after)
+		}
+	}
+}
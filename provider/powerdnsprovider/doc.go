@@ -0,0 +1,4 @@
+// Package powerdnsprovider provides a driver implementation that advertises
+// DNS-SD service instances on domain names hosted by a PowerDNS Authoritative
+// Server.
+package powerdnsprovider
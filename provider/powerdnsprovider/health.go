@@ -0,0 +1,16 @@
+package powerdnsprovider
+
+import (
+	"context"
+	"fmt"
+)
+
+// CheckHealth returns an error if the provider is unable to communicate or
+// authenticate with the PowerDNS API.
+func (p *Provider) CheckHealth(ctx context.Context) error {
+	if _, err := p.client().GetServer(ctx); err != nil {
+		return fmt.Errorf("unable to get server: %w", err)
+	}
+
+	return nil
+}
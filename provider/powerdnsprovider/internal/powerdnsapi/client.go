@@ -0,0 +1,110 @@
+package powerdnsapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultServerID is the ID of the server managed by a PowerDNS Authoritative
+// Server, which is the only server ID that it supports.
+const DefaultServerID = "localhost"
+
+// Client is a client for the PowerDNS Authoritative Server HTTP API.
+type Client struct {
+	// BaseURL is the URL of the PowerDNS web server, for example
+	// "http://ns1.example.org:8081". It must not include the "/api/v1" path.
+	BaseURL string
+
+	// APIKey is the key used to authenticate requests, as configured by the
+	// server's "api-key" setting.
+	APIKey string
+
+	// ServerID is the ID of the server that hosts the zones. If it is empty,
+	// DefaultServerID is used.
+	ServerID string
+
+	// HTTPClient is the client used to make HTTP requests. If it is nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// serverPath returns the path of the server's API endpoint, followed by the
+// given path elements, each of which is escaped.
+func (c *Client) serverPath(elems ...string) string {
+	id := c.ServerID
+	if id == "" {
+		id = DefaultServerID
+	}
+
+	p := "/api/v1/servers/" + url.PathEscape(id)
+	for _, e := range elems {
+		p += "/" + url.PathEscape(e)
+	}
+
+	return p
+}
+
+// do performs an API request and unmarshals the response into out, which may
+// be nil.
+func (c *Client) do(
+	ctx context.Context,
+	method, path string,
+	query url.Values,
+	in, out any,
+) error {
+	u := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("X-API-Key", c.APIKey)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	res, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return newError(res)
+	}
+
+	if out == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,6 @@
+// Package powerdnsapi is a minimal client for the PowerDNS Authoritative Server
+// HTTP API.
+//
+// It implements only the server, zone and RRset operations used by the
+// provider.
+package powerdnsapi
@@ -0,0 +1,56 @@
+package powerdnsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error is an unsuccessful response from the API.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// newError returns the error described by an unsuccessful response.
+func newError(res *http.Response) *Error {
+	e := &Error{StatusCode: res.StatusCode}
+
+	var body struct {
+		Error string `json:"error"`
+	}
+
+	if json.NewDecoder(res.Body).Decode(&body) == nil {
+		e.Message = body.Error
+	}
+
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("powerdns API returned HTTP %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf(
+		"powerdns API returned HTTP %d: %s",
+		e.StatusCode,
+		e.Message,
+	)
+}
+
+// HTTPStatusCode returns the HTTP status code of the response.
+func (e *Error) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// IsNotFound returns true if err is an error response from the API that
+// indicates that the requested resource does not exist.
+func IsNotFound(err error) bool {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package powerdnsapi
+
+import (
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// Change types used when patching a zone's RRsets.
+const (
+	// ChangeTypeReplace replaces all records in an RRset, creating the RRset
+	// if it does not already exist.
+	ChangeTypeReplace = "REPLACE"
+
+	// ChangeTypeDelete deletes all records in an RRset.
+	ChangeTypeDelete = "DELETE"
+)
+
+// RRset is a set of records with the same name and type.
+type RRset struct {
+	// Name is the fully-qualified name of the RRset, including the trailing
+	// dot.
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     uint32   `json:"ttl,omitempty"`
+	Records []Record `json:"records,omitempty"`
+
+	// ChangeType is the type of change to make to the RRset. It is only used
+	// when patching a zone.
+	ChangeType string `json:"changetype,omitempty"`
+}
+
+// Record is a single record within an RRset.
+type Record struct {
+	// Content is the record's data in zone file format.
+	Content  string `json:"content"`
+	Disabled bool   `json:"disabled"`
+}
+
+// Equal returns true if s and x have the same name, type, TTL and records,
+// regardless of the order of the records.
+func (s RRset) Equal(x RRset) bool {
+	if !strings.EqualFold(s.Name, x.Name) ||
+		s.Type != x.Type ||
+		s.TTL != x.TTL ||
+		len(s.Records) != len(x.Records) {
+		return false
+	}
+
+	for _, r := range s.Records {
+		if !slices.Contains(x.Records, r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,20 @@
+package powerdnsapi
+
+import (
+	"context"
+	"net/http"
+)
+
+// Server describes a server managed by the API.
+type Server struct {
+	ID         string `json:"id"`
+	DaemonType string `json:"daemon_type"`
+	Version    string `json:"version"`
+}
+
+// GetServer returns information about the client's server.
+func (c *Client) GetServer(ctx context.Context) (Server, error) {
+	var s Server
+	err := c.do(ctx, http.MethodGet, c.serverPath(), nil, nil, &s)
+	return s, err
+}
@@ -0,0 +1,66 @@
+package powerdnsapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Zone is a DNS zone hosted by the server.
+type Zone struct {
+	// ID is the opaque identifier of the zone, which is used in API paths.
+	ID string `json:"id"`
+
+	// Name is the fully-qualified name of the zone, including the trailing
+	// dot.
+	Name string `json:"name"`
+
+	// Kind is the kind of the zone, such as "Native", "Master" or "Slave".
+	Kind string `json:"kind"`
+
+	// RRsets contains the zone's RRsets. It is only populated by GetZone().
+	RRsets []RRset `json:"rrsets,omitempty"`
+}
+
+// ListZones returns the zones hosted by the server. If name is non-empty, only
+// the zone with that fully-qualified name is returned.
+func (c *Client) ListZones(ctx context.Context, name string) ([]Zone, error) {
+	q := url.Values{}
+	if name != "" {
+		q.Set("zone", name)
+	}
+
+	var zones []Zone
+	err := c.do(ctx, http.MethodGet, c.serverPath("zones"), q, nil, &zones)
+	return zones, err
+}
+
+// GetZone returns the zone with the given ID. If rrsets is true the zone's
+// RRsets are included.
+func (c *Client) GetZone(ctx context.Context, id string, rrsets bool) (Zone, error) {
+	q := url.Values{}
+	if !rrsets {
+		q.Set("rrsets", "false")
+	}
+
+	var z Zone
+	err := c.do(ctx, http.MethodGet, c.serverPath("zones", id), q, nil, &z)
+	return z, err
+}
+
+// PatchZone applies changes to the RRsets of the zone with the given ID.
+//
+// The changes are applied atomically; if any change is rejected, none of the
+// changes are applied.
+func (c *Client) PatchZone(ctx context.Context, id string, rrsets []RRset) error {
+	return c.do(
+		ctx,
+		http.MethodPatch,
+		c.serverPath("zones", id),
+		nil,
+		struct {
+			RRsets []RRset `json:"rrsets"`
+		}{rrsets},
+		nil,
+	)
+}
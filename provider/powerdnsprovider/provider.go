@@ -0,0 +1,145 @@
+package powerdnsprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/powerdnsprovider/internal/powerdnsapi"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+)
+
+// Provider is an implementation of provider.Provider that advertises DNS-SD
+// services on domains hosted by a PowerDNS Authoritative Server.
+type Provider struct {
+	// APIURL is the URL of the PowerDNS web server that serves the HTTP API,
+	// for example "http://ns1.example.org:8081".
+	APIURL string
+
+	// APIKey is the key used to authenticate with the API.
+	APIKey string
+
+	// ServerID is the ID of the server that hosts the zones. If it is empty,
+	// "localhost" is used, which is the only server ID supported by the
+	// PowerDNS Authoritative Server.
+	ServerID string
+
+	// HTTPClient is the client used to make requests to the API. If it is nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Name distinguishes this provider from other PowerDNS providers that use
+	// different servers. It may be empty if there is only one such provider.
+	Name string
+
+	Logger logr.Logger
+}
+
+// ID returns a short unique identifier for the provider.
+func (p *Provider) ID() string {
+	return provider.NamedID("powerdns", p.Name)
+}
+
+// Describe returns a human-readable description of the provider.
+func (p *Provider) Describe() string {
+	return provider.NamedDescription(
+		fmt.Sprintf("PowerDNS (%s)", p.APIURL),
+		p.Name,
+	)
+}
+
+// AdvertiserByID returns the Advertiser with the given ID.
+func (p *Provider) AdvertiserByID(
+	ctx context.Context,
+	id map[string]any,
+) (provider.Advertiser, error) {
+	zoneID, err := unmarshalAdvertiserID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	client := p.client()
+
+	zone, err := client.GetZone(ctx, zoneID, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get zone: %w", err)
+	}
+
+	return p.newAdvertiser(client, zone), nil
+}
+
+// AdvertiserByDomain returns the Advertiser used to advertise services on the
+// given domain.
+//
+// Secondary ("Slave") zones are ignored, as they can not be modified.
+//
+// ok is false if this provider does not manage the given domain.
+func (p *Provider) AdvertiserByDomain(
+	ctx context.Context,
+	domain string,
+) (provider.Advertiser, bool, error) {
+	client := p.client()
+	name := dns.Fqdn(domain)
+
+	zones, err := client.ListZones(ctx, name)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to list zones: %w", err)
+	}
+
+	for _, zone := range zones {
+		if strings.EqualFold(zone.Name, name) && zone.Kind != "Slave" {
+			return p.newAdvertiser(client, zone), true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// client returns a new API client that uses the provider's configuration.
+func (p *Provider) client() *powerdnsapi.Client {
+	return &powerdnsapi.Client{
+		BaseURL:    p.APIURL,
+		APIKey:     p.APIKey,
+		ServerID:   p.ServerID,
+		HTTPClient: p.HTTPClient,
+	}
+}
+
+func (p *Provider) newAdvertiser(
+	client *powerdnsapi.Client,
+	zone powerdnsapi.Zone,
+) *advertiser {
+	zone.RRsets = nil
+
+	return &advertiser{
+		client,
+		zone,
+		p.Logger,
+	}
+}
+
+// marshalAdvertiserID returns the ID of the advertiser for the given zone.
+func marshalAdvertiserID(z powerdnsapi.Zone) map[string]any {
+	return map[string]any{
+		"zoneID": z.ID,
+	}
+}
+
+// unmarshalAdvertiserID parses an advertiser ID into its constituent parts.
+func unmarshalAdvertiserID(id map[string]any) (zoneID string, err error) {
+	zoneIDAny, ok := id["zoneID"]
+	if !ok {
+		return "", errors.New("invalid advertiser ID: missing zoneID key")
+	}
+
+	zoneID, ok = zoneIDAny.(string)
+	if !ok || zoneID == "" {
+		return "", errors.New("invalid advertiser ID: zoneID must be a non-empty string")
+	}
+
+	return zoneID, nil
+}
@@ -0,0 +1,322 @@
+package powerdnsprovider_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	. "github.com/dogmatiq/proclaim/provider/powerdnsprovider"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	domain = "proclaim-test.example.org"
+	apiKey = "<api-key>"
+)
+
+var _ = Describe("type Provider", func() {
+	providertest.DeclareTestSuite(
+		func(ctx context.Context) providertest.TestContext {
+			srv := newServer(domain, apiKey)
+			apiURL, port := srv.start()
+
+			return providertest.TestContext{
+				Provider: &Provider{
+					APIURL: apiURL,
+					APIKey: apiKey,
+					Logger: logr.Discard(),
+				},
+				Domain: domain,
+				NameServers: func(ctx context.Context) ([]string, error) {
+					return []string{"127.0.0.1"}, nil
+				},
+				NameServerPort: port,
+				DeleteRecords: func(ctx context.Context) error {
+					srv.DeleteRecords()
+					return nil
+				},
+			}
+		},
+	)
+
+	var (
+		ctx  context.Context
+		srv  *server
+		p    *Provider
+		inst provider.ServiceInstance
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		srv = newServer(domain, apiKey)
+		apiURL, _ := srv.start()
+
+		p = &Provider{
+			APIURL: apiURL,
+			APIKey: apiKey,
+			Logger: logr.Discard(),
+		}
+
+		inst = provider.ServiceInstance{
+			Name:        "instance",
+			ServiceType: "_proclaim._tcp",
+			Domain:      domain,
+			Targets: []provider.Target{
+				{Host: "host.example.com", Port: 443},
+			},
+			TTL: 5 * time.Second,
+		}
+	})
+
+	Describe("func AdvertiserByDomain()", func() {
+		It("returns an advertiser for the zone with the given name", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(a.ID()).To(Equal(map[string]any{"zoneID": domain + "."}))
+		})
+
+		It("ignores secondary zones", func() {
+			_, ok, err := p.AdvertiserByDomain(ctx, secondaryZone)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("func AdvertiserByID()", func() {
+		It("returns an advertiser for the zone with the given ID", func() {
+			a, err := p.AdvertiserByID(ctx, map[string]any{"zoneID": domain + "."})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(a.ID()).To(Equal(map[string]any{"zoneID": domain + "."}))
+		})
+
+		It("returns an error if the zone does not exist", func() {
+			_, err := p.AdvertiserByID(ctx, map[string]any{"zoneID": "unknown.example.org."})
+			Expect(err).To(MatchError(ContainSubstring("unable to get zone")))
+		})
+
+		It("returns an error if the ID is invalid", func() {
+			_, err := p.AdvertiserByID(ctx, map[string]any{})
+			Expect(err).To(MatchError("invalid advertiser ID: missing zoneID key"))
+		})
+	})
+
+	Describe("func Advertise()", func() {
+		It("applies all changes in a single request", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			inst.Subtypes = []string{"_a", "_b"}
+
+			cs, err := a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cs).To(Equal(provider.ChangeSet{
+				PTR: provider.Created,
+				SRV: provider.Created,
+				TXT: provider.Created,
+			}))
+
+			srv.m.Lock()
+			defer srv.m.Unlock()
+			Expect(srv.Patches).To(Equal(1))
+		})
+
+		It("does not make a request if there are no changes", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cs, err := a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cs.IsEmpty()).To(BeTrue())
+
+			srv.m.Lock()
+			defer srv.m.Unlock()
+			Expect(srv.Patches).To(Equal(1))
+		})
+
+		It("retains PTR records added by other clients", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			other := "other._proclaim._tcp." + domain + "."
+
+			srv.Insert(rrset{
+				Name:    "_proclaim._tcp." + domain + ".",
+				Type:    "PTR",
+				TTL:     30,
+				Records: []record{{Content: other}},
+			})
+
+			cs, err := a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cs.PTR).To(Equal(provider.Created | provider.Updated))
+
+			_, err = a.Unadvertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			srv.m.Lock()
+			defer srv.m.Unlock()
+			Expect(srv.rrsets).To(ContainElements(
+				rrset{
+					Name:    "_proclaim._tcp." + domain + ".",
+					Type:    "PTR",
+					TTL:     30,
+					Records: []record{{Content: other}},
+				},
+				rrset{
+					Name:    "_services._dns-sd._udp." + domain + ".",
+					Type:    "PTR",
+					TTL:     30,
+					Records: []record{{Content: "_proclaim._tcp." + domain + "."}},
+				},
+			))
+		})
+		It("restores records removed concurrently by another client", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			other := "other._proclaim._tcp." + domain + "."
+
+			srv.Insert(rrset{
+				Name:    "_proclaim._tcp." + domain + ".",
+				Type:    "PTR",
+				TTL:     30,
+				Records: []record{{Content: other}},
+			})
+			srv.Insert(rrset{
+				Name:    "_services._dns-sd._udp." + domain + ".",
+				Type:    "PTR",
+				TTL:     30,
+				Records: []record{{Content: "_proclaim._tcp." + domain + "."}},
+			})
+
+			// Another controller removes the last other instance of the
+			// service type based on a snapshot that does not yet contain inst.
+			srv.PatchConcurrently(
+				rrset{
+					Name:       "_proclaim._tcp." + domain + ".",
+					Type:       "PTR",
+					ChangeType: "DELETE",
+				},
+				rrset{
+					Name:       "_services._dns-sd._udp." + domain + ".",
+					Type:       "PTR",
+					ChangeType: "DELETE",
+				},
+			)
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			srv.m.Lock()
+			defer srv.m.Unlock()
+			Expect(srv.Patches).To(Equal(2))
+			Expect(srv.rrsets).To(ContainElements(
+				rrset{
+					Name:    "_proclaim._tcp." + domain + ".",
+					Type:    "PTR",
+					TTL:     30,
+					Records: []record{{Content: "instance._proclaim._tcp." + domain + "."}},
+				},
+				rrset{
+					Name:    "_services._dns-sd._udp." + domain + ".",
+					Type:    "PTR",
+					TTL:     30,
+					Records: []record{{Content: "_proclaim._tcp." + domain + "."}},
+				},
+			))
+		})
+	})
+
+	Describe("func Unadvertise()", func() {
+		It("restores the service type if another instance is advertised concurrently", func() {
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			other := "other._proclaim._tcp." + domain + "."
+
+			// Another controller advertises an instance of the same service
+			// type based on a snapshot that still contains inst, and without
+			// changing the service type PTR RRset that it already contains.
+			srv.PatchConcurrently(rrset{
+				Name: "_proclaim._tcp." + domain + ".",
+				Type: "PTR",
+				TTL:  30,
+				Records: []record{
+					{Content: other},
+					{Content: "instance._proclaim._tcp." + domain + "."},
+				},
+				ChangeType: "REPLACE",
+			})
+
+			_, err = a.Unadvertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			srv.m.Lock()
+			defer srv.m.Unlock()
+			Expect(srv.rrsets).To(ContainElements(
+				rrset{
+					Name:    "_proclaim._tcp." + domain + ".",
+					Type:    "PTR",
+					TTL:     30,
+					Records: []record{{Content: other}},
+				},
+				rrset{
+					Name:    "_services._dns-sd._udp." + domain + ".",
+					Type:    "PTR",
+					TTL:     30,
+					Records: []record{{Content: "_proclaim._tcp." + domain + "."}},
+				},
+			))
+		})
+	})
+
+	When("the API key is invalid", func() {
+		BeforeEach(func() {
+			p.APIKey = "<invalid>"
+		})
+
+		It("returns an error when finding an advertiser", func() {
+			_, _, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).To(MatchError(ContainSubstring("HTTP 401")))
+		})
+
+		It("fails the health check", func() {
+			err := p.CheckHealth(ctx)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("func ID()", func() {
+		It("includes the provider's name", func() {
+			a := &Provider{APIURL: "http://ns1.example.org:8081", Name: "a"}
+			b := &Provider{APIURL: "http://ns1.example.org:8081", Name: "b"}
+
+			Expect(a.ID()).To(Equal("powerdns:a"))
+			Expect(b.ID()).To(Equal("powerdns:b"))
+			Expect(a.Describe()).To(Equal("PowerDNS (http://ns1.example.org:8081) [a]"))
+		})
+
+		It("does not include an empty name", func() {
+			p := &Provider{APIURL: "http://ns1.example.org:8081"}
+
+			Expect(p.ID()).To(Equal("powerdns"))
+			Expect(p.Describe()).To(Equal("PowerDNS (http://ns1.example.org:8081)"))
+		})
+	})
+})
@@ -0,0 +1,323 @@
+package powerdnsprovider_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	"github.com/miekg/dns"
+	"github.com/onsi/ginkgo/v2"
+	"golang.org/x/exp/slices"
+)
+
+// server is a stand-in for a PowerDNS Authoritative Server. It serves the
+// subset of the HTTP API used by the provider, and answers DNS queries for the
+// records in its native zone so that the advertised services can be
+// discovered.
+type server struct {
+	Zone   string
+	APIKey string
+
+	// Patches is the number of PATCH requests that have been applied to the
+	// native zone.
+	Patches int
+
+	m          sync.Mutex
+	rrsets     []rrset
+	concurrent []rrset
+}
+
+// rrset is an RRset as represented by the PowerDNS API.
+type rrset struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	TTL        uint32   `json:"ttl"`
+	Records    []record `json:"records"`
+	ChangeType string   `json:"changetype,omitempty"`
+}
+
+type record struct {
+	Content  string `json:"content"`
+	Disabled bool   `json:"disabled"`
+}
+
+// secondaryZone is the name of a secondary zone hosted by the server, which
+// can not be modified via the API.
+const secondaryZone = "secondary.example.org."
+
+// newServer returns a new server that hosts a native zone with the given name,
+// and a secondary zone.
+func newServer(zone, apiKey string) *server {
+	s := &server{
+		Zone:   dns.Fqdn(zone),
+		APIKey: apiKey,
+	}
+
+	s.DeleteRecords()
+
+	return s
+}
+
+// start starts the HTTP and DNS servers on random ports on the loopback
+// interface. It returns the URL of the API and the DNS port. The servers are
+// stopped when the current test ends.
+func (s *server) start() (apiURL, dnsPort string) {
+	api := httptest.NewServer(http.HandlerFunc(s.ServeHTTP))
+	ginkgo.DeferCleanup(api.Close)
+
+	port := providertest.StartDNSServer(
+		&providertest.Responder{
+			Zone:    s.Zone,
+			Records: s.dnsRecords,
+		},
+		nil,
+	)
+
+	return api.URL, port
+}
+
+// DeleteRecords removes all RRsets from the native zone other than the SOA and
+// NS RRsets at the zone apex.
+func (s *server) DeleteRecords() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.rrsets = []rrset{
+		{
+			Name: s.Zone,
+			Type: "SOA",
+			TTL:  3600,
+			Records: []record{
+				{Content: "ns1.example.org. hostmaster.example.org. 1 10800 3600 604800 5"},
+			},
+		},
+		{
+			Name: s.Zone,
+			Type: "NS",
+			TTL:  3600,
+			Records: []record{
+				{Content: "ns1.example.org."},
+			},
+		},
+	}
+}
+
+// Insert adds an RRset to the native zone, or replaces an existing RRset with
+// the same name and type.
+func (s *server) Insert(set rrset) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if i := indexOf(s.rrsets, set.Name, set.Type); i != -1 {
+		s.rrsets[i] = set
+	} else {
+		s.rrsets = append(s.rrsets, set)
+	}
+}
+
+// PatchConcurrently arranges for the given changes to be applied immediately
+// after the next PATCH request, as though they were made concurrently by
+// another client that fetched the zone before that request was applied.
+func (s *server) PatchConcurrently(changes ...rrset) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.concurrent = changes
+}
+
+func indexOf(rrsets []rrset, name, recordType string) int {
+	for i, set := range rrsets {
+		if strings.EqualFold(set.Name, name) && set.Type == recordType {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-API-Key") != s.APIKey {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	serverPath := "/api/v1/servers/localhost"
+	zonesPath := serverPath + "/zones"
+	zonePath := zonesPath + "/" + s.Zone
+	secondaryZonePath := zonesPath + "/" + secondaryZone
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == serverPath:
+		writeJSON(w, map[string]any{
+			"id":          "localhost",
+			"daemon_type": "authoritative",
+			"version":     "4.8.0",
+		})
+
+	case r.Method == http.MethodGet && r.URL.Path == zonesPath:
+		zones := []any{}
+		name := r.URL.Query().Get("zone")
+		if name == "" || strings.EqualFold(name, s.Zone) {
+			zones = append(zones, s.zone(s.Zone, "Native", nil))
+		}
+		if name == "" || strings.EqualFold(name, secondaryZone) {
+			zones = append(zones, s.zone(secondaryZone, "Slave", nil))
+		}
+		writeJSON(w, zones)
+
+	case r.Method == http.MethodGet && r.URL.Path == zonePath:
+		rrsets := s.rrsets
+		if r.URL.Query().Get("rrsets") == "false" {
+			rrsets = nil
+		}
+		writeJSON(w, s.zone(s.Zone, "Native", rrsets))
+
+	case r.Method == http.MethodGet && r.URL.Path == secondaryZonePath:
+		writeJSON(w, s.zone(secondaryZone, "Slave", nil))
+
+	case r.Method == http.MethodPatch && r.URL.Path == zonePath:
+		var req struct {
+			RRsets []rrset `json:"rrsets"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid JSON")
+			return
+		}
+
+		if status, message := s.patch(req.RRsets); status != http.StatusNoContent {
+			writeError(w, status, message)
+			return
+		}
+
+		s.Patches++
+
+		if s.concurrent != nil {
+			s.patch(s.concurrent)
+			s.concurrent = nil
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodPatch && r.URL.Path == secondaryZonePath:
+		writeError(w, http.StatusUnprocessableEntity, "Modifying RRsets in Secondary zones is unsupported")
+
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// patch atomically applies changes to the RRsets of the native zone.
+func (s *server) patch(changes []rrset) (status int, message string) {
+	rrsets := slices.Clone(s.rrsets)
+
+	for _, ch := range changes {
+		if !dns.IsSubDomain(s.Zone, ch.Name) {
+			return http.StatusUnprocessableEntity, fmt.Sprintf("RRset %s IN %s: Name is out of zone", ch.Name, ch.Type)
+		}
+
+		i := indexOf(rrsets, ch.Name, ch.Type)
+
+		switch ch.ChangeType {
+		case "DELETE":
+			if i != -1 {
+				rrsets = slices.Delete(rrsets, i, i+1)
+			}
+
+		case "REPLACE":
+			if len(ch.Records) == 0 {
+				return http.StatusUnprocessableEntity, fmt.Sprintf("RRset %s IN %s: no records", ch.Name, ch.Type)
+			}
+
+			for _, rr := range toRRs(ch) {
+				if rr == nil {
+					return http.StatusUnprocessableEntity, fmt.Sprintf("RRset %s IN %s: invalid record content", ch.Name, ch.Type)
+				}
+			}
+
+			ch.ChangeType = ""
+
+			if i == -1 {
+				rrsets = append(rrsets, ch)
+			} else {
+				rrsets[i] = ch
+			}
+
+		default:
+			return http.StatusUnprocessableEntity, fmt.Sprintf("Changetype not understood: %q", ch.ChangeType)
+		}
+	}
+
+	s.rrsets = rrsets
+
+	return http.StatusNoContent, ""
+}
+
+func (s *server) zone(name, kind string, rrsets []rrset) map[string]any {
+	z := map[string]any{
+		"id":   name,
+		"name": name,
+		"kind": kind,
+	}
+
+	if rrsets != nil {
+		z["rrsets"] = rrsets
+	}
+
+	return z
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": message,
+	})
+}
+
+// toRRs converts an RRset to its DNS representation. An element is nil if the
+// corresponding record content is invalid.
+func toRRs(set rrset) []dns.RR {
+	var records []dns.RR
+
+	for _, r := range set.Records {
+		rr, err := dns.NewRR(
+			fmt.Sprintf(
+				"%s %d IN %s %s",
+				set.Name,
+				set.TTL,
+				set.Type,
+				r.Content,
+			),
+		)
+		if err != nil {
+			rr = nil
+		}
+		records = append(records, rr)
+	}
+
+	return records
+}
+
+// dnsRecords returns the DNS representation of the RRsets in the native zone.
+func (s *server) dnsRecords() []dns.RR {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var records []dns.RR
+	for _, set := range s.rrsets {
+		records = append(records, toRRs(set)...)
+	}
+
+	return records
+}
@@ -0,0 +1,21 @@
+package provider
+
+import "context"
+
+// PropagationAdvertiser is an Advertiser whose changes are not immediately
+// visible on the provider's name servers.
+//
+// It is an optional interface that may be implemented by any Advertiser. The
+// reconciler uses it to delay DNS-SD discovery until the changes have
+// propagated, so that it does not report discovery failures caused by records
+// that simply have not reached the name servers yet.
+type PropagationAdvertiser interface {
+	Advertiser
+
+	// IsPropagated returns true if all of the given changes have propagated
+	// to the provider's name servers.
+	//
+	// ids are the values from the PendingChanges field of one or more
+	// ChangeSet values returned by the advertiser.
+	IsPropagated(ctx context.Context, ids []string) (bool, error)
+}
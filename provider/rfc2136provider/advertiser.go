@@ -0,0 +1,217 @@
+package rfc2136provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+)
+
+type advertiser struct {
+	Provider *Provider
+	Zone     string
+	Logger   logr.Logger
+}
+
+func (a *advertiser) ID() map[string]any {
+	return marshalAdvertiserID(a.Zone)
+}
+
+func (a *advertiser) Advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs := &changeSet{}
+
+	if err := a.syncServiceTypePTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncPTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncSRV(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.syncTXT(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) Unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs := &changeSet{}
+
+	if err := a.deletePTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteSRV(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	if err := a.deleteTXT(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
+	return a.apply(ctx, cs)
+}
+
+// apply sends a single UPDATE message containing all of the changes in cs.
+//
+// The server applies the changes atomically; either all of them succeed or
+// none of them do.
+func (a *advertiser) apply(
+	ctx context.Context,
+	cs *changeSet,
+) (provider.ChangeSet, error) {
+	if cs.IsEmpty() {
+		return provider.ChangeSet{}, nil
+	}
+
+	req := &dns.Msg{}
+	req.SetUpdate(dns.Fqdn(a.Zone))
+
+	req.Answer = append(req.Answer, cs.prerequisites...)
+
+	var result provider.ChangeSet
+
+	for _, rr := range cs.deletes {
+		req.Remove([]dns.RR{dns.Copy(rr)})
+		mergeChange(&result, rr, provider.Deleted)
+	}
+
+	for _, up := range cs.updates {
+		for _, rr := range up.Before {
+			req.Remove([]dns.RR{dns.Copy(rr)})
+		}
+		req.Insert(up.After)
+		mergeChange(&result, up.After[0], provider.Updated)
+	}
+
+	for _, rr := range cs.creates {
+		req.Insert([]dns.RR{rr})
+		mergeChange(&result, rr, provider.Created)
+	}
+
+	res, err := a.Provider.exchange(ctx, req)
+	if err != nil {
+		return provider.ChangeSet{}, fmt.Errorf("unable to send update: %w", err)
+	}
+
+	switch res.Rcode {
+	case dns.RcodeSuccess:
+	case dns.RcodeNXRrset, dns.RcodeYXRrset:
+		return provider.ChangeSet{}, fmt.Errorf(
+			"unable to apply update: shared records were modified concurrently (server responded with %s)",
+			dns.RcodeToString[res.Rcode],
+		)
+	default:
+		return provider.ChangeSet{}, fmt.Errorf(
+			"unable to apply update: server responded with %s",
+			dns.RcodeToString[res.Rcode],
+		)
+	}
+
+	for _, rr := range cs.deletes {
+		a.log("DELETE record", rr)
+	}
+
+	for _, up := range cs.updates {
+		for _, rr := range up.Before {
+			a.log("DELETE record", rr)
+		}
+		for _, rr := range up.After {
+			a.log("CREATE record", rr)
+		}
+	}
+
+	for _, rr := range cs.creates {
+		a.log("CREATE record", rr)
+	}
+
+	return result, nil
+}
+
+func (a *advertiser) log(msg string, rr dns.RR) {
+	a.Logger.Info(
+		msg,
+		"type", dns.TypeToString[rr.Header().Rrtype],
+		"name", rr.Header().Name,
+		"content", content(rr),
+		"ttl", rr.Header().Ttl,
+	)
+}
+
+// findRecords returns the records of the given type with the given name.
+func (a *advertiser) findRecords(
+	ctx context.Context,
+	name string,
+	recordType uint16,
+) ([]dns.RR, error) {
+	req := &dns.Msg{}
+	req.SetQuestion(name, recordType)
+	req.RecursionDesired = false
+
+	res, err := a.Provider.exchange(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query %s records: %w", dns.TypeToString[recordType], err)
+	}
+
+	switch res.Rcode {
+	case dns.RcodeSuccess:
+	case dns.RcodeNameError:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf(
+			"unable to query %s records: server responded with %s",
+			dns.TypeToString[recordType],
+			dns.RcodeToString[res.Rcode],
+		)
+	}
+
+	var records []dns.RR
+
+	for _, rr := range res.Answer {
+		if rr.Header().Rrtype == recordType && strings.EqualFold(rr.Header().Name, name) {
+			records = append(records, rr)
+		}
+	}
+
+	return records, nil
+}
+
+func instanceName(inst provider.ServiceInstance) string {
+	return dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, inst.Domain) + "."
+}
+
+func typeEnumerationName(inst provider.ServiceInstance) string {
+	return dnssd.TypeEnumerationDomain(inst.Domain) + "."
+}
+
+func serviceName(inst provider.ServiceInstance) string {
+	return dnssd.InstanceEnumerationDomain(inst.ServiceType, inst.Domain) + "."
+}
+
+// content returns the RDATA portion of a record in presentation format.
+func content(rr dns.RR) string {
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
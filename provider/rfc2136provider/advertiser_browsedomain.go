@@ -0,0 +1,56 @@
+package rfc2136provider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+func (a *advertiser) AdvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs := &changeSet{}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		current, err := a.findRecords(ctx, set.Name, dns.TypePTR)
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		var desired []dns.RR
+		for _, rr := range set.Records {
+			desired = append(desired, rr)
+		}
+
+		switch {
+		case len(current) == 0:
+			cs.Create(desired...)
+		case len(desired) == 0:
+			cs.Delete(current...)
+		default:
+			cs.Update(current, desired)
+		}
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) UnadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs := &changeSet{}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		current, err := a.findRecords(ctx, set.Name, dns.TypePTR)
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		cs.Delete(current...)
+	}
+
+	return a.apply(ctx, cs)
+}
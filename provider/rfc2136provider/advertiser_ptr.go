@@ -0,0 +1,92 @@
+package rfc2136provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+// findPTR returns the PTR record with the given name that refers to the given
+// instance, if any.
+func (a *advertiser) findPTR(
+	ctx context.Context,
+	name string,
+	inst provider.ServiceInstance,
+) (dns.RR, bool, error) {
+	records, err := a.findRecords(ctx, name, dns.TypePTR)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rr, ok := ptrTo(records, instanceName(inst))
+	return rr, ok, nil
+}
+
+func (a *advertiser) syncPTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findRecords(ctx, serviceName(inst), dns.TypePTR)
+	if err != nil {
+		return err
+	}
+
+	// The PTR record shares its record set with the PTR records of every other
+	// instance of the same service type. All records within a set have the
+	// same TTL, which the server may adjust as records are added, so we only
+	// check for the presence of the record and not its TTL.
+	if _, ok := ptrTo(current, instanceName(inst)); ok {
+		return nil
+	}
+
+	cs.Create(provider.NewPTRRecord(inst))
+
+	// Only add the record if the set has not been modified since we queried
+	// it. Otherwise, another controller may be removing the last instance of
+	// this service type, and with it the service type's own PTR record.
+	cs.Require(serviceName(inst), dns.TypePTR, current)
+
+	return nil
+}
+
+func (a *advertiser) deletePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findRecords(ctx, serviceName(inst), dns.TypePTR)
+	if err != nil {
+		return err
+	}
+
+	rr, ok := ptrTo(current, instanceName(inst))
+	if !ok {
+		return nil
+	}
+
+	cs.Delete(rr)
+	cs.Require(serviceName(inst), dns.TypePTR, current)
+
+	// If this is the last instance of its service type, the service type
+	// itself is no longer advertised.
+	if len(current) == 1 {
+		return a.deleteServiceTypePTR(ctx, inst, cs)
+	}
+
+	return nil
+}
+
+// ptrTo returns the PTR record within records that refers to the given target
+// name, if any.
+func ptrTo(records []dns.RR, target string) (dns.RR, bool) {
+	for _, rr := range records {
+		if ptr, ok := rr.(*dns.PTR); ok && strings.EqualFold(ptr.Ptr, target) {
+			return rr, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,58 @@
+package rfc2136provider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+// findServiceTypePTR returns the PTR record that enumerates the instance's
+// service type within its domain, if any.
+func (a *advertiser) findServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (dns.RR, bool, error) {
+	records, err := a.findRecords(ctx, typeEnumerationName(inst), dns.TypePTR)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rr, ok := ptrTo(records, serviceName(inst))
+	return rr, ok, nil
+}
+
+// syncServiceTypePTR adds the instance's service type to the shared PTR record
+// set that enumerates the service types within the domain.
+func (a *advertiser) syncServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	_, ok, err := a.findServiceTypePTR(ctx, inst)
+	if !ok && err == nil {
+		cs.Create(provider.NewServiceTypePTRRecord(inst))
+	}
+
+	return err
+}
+
+// deleteServiceTypePTR removes the instance's service type from the shared PTR
+// record set that enumerates the service types within the domain.
+//
+// It must only be called when the instance is the last instance of its service
+// type. The caller is expected to require that the instance enumeration PTR
+// record set is unchanged, such that the update fails if another instance of
+// the same service type is advertised concurrently.
+func (a *advertiser) deleteServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	rr, ok, err := a.findServiceTypePTR(ctx, inst)
+	if ok && err == nil {
+		cs.Delete(rr)
+	}
+
+	return err
+}
@@ -0,0 +1,54 @@
+package rfc2136provider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+func (a *advertiser) findSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) ([]dns.RR, error) {
+	return a.findRecords(ctx, instanceName(inst), dns.TypeSRV)
+}
+
+func (a *advertiser) syncSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findSRV(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	var desired []dns.RR
+	for _, rr := range provider.NewSRVRecords(inst) {
+		desired = append(desired, rr)
+	}
+
+	if len(current) == 0 {
+		cs.Create(desired...)
+	} else {
+		cs.Update(current, desired)
+	}
+
+	return nil
+}
+
+func (a *advertiser) deleteSRV(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findSRV(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	cs.Delete(current...)
+
+	return nil
+}
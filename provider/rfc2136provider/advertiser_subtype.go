@@ -0,0 +1,52 @@
+package rfc2136provider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+)
+
+// syncSubtypePTRs adds the instance to the PTR record set of each of its
+// subtypes.
+//
+// Unlike providers with an API that can search for records, there is no way to
+// discover which other subtypes the instance was previously advertised under
+// without transferring the entire zone. Hence, PTR records for subtypes that
+// are removed from an instance are not deleted.
+func (a *advertiser) syncSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	for _, rr := range provider.NewSubtypePTRRecords(inst) {
+		_, ok, err := a.findPTR(ctx, rr.Hdr.Name, inst)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			cs.Create(rr)
+		}
+	}
+
+	return nil
+}
+
+func (a *advertiser) deleteSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	for _, rr := range provider.NewSubtypePTRRecords(inst) {
+		current, ok, err := a.findPTR(ctx, rr.Hdr.Name, inst)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			cs.Delete(current)
+		}
+	}
+
+	return nil
+}
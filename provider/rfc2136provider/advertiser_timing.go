@@ -0,0 +1,35 @@
+package rfc2136provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+func (a *advertiser) ZoneTiming(ctx context.Context) (provider.ZoneTiming, error) {
+	req := &dns.Msg{}
+	req.SetQuestion(dns.Fqdn(a.Zone), dns.TypeSOA)
+	req.RecursionDesired = false
+
+	res, err := a.Provider.exchange(ctx, req)
+	if err != nil {
+		return provider.ZoneTiming{}, fmt.Errorf("unable to query SOA record: %w", err)
+	}
+
+	if res.Rcode != dns.RcodeSuccess {
+		return provider.ZoneTiming{}, fmt.Errorf("unable to query SOA record: %s", dns.RcodeToString[res.Rcode])
+	}
+
+	for _, rr := range res.Answer {
+		if soa, ok := rr.(*dns.SOA); ok && strings.EqualFold(soa.Hdr.Name, req.Question[0].Name) {
+			return provider.ZoneTiming{
+				NegativeTTL: provider.NegativeTTL(soa),
+			}, nil
+		}
+	}
+
+	return provider.ZoneTiming{}, nil
+}
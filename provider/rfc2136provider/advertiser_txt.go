@@ -0,0 +1,54 @@
+package rfc2136provider
+
+import (
+	"context"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+func (a *advertiser) findTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) ([]dns.RR, error) {
+	return a.findRecords(ctx, instanceName(inst), dns.TypeTXT)
+}
+
+func (a *advertiser) syncTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findTXT(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	var desired []dns.RR
+	for _, rr := range provider.NewTXTRecords(inst) {
+		desired = append(desired, rr)
+	}
+
+	if len(current) == 0 {
+		cs.Create(desired...)
+	} else {
+		cs.Update(current, desired)
+	}
+
+	return nil
+}
+
+func (a *advertiser) deleteTXT(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *changeSet,
+) error {
+	current, err := a.findTXT(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	cs.Delete(current...)
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package rfc2136provider
+
+import (
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+// changeSet encapsulates a set of DNS record changes that must be applied to
+// reconcile the DNS zone with the desired state.
+type changeSet struct {
+	prerequisites []dns.RR
+	creates       []dns.RR
+	updates       []struct {
+		Before []dns.RR
+		After  []dns.RR
+	}
+	deletes []dns.RR
+}
+
+// Require adds a prerequisite that the RRset with the given name and type
+// consists of exactly the given records, as per RFC 2136 section 2.4.2. If
+// records is empty, the prerequisite is that the RRset does not exist, as per
+// section 2.4.3.
+//
+// The server rejects the entire update if any of its prerequisites are not
+// met, which allows changes to be made conditional on the current state of
+// record sets that are shared with other instances.
+func (cs *changeSet) Require(name string, recordType uint16, records []dns.RR) {
+	if len(records) == 0 {
+		cs.prerequisites = append(
+			cs.prerequisites,
+			&dns.ANY{
+				Hdr: dns.RR_Header{
+					Name:   name,
+					Rrtype: recordType,
+					Class:  dns.ClassNONE,
+				},
+			},
+		)
+		return
+	}
+
+	for _, rr := range records {
+		rr = dns.Copy(rr)
+		rr.Header().Ttl = 0
+		cs.prerequisites = append(cs.prerequisites, rr)
+	}
+}
+
+func (cs *changeSet) Create(records ...dns.RR) {
+	cs.creates = append(cs.creates, records...)
+}
+
+// Update replaces the before record set with the after record set, unless they
+// are already equivalent.
+func (cs *changeSet) Update(before, after []dns.RR) {
+	if !recordSetsEqual(before, after) {
+		cs.updates = append(
+			cs.updates,
+			struct {
+				Before []dns.RR
+				After  []dns.RR
+			}{
+				before,
+				after,
+			},
+		)
+	}
+}
+
+func (cs *changeSet) Delete(records ...dns.RR) {
+	cs.deletes = append(cs.deletes, records...)
+}
+
+// IsEmpty returns true if the change set does not contain any changes. It does
+// not consider the prerequisites.
+func (cs *changeSet) IsEmpty() bool {
+	return len(cs.creates) == 0 &&
+		len(cs.updates) == 0 &&
+		len(cs.deletes) == 0
+}
+
+// recordSetsEqual returns true if a and b contain the same records with the
+// same TTLs, regardless of order.
+func recordSetsEqual(a, b []dns.RR) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+next:
+	for _, x := range a {
+		for _, y := range b {
+			if dns.IsDuplicate(x, y) && x.Header().Ttl == y.Header().Ttl {
+				continue next
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// mergeChange adds the change c to the change set based on the type of rr.
+func mergeChange(cs *provider.ChangeSet, rr dns.RR, c provider.Change) {
+	switch rr.Header().Rrtype {
+	case dns.TypePTR:
+		cs.PTR |= c
+	case dns.TypeSRV:
+		cs.SRV |= c
+	case dns.TypeTXT:
+		cs.TXT |= c
+	}
+}
@@ -0,0 +1,4 @@
+// Package rfc2136provider provides a driver implementation that advertises
+// DNS-SD service instances on domain names hosted by any authoritative DNS
+// server that supports dynamic updates, as per RFC 2136.
+package rfc2136provider
@@ -0,0 +1,30 @@
+package rfc2136provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/miekg/dns"
+)
+
+// CheckHealth returns an error if the provider is unable to communicate with
+// the name server, or if the server rejects the provider's TSIG key.
+func (p *Provider) CheckHealth(ctx context.Context) error {
+	// Any query will do, we're only interested in whether the server responds
+	// and, if a key is configured, accepts its signature. The response is
+	// verified by the client when the request is signed.
+	req := &dns.Msg{}
+	req.SetQuestion(".", dns.TypeSOA)
+	req.RecursionDesired = false
+
+	res, err := p.exchange(ctx, req)
+	if err != nil {
+		return fmt.Errorf("unable to query name server: %w", err)
+	}
+
+	if res.Rcode == dns.RcodeNotAuth {
+		return fmt.Errorf("name server rejected the request: %s", dns.RcodeToString[res.Rcode])
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package rfc2136provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+)
+
+// Provider is an implementation of provider.Provider that advertises DNS-SD
+// services on domains hosted by a DNS server that supports RFC 2136 dynamic
+// updates, such as BIND or Knot.
+type Provider struct {
+	// Server is the address of the primary (master) name server for the zones
+	// managed by this provider, in "host:port" format.
+	Server string
+
+	// Key is the key used to authenticate messages sent to the server. If it is
+	// nil, messages are sent without TSIG authentication.
+	Key *TSIGKey
+
+	// Name distinguishes this provider from other RFC 2136 providers. It may be
+	// empty if there is only one such provider.
+	Name string
+
+	Logger logr.Logger
+}
+
+// TSIGKey is a shared secret used to authenticate DNS messages, as per RFC
+// 8945.
+type TSIGKey struct {
+	// Name is the name of the key, as configured on the server.
+	Name string
+
+	// Algorithm is the HMAC algorithm used to sign messages, such as
+	// "hmac-sha256".
+	Algorithm string
+
+	// Secret is the base64-encoded key material.
+	Secret string
+}
+
+// ID returns a short unique identifier for the provider.
+func (p *Provider) ID() string {
+	return provider.NamedID("rfc2136", p.Name)
+}
+
+// Describe returns a human-readable description of the provider.
+func (p *Provider) Describe() string {
+	return provider.NamedDescription(
+		fmt.Sprintf("RFC 2136 (%s)", p.Server),
+		p.Name,
+	)
+}
+
+// AdvertiserByID returns the Advertiser with the given ID.
+func (p *Provider) AdvertiserByID(
+	ctx context.Context,
+	id map[string]any,
+) (provider.Advertiser, error) {
+	zone, err := unmarshalAdvertiserID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := p.isZoneApex(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%s is not authoritative for the %q zone", p.Server, zone)
+	}
+
+	return &advertiser{
+		p,
+		zone,
+		p.Logger,
+	}, nil
+}
+
+// AdvertiserByDomain returns the Advertiser used to advertise services on
+// the given domain.
+//
+// ok is false if this provider does not manage the given domain.
+func (p *Provider) AdvertiserByDomain(
+	ctx context.Context,
+	domain string,
+) (provider.Advertiser, bool, error) {
+	ok, err := p.isZoneApex(ctx, domain)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+
+	return &advertiser{
+		p,
+		domain,
+		p.Logger,
+	}, true, nil
+}
+
+// isZoneApex returns true if the server is authoritative for a zone with the
+// given domain name at its apex.
+//
+// Only the apex of a zone has an SOA record in the answer section of an SOA
+// query. Queries for other names within the zone return the SOA record in the
+// authority section, instead.
+func (p *Provider) isZoneApex(ctx context.Context, domain string) (bool, error) {
+	req := &dns.Msg{}
+	req.SetQuestion(dns.Fqdn(domain), dns.TypeSOA)
+	req.RecursionDesired = false
+
+	res, err := p.exchange(ctx, req)
+	if err != nil {
+		return false, fmt.Errorf("unable to query SOA record: %w", err)
+	}
+
+	switch res.Rcode {
+	case dns.RcodeSuccess:
+	case dns.RcodeNameError, dns.RcodeRefused, dns.RcodeNotAuth:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unable to query SOA record: %s", dns.RcodeToString[res.Rcode])
+	}
+
+	for _, rr := range res.Answer {
+		if rr.Header().Rrtype == dns.TypeSOA && strings.EqualFold(rr.Header().Name, req.Question[0].Name) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// maxUDPMessageSize is the maximum size of a message sent over UDP, as reported
+// by dns.Msg.Len().
+//
+// The length does not include the TSIG MAC, which is only computed when the
+// message is packed, so we leave room for the largest supported MAC (64 bytes,
+// for HMAC-SHA512).
+const maxUDPMessageSize = dns.MinMsgSize - 64
+
+// exchange sends a message to the server and returns the response.
+//
+// The request is signed using p.Key, if configured. TCP is used if the request
+// is too large for UDP, or if the response is truncated.
+func (p *Provider) exchange(ctx context.Context, req *dns.Msg) (*dns.Msg, error) {
+	if p.Key != nil {
+		req.SetTsig(
+			dns.CanonicalName(p.Key.Name),
+			dns.CanonicalName(p.Key.Algorithm),
+			300,
+			time.Now().Unix(),
+		)
+	}
+
+	// Messages that do not fit in a single UDP datagram, such as large
+	// updates, must be sent over TCP.
+	if req.Len() > maxUDPMessageSize {
+		res, _, err := p.client("tcp").ExchangeContext(ctx, req, p.Server)
+		return res, err
+	}
+
+	res, _, err := p.client("udp").ExchangeContext(ctx, req, p.Server)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Truncated {
+		res, _, err = p.client("tcp").ExchangeContext(ctx, req, p.Server)
+	}
+
+	return res, err
+}
+
+// client returns a DNS client that uses the given network.
+func (p *Provider) client(network string) *dns.Client {
+	c := &dns.Client{
+		Net: network,
+	}
+
+	if p.Key != nil {
+		c.TsigSecret = map[string]string{
+			dns.CanonicalName(p.Key.Name): p.Key.Secret,
+		}
+	}
+
+	return c
+}
+
+// marshalAdvertiserID returns the ID of the advertiser for the given zone.
+func marshalAdvertiserID(zone string) map[string]any {
+	return map[string]any{
+		"zone": zone,
+	}
+}
+
+// unmarshalAdvertiserID parses an advertiser ID into its constituent parts.
+func unmarshalAdvertiserID(id map[string]any) (zone string, err error) {
+	zoneAny, ok := id["zone"]
+	if !ok {
+		return "", errors.New("invalid advertiser ID: missing zone key")
+	}
+
+	zone, ok = zoneAny.(string)
+	if !ok || zone == "" {
+		return "", errors.New("invalid advertiser ID: zone must be a non-empty string")
+	}
+
+	return zone, nil
+}
@@ -0,0 +1,162 @@
+package rfc2136provider_test
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	. "github.com/dogmatiq/proclaim/provider/rfc2136provider"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	domain  = "proclaim-test.example.org"
+	keyName = "proclaim"
+	secret  = "c2VjcmV0LWtleS1tYXRlcmlhbC1mb3ItdGVzdGluZw=="
+)
+
+var _ = Describe("type Provider", func() {
+	providertest.DeclareTestSuite(
+		func(ctx context.Context) providertest.TestContext {
+			srv := newServer(domain, keyName)
+			port := srv.start(secret)
+
+			return providertest.TestContext{
+				Provider: &Provider{
+					Server: net.JoinHostPort("127.0.0.1", port),
+					Key: &TSIGKey{
+						Name:      keyName,
+						Algorithm: "hmac-sha256",
+						Secret:    secret,
+					},
+					Logger: logr.Discard(),
+				},
+				Domain: domain,
+				NameServers: func(ctx context.Context) ([]string, error) {
+					return []string{"127.0.0.1"}, nil
+				},
+				NameServerPort: port,
+				DeleteRecords: func(ctx context.Context) error {
+					srv.DeleteRecords()
+					return nil
+				},
+			}
+		},
+	)
+
+	When("another client modifies the instance enumeration records", func() {
+		It("does not remove the service type enumeration record", func() {
+			ctx := context.Background()
+
+			srv := newServer(domain, keyName)
+			port := srv.start(secret)
+
+			p := &Provider{
+				Server: net.JoinHostPort("127.0.0.1", port),
+				Key: &TSIGKey{
+					Name:      keyName,
+					Algorithm: "hmac-sha256",
+					Secret:    secret,
+				},
+				Logger: logr.Discard(),
+			}
+
+			a, ok, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			inst := provider.ServiceInstance{
+				Name:        "instance",
+				ServiceType: "_proclaim._tcp",
+				Domain:      domain,
+				Targets: []provider.Target{
+					{Host: "host.example.com", Port: 443},
+				},
+				TTL: 5 * time.Second,
+			}
+
+			_, err = a.Advertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// Simulate another controller advertising a second instance of the
+			// same service type after the records have been queried, but
+			// before the update is applied.
+			other := inst
+			other.Name = "other"
+
+			srv.BeforeUpdate(func() {
+				srv.Insert(provider.NewPTRRecord(other))
+			})
+
+			_, err = a.Unadvertise(ctx, inst)
+			Expect(err).To(MatchError(ContainSubstring("modified concurrently")))
+
+			srv.BeforeUpdate(nil)
+
+			_, err = a.Unadvertise(ctx, inst)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			res := &dnssd.UnicastResolver{
+				Config: &dns.ClientConfig{
+					Servers:  []string{"127.0.0.1"},
+					Port:     port,
+					Ndots:    1,
+					Timeout:  1,
+					Attempts: 3,
+				},
+			}
+
+			serviceTypes, err := res.EnumerateServiceTypes(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(serviceTypes).To(ConsistOf("_proclaim._tcp"))
+		})
+	})
+
+	When("the TSIG key is incorrect", func() {
+		It("returns an error when advertising", func() {
+			ctx := context.Background()
+
+			srv := newServer(domain, keyName)
+			port := srv.start(secret)
+
+			p := &Provider{
+				Server: net.JoinHostPort("127.0.0.1", port),
+				Key: &TSIGKey{
+					Name:      keyName,
+					Algorithm: "hmac-sha256",
+					Secret:    "d3Jvbmctc2VjcmV0",
+				},
+				Logger: logr.Discard(),
+			}
+
+			_, _, err := p.AdvertiserByDomain(ctx, domain)
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("fails the health check", func() {
+			ctx := context.Background()
+
+			srv := newServer(domain, keyName)
+			port := srv.start(secret)
+
+			p := &Provider{
+				Server: net.JoinHostPort("127.0.0.1", port),
+				Key: &TSIGKey{
+					Name:      keyName,
+					Algorithm: "hmac-sha256",
+					Secret:    "d3Jvbmctc2VjcmV0",
+				},
+				Logger: logr.Discard(),
+			}
+
+			err := p.CheckHealth(ctx)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
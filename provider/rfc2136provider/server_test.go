@@ -0,0 +1,343 @@
+package rfc2136provider_test
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/proclaim/provider/internal/providertest"
+	"github.com/miekg/dns"
+)
+
+// server is a minimal authoritative DNS server that supports RFC 2136 dynamic
+// updates authenticated with TSIG. It is a stand-in for a "real" server such
+// as BIND or Knot.
+type server struct {
+	Zone    string
+	KeyName string
+
+	m            sync.Mutex
+	records      []dns.RR
+	beforeUpdate func()
+}
+
+// newServer returns a new server that is authoritative for the given zone.
+func newServer(zone, keyName string) *server {
+	zone = dns.Fqdn(zone)
+
+	return &server{
+		Zone:    zone,
+		KeyName: dns.CanonicalName(keyName),
+		records: []dns.RR{
+			&dns.SOA{
+				Hdr: dns.RR_Header{
+					Name:   zone,
+					Rrtype: dns.TypeSOA,
+					Class:  dns.ClassINET,
+					Ttl:    3600,
+				},
+				Ns:      "ns." + zone,
+				Mbox:    "hostmaster." + zone,
+				Serial:  1,
+				Refresh: 3600,
+				Retry:   600,
+				Expire:  86400,
+				Minttl:  5,
+			},
+			&dns.NS{
+				Hdr: dns.RR_Header{
+					Name:   zone,
+					Rrtype: dns.TypeNS,
+					Class:  dns.ClassINET,
+					Ttl:    3600,
+				},
+				Ns: "ns." + zone,
+			},
+		},
+	}
+}
+
+// start starts the server on a random port on the loopback interface,
+// listening for both UDP and TCP connections. It returns the port number. The
+// server is stopped when the current test ends.
+func (s *server) start(secret string) string {
+	return providertest.StartDNSServer(
+		s,
+		func(srv *dns.Server) {
+			srv.TsigSecret = map[string]string{
+				s.KeyName: secret,
+			}
+			srv.MsgAcceptFunc = func(dns.Header) dns.MsgAcceptAction {
+				// The default accept function rejects UPDATE messages.
+				return dns.MsgAccept
+			}
+		},
+	)
+}
+
+// Insert adds records to the zone.
+func (s *server) Insert(records ...dns.RR) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for _, rr := range records {
+		s.records = add(s.records, rr)
+	}
+}
+
+// BeforeUpdate sets a function that is called before each update is applied.
+// It can be used to simulate changes made by other clients. A nil function
+// removes the hook.
+func (s *server) BeforeUpdate(fn func()) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.beforeUpdate = fn
+}
+
+// DeleteRecords removes all records other than the SOA and NS records at the
+// zone apex.
+func (s *server) DeleteRecords() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.records = s.records[:2]
+}
+
+func (s *server) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	res := &dns.Msg{}
+	res.SetReply(req)
+	res.Authoritative = true
+
+	t := req.IsTsig()
+
+	switch {
+	case t != nil && w.TsigStatus() != nil:
+		res.Rcode = dns.RcodeNotAuth
+		res.SetTsig(t.Hdr.Name, t.Algorithm, 300, time.Now().Unix())
+		res.IsTsig().Error = dns.RcodeBadSig
+		_ = w.WriteMsg(res)
+		return
+	case req.Opcode == dns.OpcodeQuery:
+		r := &providertest.Responder{
+			Zone:    s.Zone,
+			Records: s.snapshot,
+		}
+		res = r.Respond(req)
+	case req.Opcode == dns.OpcodeUpdate && t == nil:
+		res.Rcode = dns.RcodeRefused
+	case req.Opcode == dns.OpcodeUpdate:
+		s.update(req, res)
+	default:
+		res.Rcode = dns.RcodeNotImplemented
+	}
+
+	if t != nil {
+		res.SetTsig(t.Hdr.Name, t.Algorithm, 300, time.Now().Unix())
+	}
+
+	_ = w.WriteMsg(res)
+}
+
+// snapshot returns a copy of the records in the zone.
+func (s *server) snapshot() []dns.RR {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	records := make([]dns.RR, len(s.records))
+	for i, rr := range s.records {
+		records[i] = dns.Copy(rr)
+	}
+
+	return records
+}
+
+func (s *server) update(req, res *dns.Msg) {
+	if !strings.EqualFold(req.Question[0].Name, s.Zone) {
+		res.Rcode = dns.RcodeNotAuth
+		return
+	}
+
+	// The hook is called without holding the lock so that it can modify the
+	// zone.
+	s.m.Lock()
+	fn := s.beforeUpdate
+	s.m.Unlock()
+
+	if fn != nil {
+		fn()
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var valueDependent []dns.RR
+
+	for _, rr := range req.Answer {
+		if rr.Header().Class == dns.ClassINET {
+			// Value-dependent prerequisites are checked as a whole, once
+			// they have all been collected.
+			valueDependent = append(valueDependent, rr)
+		} else if rcode := s.checkPrerequisite(rr); rcode != dns.RcodeSuccess {
+			res.Rcode = rcode
+			return
+		}
+	}
+
+	if rcode := s.checkValueDependentPrerequisites(valueDependent); rcode != dns.RcodeSuccess {
+		res.Rcode = rcode
+		return
+	}
+
+	// Apply the updates to a copy of the records so that the update is atomic
+	// in the case of a failure.
+	records := append([]dns.RR(nil), s.records...)
+
+	for _, rr := range req.Ns {
+		h := rr.Header()
+
+		if !dns.IsSubDomain(s.Zone, h.Name) {
+			res.Rcode = dns.RcodeNotZone
+			return
+		}
+
+		switch h.Class {
+		case dns.ClassINET:
+			records = add(records, rr)
+		case dns.ClassANY:
+			records = remove(records, func(x dns.RR) bool {
+				return strings.EqualFold(x.Header().Name, h.Name) &&
+					(h.Rrtype == dns.TypeANY || x.Header().Rrtype == h.Rrtype)
+			})
+		case dns.ClassNONE:
+			records = remove(records, func(x dns.RR) bool {
+				c := dns.Copy(rr)
+				c.Header().Class = dns.ClassINET
+				return dns.IsDuplicate(x, c)
+			})
+		default:
+			res.Rcode = dns.RcodeFormatError
+			return
+		}
+	}
+
+	s.records = records
+}
+
+func (s *server) checkPrerequisite(rr dns.RR) int {
+	h := rr.Header()
+
+	nameExists := false
+	setExists := false
+
+	for _, x := range s.records {
+		if strings.EqualFold(x.Header().Name, h.Name) {
+			nameExists = true
+			if x.Header().Rrtype == h.Rrtype {
+				setExists = true
+			}
+		}
+	}
+
+	switch {
+	case h.Class == dns.ClassANY && h.Rrtype == dns.TypeANY:
+		if !nameExists {
+			return dns.RcodeNameError
+		}
+	case h.Class == dns.ClassANY:
+		if !setExists {
+			return dns.RcodeNXRrset
+		}
+	case h.Class == dns.ClassNONE && h.Rrtype == dns.TypeANY:
+		if nameExists {
+			return dns.RcodeYXDomain
+		}
+	case h.Class == dns.ClassNONE:
+		if setExists {
+			return dns.RcodeYXRrset
+		}
+	default:
+		return dns.RcodeNotImplemented
+	}
+
+	return dns.RcodeSuccess
+}
+
+// checkValueDependentPrerequisites checks that each RRset referred to by the
+// given prerequisites contains exactly the records in the prerequisites, as
+// per RFC 2136 section 3.2.5.
+func (s *server) checkValueDependentPrerequisites(prereqs []dns.RR) int {
+	for _, p := range prereqs {
+		h := p.Header()
+
+		var expect, actual []dns.RR
+
+		for _, x := range prereqs {
+			if strings.EqualFold(x.Header().Name, h.Name) && x.Header().Rrtype == h.Rrtype {
+				expect = append(expect, x)
+			}
+		}
+
+		for _, x := range s.records {
+			if strings.EqualFold(x.Header().Name, h.Name) && x.Header().Rrtype == h.Rrtype {
+				actual = append(actual, x)
+			}
+		}
+
+		if len(expect) != len(actual) {
+			return dns.RcodeNXRrset
+		}
+
+	next:
+		for _, x := range actual {
+			for _, y := range expect {
+				if dns.IsDuplicate(x, y) {
+					continue next
+				}
+			}
+
+			return dns.RcodeNXRrset
+		}
+	}
+
+	return dns.RcodeSuccess
+}
+
+// add adds rr to records. All records in the same RRset are given the TTL of
+// rr, as per RFC 2136 section 3.4.2.2.
+func add(records []dns.RR, rr dns.RR) []dns.RR {
+	h := rr.Header()
+	exists := false
+
+	for i, x := range records {
+		if strings.EqualFold(x.Header().Name, h.Name) && x.Header().Rrtype == h.Rrtype {
+			x = dns.Copy(x)
+			x.Header().Ttl = h.Ttl
+			records[i] = x
+
+			if dns.IsDuplicate(x, rr) {
+				exists = true
+			}
+		}
+	}
+
+	if exists {
+		return records
+	}
+
+	return append(records, dns.Copy(rr))
+}
+
+// remove removes the records that match pred, except for the SOA and NS
+// records at the zone apex.
+func remove(records []dns.RR, pred func(dns.RR) bool) []dns.RR {
+	var result []dns.RR
+
+	for i, x := range records {
+		if i < 2 || !pred(x) {
+			result = append(result, x)
+		}
+	}
+
+	return result
+}
@@ -3,6 +3,7 @@ package route53provider
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,6 +13,7 @@ import (
 	"github.com/dogmatiq/proclaim/provider"
 	"github.com/go-logr/logr"
 	"github.com/miekg/dns"
+	"golang.org/x/exp/slices"
 )
 
 type advertiser struct {
@@ -26,7 +28,15 @@ func (a *advertiser) ID() map[string]any {
 
 func (a *advertiser) Advertise(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs, err := a.advertise(ctx, inst)
+	return cs, rateLimitError(err)
+}
+
+func (a *advertiser) advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
 ) (provider.ChangeSet, error) {
 	cs := &types.ChangeBatch{
 		Comment: aws.String(fmt.Sprintf(
@@ -36,10 +46,18 @@ func (a *advertiser) Advertise(
 		)),
 	}
 
+	if err := a.syncServiceTypePTR(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
 	if err := a.syncPTR(ctx, inst, cs); err != nil {
 		return provider.ChangeSet{}, err
 	}
 
+	if err := a.syncSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
 	if err := a.syncSRV(ctx, inst, cs); err != nil {
 		return provider.ChangeSet{}, err
 	}
@@ -53,7 +71,15 @@ func (a *advertiser) Advertise(
 
 func (a *advertiser) Unadvertise(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	cs, err := a.unadvertise(ctx, inst)
+	return cs, rateLimitError(err)
+}
+
+func (a *advertiser) unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
 ) (provider.ChangeSet, error) {
 	cs := &types.ChangeBatch{
 		Comment: aws.String(fmt.Sprintf(
@@ -67,6 +93,10 @@ func (a *advertiser) Unadvertise(
 		return provider.ChangeSet{}, err
 	}
 
+	if err := a.deleteSubtypePTRs(ctx, inst, cs); err != nil {
+		return provider.ChangeSet{}, err
+	}
+
 	if err := a.deleteSRV(ctx, inst, cs); err != nil {
 		return provider.ChangeSet{}, err
 	}
@@ -86,7 +116,7 @@ func (a *advertiser) apply(
 		return provider.ChangeSet{}, nil
 	}
 
-	_, err := a.Client.ChangeResourceRecordSets(
+	out, err := a.Client.ChangeResourceRecordSets(
 		ctx,
 		&route53.ChangeResourceRecordSetsInput{
 			HostedZoneId: aws.String(a.ZoneID),
@@ -99,6 +129,13 @@ func (a *advertiser) apply(
 
 	var result provider.ChangeSet
 
+	if out.ChangeInfo != nil && out.ChangeInfo.Status != types.ChangeStatusInsync {
+		result.PendingChanges = append(
+			result.PendingChanges,
+			aws.ToString(out.ChangeInfo.Id),
+		)
+	}
+
 	for _, c := range cs.Changes {
 		var change provider.Change
 
@@ -169,13 +206,13 @@ func (a *advertiser) findResourceRecordSet(
 	return set, true, nil
 }
 
-func instanceName(inst dnssd.ServiceInstance) *string {
+func instanceName(inst provider.ServiceInstance) *string {
 	return aws.String(
 		dnssd.ServiceInstanceName(inst.Name, inst.ServiceType, inst.Domain) + ".",
 	)
 }
 
-func serviceName(inst dnssd.ServiceInstance) *string {
+func serviceName(inst provider.ServiceInstance) *string {
 	return aws.String(
 		dnssd.InstanceEnumerationDomain(inst.ServiceType, inst.Domain) + ".",
 	)
@@ -205,3 +242,25 @@ func convertRecords[
 
 	return result
 }
+
+// equalIgnoringOrder returns true if x and y are equal, without regard to the
+// order of their records.
+func equalIgnoringOrder(x, y types.ResourceRecordSet) bool {
+	x.ResourceRecords = sortRecords(x.ResourceRecords)
+	y.ResourceRecords = sortRecords(y.ResourceRecords)
+	return reflect.DeepEqual(x, y)
+}
+
+// sortRecords returns a sorted copy of records.
+func sortRecords(records []types.ResourceRecord) []types.ResourceRecord {
+	sorted := slices.Clone(records)
+
+	slices.SortFunc(
+		sorted,
+		func(a, b types.ResourceRecord) bool {
+			return aws.ToString(a.Value) < aws.ToString(b.Value)
+		},
+	)
+
+	return sorted
+}
@@ -0,0 +1,123 @@
+package route53provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/dogmatiq/proclaim/provider"
+)
+
+func (a *advertiser) AdvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs, err := a.advertiseBrowseDomains(ctx, d)
+	return cs, rateLimitError(err)
+}
+
+func (a *advertiser) advertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs := &types.ChangeBatch{
+		Comment: aws.String(fmt.Sprintf(
+			"dogmatiq/proclaim: advertising browse domains: %s",
+			d.Domain,
+		)),
+	}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		current, ok, err := a.findResourceRecordSet(ctx, aws.String(set.Name), types.RRTypePtr)
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		if len(set.Records) == 0 {
+			if ok {
+				cs.Changes = append(
+					cs.Changes,
+					types.Change{
+						Action:            types.ChangeActionDelete,
+						ResourceRecordSet: &current,
+					},
+				)
+			}
+
+			continue
+		}
+
+		desired := types.ResourceRecordSet{
+			Name:            aws.String(set.Name),
+			Type:            types.RRTypePtr,
+			TTL:             aws.Int64(int64(set.Records[0].Hdr.Ttl)),
+			ResourceRecords: convertRecords(set.Records...),
+		}
+
+		if !ok {
+			cs.Changes = append(
+				cs.Changes,
+				types.Change{
+					Action:            types.ChangeActionCreate,
+					ResourceRecordSet: &desired,
+				},
+			)
+			continue
+		}
+
+		if reflect.DeepEqual(current, desired) {
+			continue
+		}
+
+		cs.Changes = append(
+			cs.Changes,
+			types.Change{
+				Action:            types.ChangeActionUpsert,
+				ResourceRecordSet: &desired,
+			},
+		)
+	}
+
+	return a.apply(ctx, cs)
+}
+
+func (a *advertiser) UnadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs, err := a.unadvertiseBrowseDomains(ctx, d)
+	return cs, rateLimitError(err)
+}
+
+func (a *advertiser) unadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	cs := &types.ChangeBatch{
+		Comment: aws.String(fmt.Sprintf(
+			"dogmatiq/proclaim: unadvertising browse domains: %s",
+			d.Domain,
+		)),
+	}
+
+	for _, set := range provider.NewBrowseDomainRecordSets(d) {
+		current, ok, err := a.findResourceRecordSet(ctx, aws.String(set.Name), types.RRTypePtr)
+		if err != nil {
+			return provider.ChangeSet{}, err
+		}
+
+		if ok {
+			cs.Changes = append(
+				cs.Changes,
+				types.Change{
+					Action:            types.ChangeActionDelete,
+					ResourceRecordSet: &current,
+				},
+			)
+		}
+	}
+
+	return a.apply(ctx, cs)
+}
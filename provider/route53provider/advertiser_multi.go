@@ -0,0 +1,114 @@
+package route53provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/proclaim/provider"
+)
+
+// multiAdvertiser is an advertiser that advertises on several hosted zones
+// with the same name, such as the public and private zones of a split-horizon
+// domain.
+type multiAdvertiser []*advertiser
+
+func (m multiAdvertiser) ID() map[string]any {
+	var zoneIDs []string
+	for _, a := range m {
+		zoneIDs = append(zoneIDs, a.ZoneID)
+	}
+	return marshalAdvertiserID(zoneIDs...)
+}
+
+func (m multiAdvertiser) Advertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	return m.each(
+		func(a *advertiser) (provider.ChangeSet, error) {
+			return a.Advertise(ctx, inst)
+		},
+	)
+}
+
+func (m multiAdvertiser) Unadvertise(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) (provider.ChangeSet, error) {
+	return m.each(
+		func(a *advertiser) (provider.ChangeSet, error) {
+			return a.Unadvertise(ctx, inst)
+		},
+	)
+}
+
+func (m multiAdvertiser) AdvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	return m.each(
+		func(a *advertiser) (provider.ChangeSet, error) {
+			return a.AdvertiseBrowseDomains(ctx, d)
+		},
+	)
+}
+
+func (m multiAdvertiser) UnadvertiseBrowseDomains(
+	ctx context.Context,
+	d provider.BrowseDomains,
+) (provider.ChangeSet, error) {
+	return m.each(
+		func(a *advertiser) (provider.ChangeSet, error) {
+			return a.UnadvertiseBrowseDomains(ctx, d)
+		},
+	)
+}
+
+// ZoneTiming returns the most conservative timing of all of the zones.
+func (m multiAdvertiser) ZoneTiming(ctx context.Context) (provider.ZoneTiming, error) {
+	var result provider.ZoneTiming
+
+	for _, a := range m {
+		t, err := a.ZoneTiming(ctx)
+		if err != nil {
+			return provider.ZoneTiming{}, err
+		}
+
+		if t.NegativeTTL > result.NegativeTTL {
+			result.NegativeTTL = t.NegativeTTL
+		}
+
+	}
+
+	return result, nil
+}
+
+// IsPropagated returns true if all of the given changes have propagated to
+// the Route 53 name servers.
+func (m multiAdvertiser) IsPropagated(ctx context.Context, ids []string) (bool, error) {
+	// Change IDs are not specific to a hosted zone, so any of the advertisers
+	// can check them.
+	return m[0].IsPropagated(ctx, ids)
+}
+
+// each calls fn for each of the zone advertisers, and returns the combined
+// changes made to all zones.
+func (m multiAdvertiser) each(
+	fn func(*advertiser) (provider.ChangeSet, error),
+) (provider.ChangeSet, error) {
+	var result provider.ChangeSet
+
+	for _, a := range m {
+		cs, err := fn(a)
+		if err != nil {
+			return provider.ChangeSet{}, fmt.Errorf("%s: %w", a.ZoneID, err)
+		}
+
+		result.PTR |= cs.PTR
+		result.SRV |= cs.SRV
+		result.TXT |= cs.TXT
+		result.PendingChanges = append(result.PendingChanges, cs.PendingChanges...)
+	}
+
+	return result, nil
+}
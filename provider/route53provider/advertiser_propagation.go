@@ -0,0 +1,45 @@
+package route53provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/smithy-go"
+)
+
+// IsPropagated returns true if all of the given changes have propagated to
+// the Route 53 name servers, that is, their status is INSYNC.
+func (a *advertiser) IsPropagated(ctx context.Context, ids []string) (bool, error) {
+	for _, id := range ids {
+		out, err := a.Client.GetChange(
+			ctx,
+			&route53.GetChangeInput{
+				Id: aws.String(id),
+			},
+		)
+		if err != nil {
+			if isThrottled(err) {
+				return false, nil
+			}
+
+			// Route 53 only retains the status of changes for a limited
+			// time, a change that no longer exists has long since propagated.
+			var apiErr smithy.APIError
+			if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchChange" {
+				continue
+			}
+
+			return false, fmt.Errorf("unable to get change status: %w", err)
+		}
+
+		if out.ChangeInfo.Status != types.ChangeStatusInsync {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -10,7 +10,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/route53/types"
-	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
 	"golang.org/x/exp/slices"
 )
 
@@ -22,34 +22,61 @@ import (
 // a TTL.
 const ptrTTL = 30 * time.Second
 
-func (a *advertiser) findPTR(
+func (a *advertiser) syncPTR(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
-) (types.ResourceRecordSet, bool, error) {
-	return a.findResourceRecordSet(
-		ctx,
-		serviceName(inst),
-		types.RRTypePtr,
-	)
+	inst provider.ServiceInstance,
+	cs *types.ChangeBatch,
+) error {
+	return a.addToPTRSet(ctx, serviceName(inst), instanceName(inst), cs)
 }
 
-func (a *advertiser) syncPTR(
+func (a *advertiser) deletePTR(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
+	cs *types.ChangeBatch,
+) error {
+	current, ok, err := a.findResourceRecordSet(ctx, serviceName(inst), types.RRTypePtr)
+	if !ok || err != nil {
+		return err
+	}
+
+	if err := a.removeFromPTRSet(current, instanceName(inst), cs); err != nil {
+		return err
+	}
+
+	// If this is the last instance of its service type, the service type
+	// itself is no longer advertised.
+	if len(current.ResourceRecords) == 1 && indexOf(current, instanceName(inst)) == 0 {
+		return a.deleteServiceTypePTR(ctx, inst, cs)
+	}
+
+	return nil
+}
+
+// addToPTRSet adds a PTR record that refers to the target name to the shared
+// PTR record set with the given name.
+//
+// The record set is shared with other instances, so it is never modified in
+// place. Instead, the existing set is deleted and a new set with the next
+// "generation" is created within the same change batch. The change batch
+// fails if any other process has modified the set in the meantime.
+func (a *advertiser) addToPTRSet(
+	ctx context.Context,
+	name, target *string,
 	cs *types.ChangeBatch,
 ) error {
 	desired := types.ResourceRecordSet{
 		SetIdentifier: marshalGeneration(0),
 		Weight:        aws.Int64(0),
 		Type:          types.RRTypePtr,
-		Name:          serviceName(inst),
+		Name:          name,
 		TTL:           aws.Int64(int64(ptrTTL.Seconds())),
-		ResourceRecords: convertRecords(
-			dnssd.NewPTRRecord(inst),
-		),
+		ResourceRecords: []types.ResourceRecord{
+			{Value: target},
+		},
 	}
 
-	current, ok, err := a.findPTR(ctx, inst)
+	current, ok, err := a.findResourceRecordSet(ctx, name, types.RRTypePtr)
 	if err != nil {
 		return err
 	}
@@ -66,7 +93,7 @@ func (a *advertiser) syncPTR(
 		return nil
 	}
 
-	if indexOf(current, inst) != -1 {
+	if indexOf(current, target) != -1 {
 		return nil
 	}
 
@@ -93,17 +120,16 @@ func (a *advertiser) syncPTR(
 	return nil
 }
 
-func (a *advertiser) deletePTR(
-	ctx context.Context,
-	inst dnssd.ServiceInstance,
+// removeFromPTRSet removes the PTR record that refers to the target name from
+// the given shared PTR record set.
+//
+// See addToPTRSet() for details about how the shared record set is updated.
+func (a *advertiser) removeFromPTRSet(
+	current types.ResourceRecordSet,
+	target *string,
 	cs *types.ChangeBatch,
 ) error {
-	current, ok, err := a.findPTR(ctx, inst)
-	if !ok || err != nil {
-		return err
-	}
-
-	index := indexOf(current, inst)
+	index := indexOf(current, target)
 	if index == -1 {
 		return nil
 	}
@@ -125,7 +151,7 @@ func (a *advertiser) deletePTR(
 		SetIdentifier: marshalGeneration(gen + 1),
 		Weight:        aws.Int64(0),
 		Type:          types.RRTypePtr,
-		Name:          serviceName(inst),
+		Name:          current.Name,
 		TTL:           aws.Int64(int64(ptrTTL.Seconds())),
 		ResourceRecords: slices.Delete(
 			slices.Clone(current.ResourceRecords),
@@ -147,12 +173,11 @@ func (a *advertiser) deletePTR(
 	return nil
 }
 
-// indexOf returns the index of the given inst in a PTR resource record set, or
-// -1 if it is not present.
-func indexOf(set types.ResourceRecordSet, inst dnssd.ServiceInstance) int {
-	n := instanceName(inst)
+// indexOf returns the index of the PTR record that refers to the target name
+// in a PTR resource record set, or -1 if it is not present.
+func indexOf(set types.ResourceRecordSet, target *string) int {
 	for i, rec := range set.ResourceRecords {
-		if strings.EqualFold(*rec.Value, *n) {
+		if strings.EqualFold(*rec.Value, *target) {
 			return i
 		}
 	}
@@ -0,0 +1,47 @@
+package route53provider
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+)
+
+// syncServiceTypePTR adds the instance's service type to the shared PTR record
+// set that enumerates the service types within the domain.
+func (a *advertiser) syncServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *types.ChangeBatch,
+) error {
+	return a.addToPTRSet(ctx, typeEnumerationName(inst), serviceName(inst), cs)
+}
+
+// deleteServiceTypePTR removes the instance's service type from the shared PTR
+// record set that enumerates the service types within the domain.
+//
+// It must only be called when the instance is the last instance of its service
+// type. This is safe even when other controllers advertise instances of the same
+// type within the zone, because the change batch also replaces the instance
+// enumeration PTR record set, which fails if any other instance has been added
+// to (or removed from) that set in the meantime.
+func (a *advertiser) deleteServiceTypePTR(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *types.ChangeBatch,
+) error {
+	current, ok, err := a.findResourceRecordSet(ctx, typeEnumerationName(inst), types.RRTypePtr)
+	if !ok || err != nil {
+		return err
+	}
+
+	return a.removeFromPTRSet(current, serviceName(inst), cs)
+}
+
+func typeEnumerationName(inst provider.ServiceInstance) *string {
+	return aws.String(
+		dnssd.TypeEnumerationDomain(inst.Domain) + ".",
+	)
+}
@@ -2,16 +2,15 @@ package route53provider
 
 import (
 	"context"
-	"reflect"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/route53/types"
-	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
 )
 
 func (a *advertiser) findSRV(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 ) (types.ResourceRecordSet, bool, error) {
 	return a.findResourceRecordSet(
 		ctx,
@@ -22,7 +21,7 @@ func (a *advertiser) findSRV(
 
 func (a *advertiser) syncSRV(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 	cs *types.ChangeBatch,
 ) error {
 	desired := types.ResourceRecordSet{
@@ -30,7 +29,7 @@ func (a *advertiser) syncSRV(
 		Type: types.RRTypeSrv,
 		TTL:  aws.Int64(int64(inst.TTL.Seconds())),
 		ResourceRecords: convertRecords(
-			dnssd.NewSRVRecord(inst),
+			provider.NewSRVRecords(inst)...,
 		),
 	}
 
@@ -50,7 +49,10 @@ func (a *advertiser) syncSRV(
 		return nil
 	}
 
-	if reflect.DeepEqual(current, desired) {
+	// Route 53 does not guarantee the order of the records within a set, and
+	// the order of the targets has no meaning, so the records are compared
+	// without regard to their order.
+	if equalIgnoringOrder(current, desired) {
 		return nil
 	}
 
@@ -67,7 +69,7 @@ func (a *advertiser) syncSRV(
 
 func (a *advertiser) deleteSRV(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 	cs *types.ChangeBatch,
 ) error {
 	current, ok, err := a.findSRV(ctx, inst)
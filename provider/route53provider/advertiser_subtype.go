@@ -0,0 +1,122 @@
+package route53provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
+	"golang.org/x/exp/slices"
+)
+
+// findSubtypePTRs returns all of the subtype PTR record sets for the instance's
+// service type, regardless of which subtypes the instance provides.
+func (a *advertiser) findSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+) ([]types.ResourceRecordSet, error) {
+	parent := "._sub." + *serviceName(inst)
+
+	in := &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(a.ZoneID),
+		StartRecordName: aws.String(parent[1:]),
+	}
+
+	var sets []types.ResourceRecordSet
+
+	for {
+		out, err := a.Client.ListResourceRecordSets(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, set := range out.ResourceRecordSets {
+			// Route 53 lists record sets in DNS order, that is, sorted by their
+			// labels in reverse order. This means that all of the names within
+			// the "_sub" domain are listed contiguously.
+			if !hasSuffixFold(*set.Name, parent) {
+				return sets, nil
+			}
+
+			if set.Type == types.RRTypePtr {
+				sets = append(sets, set)
+			}
+		}
+
+		if !out.IsTruncated {
+			return sets, nil
+		}
+
+		in.StartRecordIdentifier = out.NextRecordIdentifier
+		in.StartRecordName = out.NextRecordName
+		in.StartRecordType = out.NextRecordType
+	}
+}
+
+func (a *advertiser) syncSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *types.ChangeBatch,
+) error {
+	for _, st := range inst.Subtypes {
+		if err := a.addToPTRSet(ctx, subtypeName(inst, st), instanceName(inst), cs); err != nil {
+			return err
+		}
+	}
+
+	// Remove the instance from any subtypes that it no longer provides.
+	current, err := a.findSubtypePTRs(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for _, set := range current {
+		if slices.ContainsFunc(
+			inst.Subtypes,
+			func(st string) bool {
+				return strings.EqualFold(*set.Name, *subtypeName(inst, st))
+			},
+		) {
+			continue
+		}
+
+		if err := a.removeFromPTRSet(set, instanceName(inst), cs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *advertiser) deleteSubtypePTRs(
+	ctx context.Context,
+	inst provider.ServiceInstance,
+	cs *types.ChangeBatch,
+) error {
+	current, err := a.findSubtypePTRs(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for _, set := range current {
+		if err := a.removeFromPTRSet(set, instanceName(inst), cs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func subtypeName(inst provider.ServiceInstance, subtype string) *string {
+	return aws.String(
+		dnssd.SelectiveInstanceEnumerationDomain(subtype, inst.ServiceType, inst.Domain) + ".",
+	)
+}
+
+func hasSuffixFold(s, suffix string) bool {
+	return len(s) >= len(suffix) &&
+		strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
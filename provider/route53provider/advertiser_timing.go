@@ -0,0 +1,93 @@
+package route53provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/smithy-go"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/miekg/dns"
+)
+
+// throttleInterval is the amount of time to wait after a request is throttled
+// by Route 53.
+//
+// Route 53 limits requests to 5 per second per account, and does not provide
+// any information about when the limit is reset.
+//
+// See https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/DNSLimitations.html#limits-api-requests.
+const throttleInterval = 1 * time.Second
+
+func (a *advertiser) ZoneTiming(ctx context.Context) (provider.ZoneTiming, error) {
+	out, err := a.Client.GetHostedZone(
+		ctx,
+		&route53.GetHostedZoneInput{
+			Id: aws.String(a.ZoneID),
+		},
+	)
+	if err != nil {
+		return provider.ZoneTiming{}, fmt.Errorf("unable to get hosted zone: %w", err)
+	}
+
+	set, ok, err := a.findResourceRecordSet(ctx, out.HostedZone.Name, types.RRTypeSoa)
+	if err != nil {
+		return provider.ZoneTiming{}, fmt.Errorf("unable to find SOA record: %w", err)
+	}
+
+	if !ok || len(set.ResourceRecords) == 0 {
+		return provider.ZoneTiming{}, nil
+	}
+
+	rr, err := dns.NewRR(
+		fmt.Sprintf(
+			"%s %d IN SOA %s",
+			*set.Name,
+			aws.ToInt64(set.TTL),
+			aws.ToString(set.ResourceRecords[0].Value),
+		),
+	)
+	if err != nil {
+		return provider.ZoneTiming{}, fmt.Errorf("unable to parse SOA record: %w", err)
+	}
+
+	soa, ok := rr.(*dns.SOA)
+	if !ok {
+		return provider.ZoneTiming{}, nil
+	}
+
+	return provider.ZoneTiming{
+		NegativeTTL: provider.NegativeTTL(soa),
+	}, nil
+}
+
+// rateLimitError returns a provider.RateLimitError that wraps err if err
+// indicates that a request was throttled. Otherwise, it returns err unchanged.
+func rateLimitError(err error) error {
+	if isThrottled(err) {
+		return provider.RateLimitError{
+			Reset: time.Now().Add(throttleInterval),
+			Cause: err,
+		}
+	}
+	return err
+}
+
+// isThrottled returns true if err indicates that a request was rejected
+// because the Route 53 API rate limit was exceeded.
+func isThrottled(err error) bool {
+	var apiErr smithy.APIError
+
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "Throttling", "ThrottlingException", "PriorRequestNotComplete":
+			return true
+		}
+	}
+
+	return false
+}
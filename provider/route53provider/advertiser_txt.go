@@ -6,12 +6,12 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/route53/types"
-	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/provider"
 )
 
 func (a *advertiser) findTXT(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 ) (types.ResourceRecordSet, bool, error) {
 	return a.findResourceRecordSet(
 		ctx,
@@ -22,7 +22,7 @@ func (a *advertiser) findTXT(
 
 func (a *advertiser) syncTXT(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 	cs *types.ChangeBatch,
 ) error {
 	desired := types.ResourceRecordSet{
@@ -30,7 +30,7 @@ func (a *advertiser) syncTXT(
 		Type: types.RRTypeTxt,
 		TTL:  aws.Int64(int64(inst.TTL.Seconds())),
 		ResourceRecords: convertRecords(
-			dnssd.NewTXTRecords(inst)...,
+			provider.NewTXTRecords(inst)...,
 		),
 	}
 
@@ -67,7 +67,7 @@ func (a *advertiser) syncTXT(
 
 func (a *advertiser) deleteTXT(
 	ctx context.Context,
-	inst dnssd.ServiceInstance,
+	inst provider.ServiceInstance,
 	cs *types.ChangeBatch,
 ) error {
 	current, ok, err := a.findTXT(ctx, inst)
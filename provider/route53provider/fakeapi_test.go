@@ -0,0 +1,283 @@
+package route53provider_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/dogmatiq/proclaim/provider"
+	. "github.com/dogmatiq/proclaim/provider/route53provider"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("type Provider (fake Route 53 API)", func() {
+	var (
+		ctx    context.Context
+		client *route53.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		srv := httptest.NewServer(http.HandlerFunc(serveFakeAPI))
+		DeferCleanup(srv.Close)
+
+		client = route53.New(
+			route53.Options{
+				Region:           region,
+				Credentials:      aws.AnonymousCredentials{},
+				EndpointResolver: route53.EndpointResolverFromURL(srv.URL),
+				RetryMaxAttempts: 1,
+			},
+		)
+	})
+
+	DescribeTable(
+		"func IsPropagated()",
+		func(ids []string, expect bool) {
+			p := &Provider{
+				Client:         client,
+				ZoneVisibility: AllZones,
+				Logger:         logr.Discard(),
+			}
+
+			a, ok, err := p.AdvertiserByDomain(ctx, "example.org")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			pa, ok := a.(provider.PropagationAdvertiser)
+			Expect(ok).To(BeTrue())
+
+			ok, err = pa.IsPropagated(ctx, ids)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(Equal(expect))
+		},
+		Entry("all changes are in sync", []string{"/change/CINSYNC", "/change/CUNKNOWN"}, true),
+		Entry("some changes are pending", []string{"/change/CINSYNC", "/change/CPENDING"}, false),
+	)
+
+	It("returns a rate limit error when requests are throttled", func() {
+		p := &Provider{
+			Client: client,
+			Logger: logr.Discard(),
+		}
+
+		a, ok, err := p.AdvertiserByDomain(ctx, "example.org")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		_, err = a.Advertise(
+			ctx,
+			provider.ServiceInstance{
+				Name:        "instance",
+				ServiceType: "_proclaim._tcp",
+				Domain:      "example.org",
+				Targets: []provider.Target{
+					{Host: "host.example.org", Port: 443},
+				},
+				TTL: 60 * time.Second,
+			},
+		)
+		Expect(err).Should(HaveOccurred())
+
+		reset, ok := provider.RateLimitReset(err)
+		Expect(ok).To(BeTrue())
+		Expect(reset).To(BeTemporally("~", time.Now().Add(1*time.Second), time.Second))
+	})
+
+	DescribeTable(
+		"func AdvertiserByDomain()",
+		func(p *Provider, expect map[string]any) {
+			p.Client = client
+			p.Logger = logr.Discard()
+
+			a, ok, err := p.AdvertiserByDomain(ctx, "example.org")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			if expect == nil {
+				Expect(ok).To(BeFalse())
+				return
+			}
+
+			Expect(ok).To(BeTrue())
+			Expect(a.ID()).To(Equal(expect))
+
+			_, err = p.AdvertiserByID(ctx, a.ID())
+			Expect(err).ShouldNot(HaveOccurred())
+		},
+		Entry(
+			"prefers the public zone by default",
+			&Provider{},
+			map[string]any{"hostedZoneID": "/hostedzone/ZPUBLIC"},
+		),
+		Entry(
+			"uses only public zones",
+			&Provider{ZoneVisibility: PublicZones},
+			map[string]any{"hostedZoneID": "/hostedzone/ZPUBLIC"},
+		),
+		Entry(
+			"uses only private zones",
+			&Provider{ZoneVisibility: PrivateZones},
+			map[string]any{"hostedZoneIDs": []any{"/hostedzone/ZPRIVATE1", "/hostedzone/ZPRIVATE2"}},
+		),
+		Entry(
+			"uses all zones",
+			&Provider{ZoneVisibility: AllZones},
+			map[string]any{"hostedZoneIDs": []any{"/hostedzone/ZPUBLIC", "/hostedzone/ZPRIVATE1", "/hostedzone/ZPRIVATE2"}},
+		),
+		Entry(
+			"uses only private zones associated with the VPCs",
+			&Provider{ZoneVisibility: PrivateZones, VPCIDs: []string{"vpc-2"}},
+			map[string]any{"hostedZoneID": "/hostedzone/ZPRIVATE2"},
+		),
+		Entry(
+			"ignores private zones that are not associated with the VPCs",
+			&Provider{ZoneVisibility: PrivateZones, VPCIDs: []string{"vpc-3"}},
+			nil,
+		),
+	)
+})
+
+// serveFakeAPI is an HTTP handler that implements the subset of the Route 53
+// API used to find hosted zones and check the status of changes.
+//
+// It has a public hosted zone and two private hosted zones named
+// "example.org", associated with VPCs "vpc-1" and "vpc-2", respectively. The
+// "CPENDING" change is pending, and the "CINSYNC" change is in sync. All
+// requests for resource record sets are throttled.
+func serveFakeAPI(w http.ResponseWriter, r *http.Request) {
+	type zone struct {
+		ID      string
+		Name    string
+		Private bool
+		VPC     string
+	}
+
+	zones := []zone{
+		{"ZPUBLIC", "example.org.", false, ""},
+		{"ZPRIVATE1", "example.org.", true, "vpc-1"},
+		{"ZPRIVATE2", "example.org.", true, "vpc-2"},
+		{"ZOTHER", "other.example.org.", false, ""},
+	}
+
+	render := func(z zone) string {
+		return fmt.Sprintf(
+			`<HostedZone><Id>/hostedzone/%s</Id><Name>%s</Name><CallerReference>%s</CallerReference><Config><PrivateZone>%t</PrivateZone></Config></HostedZone>`,
+			z.ID,
+			z.Name,
+			z.ID,
+			z.Private,
+		)
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/rrset"):
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(
+			w,
+			`<ErrorResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><Error><Type>Sender</Type><Code>Throttling</Code><Message>Rate exceeded</Message></Error></ErrorResponse>`,
+		)
+
+	case r.URL.Path == "/2013-04-01/hostedzonesbyname":
+		// Return one zone per page to exercise pagination.
+		start := 0
+		if id := r.URL.Query().Get("hostedzoneid"); id != "" {
+			for i, z := range zones {
+				if z.ID == id {
+					start = i
+				}
+			}
+		}
+
+		body := render(zones[start])
+		if start+1 < len(zones) {
+			next := zones[start+1]
+			body = fmt.Sprintf(
+				`<HostedZones>%s</HostedZones><IsTruncated>true</IsTruncated><NextDNSName>%s</NextDNSName><NextHostedZoneId>%s</NextHostedZoneId><MaxItems>1</MaxItems>`,
+				body,
+				next.Name,
+				next.ID,
+			)
+		} else {
+			body = fmt.Sprintf(
+				`<HostedZones>%s</HostedZones><IsTruncated>false</IsTruncated><MaxItems>1</MaxItems>`,
+				body,
+			)
+		}
+
+		fmt.Fprintf(
+			w,
+			`<ListHostedZonesByNameResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">%s</ListHostedZonesByNameResponse>`,
+			body,
+		)
+
+	case strings.HasPrefix(r.URL.Path, "/2013-04-01/hostedzone/"):
+		id := strings.TrimPrefix(r.URL.Path, "/2013-04-01/hostedzone/")
+
+		for _, z := range zones {
+			if z.ID != id {
+				continue
+			}
+
+			vpcs := ""
+			if z.VPC != "" {
+				vpcs = fmt.Sprintf(
+					`<VPCs><VPC><VPCRegion>%s</VPCRegion><VPCId>%s</VPCId></VPC></VPCs>`,
+					region,
+					z.VPC,
+				)
+			}
+
+			fmt.Fprintf(
+				w,
+				`<GetHostedZoneResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">%s%s</GetHostedZoneResponse>`,
+				render(z),
+				vpcs,
+			)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(
+			w,
+			`<ErrorResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><Error><Type>Sender</Type><Code>NoSuchHostedZone</Code><Message>not found</Message></Error></ErrorResponse>`,
+		)
+
+	case strings.HasPrefix(r.URL.Path, "/2013-04-01/change/"):
+		id := strings.TrimPrefix(r.URL.Path, "/2013-04-01/change/")
+
+		var status string
+		switch id {
+		case "CPENDING":
+			status = "PENDING"
+		case "CINSYNC":
+			status = "INSYNC"
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(
+				w,
+				`<ErrorResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><Error><Type>Sender</Type><Code>NoSuchChange</Code><Message>not found</Message></Error></ErrorResponse>`,
+			)
+			return
+		}
+
+		fmt.Fprintf(
+			w,
+			`<GetChangeResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><ChangeInfo><Id>/change/%s</Id><Status>%s</Status><SubmittedAt>2023-01-01T00:00:00Z</SubmittedAt></ChangeInfo></GetChangeResponse>`,
+			id,
+			status,
+		)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
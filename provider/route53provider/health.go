@@ -0,0 +1,24 @@
+package route53provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+)
+
+// CheckHealth returns an error if the provider is unable to communicate or
+// authenticate with Route 53.
+func (p *Provider) CheckHealth(ctx context.Context) error {
+	if _, err := p.Client.ListHostedZones(
+		ctx,
+		&route53.ListHostedZonesInput{
+			MaxItems: aws.Int32(1),
+		},
+	); err != nil {
+		return fmt.Errorf("unable to list hosted zones: %w", err)
+	}
+
+	return nil
+}
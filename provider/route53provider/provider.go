@@ -18,23 +18,48 @@ const defaultPartition = "aws"
 type Provider struct {
 	Client      *route53.Client
 	PartitionID string
-	Logger      logr.Logger
+
+	// RoleARN is the ARN of the IAM role that the client assumes in order to
+	// access Route 53, if any. It is used only to describe the provider; the
+	// client must already be configured to assume the role.
+	RoleARN string
+
+	// ZoneVisibility controls whether services are advertised on public or
+	// private hosted zones, or both.
+	ZoneVisibility ZoneVisibility
+
+	// VPCIDs restricts the private hosted zones used by the provider to those
+	// that are associated with at least one of these VPCs. If it is empty,
+	// any private hosted zone may be used.
+	VPCIDs []string
+
+	// Name distinguishes this provider from other Route 53 providers that use
+	// different AWS accounts. It may be empty if there is only one such
+	// provider.
+	Name string
+
+	Logger logr.Logger
 }
 
 // ID returns a short unique identifier for the provider.
 func (p *Provider) ID() string {
-	if id := p.partitionID(); id != defaultPartition {
-		return fmt.Sprintf("route53-%s", id)
+	id := "route53"
+	if pid := p.partitionID(); pid != defaultPartition {
+		id = fmt.Sprintf("route53-%s", pid)
 	}
-	return "route53"
+	return provider.NamedID(id, p.Name)
 }
 
 // Describe returns a human-readable description of the provider.
 func (p *Provider) Describe() string {
-	if id := p.partitionID(); id != defaultPartition {
-		return fmt.Sprintf("Amazon Route 53 (%s)", id)
+	desc := "Amazon Route 53"
+	if pid := p.partitionID(); pid != defaultPartition {
+		desc = fmt.Sprintf("Amazon Route 53 (%s)", pid)
 	}
-	return "Amazon Route 53"
+	if p.RoleARN != "" {
+		desc = fmt.Sprintf("%s via %s", desc, p.RoleARN)
+	}
+	return provider.NamedDescription(desc, p.Name)
 }
 
 // AdvertiserByID returns the Advertiser with the given ID.
@@ -42,25 +67,23 @@ func (p *Provider) AdvertiserByID(
 	ctx context.Context,
 	id map[string]any,
 ) (provider.Advertiser, error) {
-	zoneID, err := unmarshalAdvertiserID(id)
+	zoneIDs, err := unmarshalAdvertiserID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := p.Client.GetHostedZone(
-		ctx,
-		&route53.GetHostedZoneInput{
-			Id: aws.String(zoneID),
-		},
-	); err != nil {
-		return nil, fmt.Errorf("unable to get hosted zone: %w", err)
+	for _, zoneID := range zoneIDs {
+		if _, err := p.Client.GetHostedZone(
+			ctx,
+			&route53.GetHostedZoneInput{
+				Id: aws.String(zoneID),
+			},
+		); err != nil {
+			return nil, fmt.Errorf("unable to get hosted zone: %w", err)
+		}
 	}
 
-	return &advertiser{
-		p.Client,
-		zoneID,
-		p.Logger,
-	}, nil
+	return p.newAdvertiser(zoneIDs), nil
 }
 
 // AdvertiserByDomain returns the Advertiser used to advertise services on
@@ -71,34 +94,39 @@ func (p *Provider) AdvertiserByDomain(
 	ctx context.Context,
 	domain string,
 ) (provider.Advertiser, bool, error) {
-	domain += "."
-
-	out, err := p.Client.ListHostedZonesByName(
-		ctx,
-		&route53.ListHostedZonesByNameInput{
-			DNSName:  aws.String(domain),
-			MaxItems: aws.Int32(1),
-		},
-	)
+	zoneIDs, err := p.findZones(ctx, domain+".")
 	if err != nil {
-		return nil, false, fmt.Errorf("unable to list hosted zones: %w", err)
+		return nil, false, err
 	}
 
-	if len(out.HostedZones) == 0 {
+	if len(zoneIDs) == 0 {
 		return nil, false, nil
 	}
 
-	zone := out.HostedZones[0]
+	return p.newAdvertiser(zoneIDs), true, nil
+}
 
-	if *zone.Name != domain {
-		return nil, false, nil
+// newAdvertiser returns an advertiser that advertises on all of the given
+// hosted zones.
+func (p *Provider) newAdvertiser(zoneIDs []string) provider.BrowseDomainAdvertiser {
+	var advertisers multiAdvertiser
+
+	for _, zoneID := range zoneIDs {
+		advertisers = append(
+			advertisers,
+			&advertiser{
+				p.Client,
+				zoneID,
+				p.Logger,
+			},
+		)
+	}
+
+	if len(advertisers) == 1 {
+		return advertisers[0]
 	}
 
-	return &advertiser{
-		p.Client,
-		*zone.Id,
-		p.Logger,
-	}, true, nil
+	return advertisers
 }
 
 func (p *Provider) partitionID() string {
@@ -108,24 +136,56 @@ func (p *Provider) partitionID() string {
 	return p.PartitionID
 }
 
-// marshalAdvertiserID returns the ID of the advertiser for the given zone.
-func marshalAdvertiserID(zoneID string) map[string]any {
+// marshalAdvertiserID returns the ID of the advertiser for the given zones.
+//
+// An advertiser for a single zone uses the "hostedZoneID" key, which is the
+// format used before advertisers could target multiple zones.
+func marshalAdvertiserID(zoneIDs ...string) map[string]any {
+	if len(zoneIDs) == 1 {
+		return map[string]any{
+			"hostedZoneID": zoneIDs[0],
+		}
+	}
+
+	ids := make([]any, len(zoneIDs))
+	for i, id := range zoneIDs {
+		ids[i] = id
+	}
+
 	return map[string]any{
-		"hostedZoneID": zoneID,
+		"hostedZoneIDs": ids,
 	}
 }
 
 // unmarshalAdvertiserID parses an advertiser ID into its constituent parts.
-func unmarshalAdvertiserID(id map[string]any) (zoneID string, err error) {
-	zoneIDAny, ok := id["hostedZoneID"]
+func unmarshalAdvertiserID(id map[string]any) (zoneIDs []string, err error) {
+	if zoneIDAny, ok := id["hostedZoneID"]; ok {
+		zoneID, ok := zoneIDAny.(string)
+		if !ok || zoneID == "" {
+			return nil, errors.New("invalid advertiser ID: hostedZoneID must be a non-empty string")
+		}
+
+		return []string{zoneID}, nil
+	}
+
+	zoneIDsAny, ok := id["hostedZoneIDs"]
 	if !ok {
-		return "", errors.New("invalid advertiser ID: missing hostedZoneID key")
+		return nil, errors.New("invalid advertiser ID: missing hostedZoneID key")
 	}
 
-	zoneID, ok = zoneIDAny.(string)
-	if !ok || zoneID == "" {
-		return "", errors.New("invalid advertiser ID: hostedZoneID must be a non-empty string")
+	elems, ok := zoneIDsAny.([]any)
+	if !ok || len(elems) == 0 {
+		return nil, errors.New("invalid advertiser ID: hostedZoneIDs must be a non-empty array")
+	}
+
+	for _, elem := range elems {
+		zoneID, ok := elem.(string)
+		if !ok || zoneID == "" {
+			return nil, errors.New("invalid advertiser ID: hostedZoneIDs must contain non-empty strings")
+		}
+
+		zoneIDs = append(zoneIDs, zoneID)
 	}
 
-	return zoneID, nil
+	return zoneIDs, nil
 }
@@ -11,6 +11,7 @@ import (
 	. "github.com/dogmatiq/proclaim/provider/route53provider"
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
 )
 
 const (
@@ -132,4 +133,15 @@ var _ = Describe("type Provider", func() {
 			}
 		},
 	)
+
+	Describe("func Describe()", func() {
+		It("includes the assumed role", func() {
+			p := &Provider{
+				RoleARN: "arn:aws:iam::111122223333:role/proclaim",
+				Name:    "dns",
+			}
+
+			Expect(p.Describe()).To(Equal("Amazon Route 53 via arn:aws:iam::111122223333:role/proclaim [dns]"))
+		})
+	})
 })
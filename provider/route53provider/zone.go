@@ -0,0 +1,130 @@
+package route53provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"golang.org/x/exp/slices"
+)
+
+// ZoneVisibility controls which hosted zones a provider advertises on when
+// public and private hosted zones share the same name (split-horizon DNS).
+type ZoneVisibility string
+
+const (
+	// PreferPublicZones advertises on the public hosted zone for a domain if
+	// there is one, otherwise on a single private hosted zone.
+	PreferPublicZones ZoneVisibility = ""
+
+	// PublicZones advertises only on public hosted zones.
+	PublicZones ZoneVisibility = "public"
+
+	// PrivateZones advertises only on private hosted zones.
+	PrivateZones ZoneVisibility = "private"
+
+	// AllZones advertises on every public and private hosted zone for a
+	// domain.
+	AllZones ZoneVisibility = "all"
+)
+
+// findZones returns the IDs of the hosted zones that are used to advertise on
+// the given domain, which must be fully-qualified.
+func (p *Provider) findZones(
+	ctx context.Context,
+	domain string,
+) ([]string, error) {
+	var public, private []string
+
+	in := &route53.ListHostedZonesByNameInput{
+		DNSName: aws.String(domain),
+	}
+
+	for {
+		out, err := p.Client.ListHostedZonesByName(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list hosted zones: %w", err)
+		}
+
+		for _, zone := range out.HostedZones {
+			// Zones are sorted by name, so there are no more zones for this
+			// domain.
+			if *zone.Name != domain {
+				return p.selectZones(public, private), nil
+			}
+
+			if zone.Config == nil || !zone.Config.PrivateZone {
+				public = append(public, *zone.Id)
+				continue
+			}
+
+			ok, err := p.isAssociatedWithVPC(ctx, *zone.Id)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				private = append(private, *zone.Id)
+			}
+		}
+
+		if !out.IsTruncated {
+			return p.selectZones(public, private), nil
+		}
+
+		in.DNSName = out.NextDNSName
+		in.HostedZoneId = out.NextHostedZoneId
+	}
+}
+
+// selectZones returns the zones to advertise on, based on the provider's zone
+// visibility.
+func (p *Provider) selectZones(public, private []string) []string {
+	switch p.ZoneVisibility {
+	case PublicZones:
+		return public
+	case PrivateZones:
+		return private
+	case AllZones:
+		return append(public, private...)
+	default:
+		if len(public) != 0 {
+			return public[:1]
+		}
+		if len(private) != 0 {
+			return private[:1]
+		}
+		return nil
+	}
+}
+
+// isAssociatedWithVPC returns true if the private hosted zone with the given
+// ID is associated with any of the provider's VPCs, or the provider is not
+// restricted to specific VPCs.
+func (p *Provider) isAssociatedWithVPC(
+	ctx context.Context,
+	zoneID string,
+) (bool, error) {
+	if len(p.VPCIDs) == 0 {
+		return true, nil
+	}
+
+	out, err := p.Client.GetHostedZone(
+		ctx,
+		&route53.GetHostedZoneInput{
+			Id: aws.String(zoneID),
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("unable to get hosted zone: %w", err)
+	}
+
+	return slices.ContainsFunc(
+		out.VPCs,
+		func(vpc types.VPC) bool {
+			return slices.Contains(p.VPCIDs, aws.ToString(vpc.VPCId))
+		},
+	), nil
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// ZoneTimingAdvertiser is an Advertiser that can provide information about
+// the timing characteristics of the zone that it manages.
+//
+// It is an optional interface that may be implemented by any Advertiser. The
+// reconciler uses it to decide how long to wait before re-checking DNS
+// records that are not yet discoverable. The timing information is cached, so
+// it is not requested after every failed attempt.
+type ZoneTimingAdvertiser interface {
+	Advertiser
+
+	// ZoneTiming returns timing information about the advertiser's zone.
+	ZoneTiming(ctx context.Context) (ZoneTiming, error)
+}
+
+// ZoneTiming describes the timing characteristics of a zone.
+type ZoneTiming struct {
+	// NegativeTTL is the duration for which resolvers cache negative
+	// responses (such as NXDOMAIN) for names within the zone.
+	//
+	// It is zero if the negative TTL is unknown.
+	//
+	// See https://www.rfc-editor.org/rfc/rfc2308#section-5.
+	NegativeTTL time.Duration
+}
+
+// RateLimitError is an error that indicates that an operation failed because
+// the provider's API rate limit was exceeded.
+//
+// Advertisers return it so that the reconciler can wait until the limit is
+// reset before retrying, without making any further API requests.
+type RateLimitError struct {
+	// Reset is the time at which the rate limit is reset.
+	Reset time.Time
+
+	// Cause is the error returned by the provider's API.
+	Cause error
+}
+
+func (e RateLimitError) Error() string {
+	return e.Cause.Error()
+}
+
+func (e RateLimitError) Unwrap() error {
+	return e.Cause
+}
+
+// RateLimitReset returns the time at which the provider's API rate limit is
+// reset if err is (or wraps) a RateLimitError.
+func RateLimitReset(err error) (_ time.Time, ok bool) {
+	var e RateLimitError
+	if errors.As(err, &e) {
+		return e.Reset, true
+	}
+	return time.Time{}, false
+}
+
+// NegativeTTL returns the negative caching TTL described by an SOA record,
+// which is the lesser of the record's own TTL and its "minimum" field.
+//
+// See https://www.rfc-editor.org/rfc/rfc2308#section-5.
+func NegativeTTL(soa *dns.SOA) time.Duration {
+	ttl := soa.Hdr.Ttl
+	if soa.Minttl < ttl {
+		ttl = soa.Minttl
+	}
+	return time.Duration(ttl) * time.Second
+}
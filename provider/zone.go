@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// FilterZones returns a provider that only advertises on the given zones, or
+// their subdomains, using p.
+//
+// Advertisers that are already associated with a resource are still available
+// via AdvertiserByID(), so that records can be unadvertised if the zones
+// change.
+func FilterZones(p Provider, zones []string) Provider {
+	return &zoneFilter{p, zones}
+}
+
+type zoneFilter struct {
+	Provider
+	Zones []string
+}
+
+func (f *zoneFilter) Describe() string {
+	return fmt.Sprintf(
+		"%s, restricted to %s",
+		f.Provider.Describe(),
+		strings.Join(f.Zones, ", "),
+	)
+}
+
+func (f *zoneFilter) AdvertiserByDomain(
+	ctx context.Context,
+	domain string,
+) (Advertiser, bool, error) {
+	for _, z := range f.Zones {
+		if dns.IsSubDomain(dns.Fqdn(z), dns.Fqdn(domain)) {
+			return f.Provider.AdvertiserByDomain(ctx, domain)
+		}
+	}
+
+	return nil, false, nil
+}
+
+func (f *zoneFilter) CheckHealth(ctx context.Context) error {
+	if hc, ok := f.Provider.(HealthChecker); ok {
+		return hc.CheckHealth(ctx)
+	}
+	return nil
+}
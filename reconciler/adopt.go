@@ -28,14 +28,14 @@ func (r *Reconciler) associateAdvertiser(
 ) (provider.Advertiser, bool, error) {
 	exhaustive := true
 
-	for _, p := range r.Providers {
+	for _, p := range r.providers() {
 		a, ok, err := p.AdvertiserByDomain(ctx, res.Spec.Instance.Domain)
 		if err != nil {
-			crd.ProviderError(
-				r.Manager,
+			r.providerError(
 				res,
 				p.ID(),
 				p.Describe(),
+				operationGetAdvertiser,
 				err,
 			)
 
@@ -82,7 +82,7 @@ func (r *Reconciler) getAdvertiser(
 	ctx context.Context,
 	res *crd.DNSSDServiceInstance,
 ) (provider.Advertiser, bool, error) {
-	for _, p := range r.Providers {
+	for _, p := range r.providers() {
 		if p.ID() != res.Status.Provider {
 			continue
 		}
@@ -97,11 +97,11 @@ func (r *Reconciler) getAdvertiser(
 
 		a, err := p.AdvertiserByID(ctx, res.Status.Advertiser)
 		if err != nil {
-			crd.ProviderError(
-				r.Manager,
+			r.providerError(
 				res,
 				p.ID(),
 				p.Describe(),
+				operationGetAdvertiser,
 				err,
 			)
 			return nil, false, ctx.Err()
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -26,45 +27,87 @@ func (r *Reconciler) advertise(
 		}
 	}
 
-	if shouldAdvertise(res) {
-		if err := r.doAdvertise(ctx, res); err != nil {
+	inst, err := res.Spec.ToServiceInstance()
+	if err != nil {
+		// There's no point retrying until the resource is modified, which
+		// triggers another reconciliation.
+		crd.InvalidSpec(r.Manager, res, err)
+		return reconcile.Result{}, r.update(
+			res,
+			crd.MergeCondition(crd.InvalidSpecCondition(err)),
+		)
+	}
+
+	var reset time.Time
+
+	if isPropagating(res) {
+		if ok, err := r.checkPropagation(ctx, res); !ok || err != nil {
+			return reconcile.Result{RequeueAfter: propagationPollInterval}, err
+		}
+	} else if r.shouldAdvertise(res) {
+		reset, err = r.doAdvertise(ctx, res, inst)
+		if err != nil {
 			return reconcile.Result{}, err
 		}
+
+		if isPropagating(res) {
+			return reconcile.Result{RequeueAfter: propagationPollInterval}, nil
+		}
 	}
 
 	if shouldDiscover(res) {
-		ttl, err := r.doDiscover(ctx, res)
-		return shouldRequeue(res, ttl), err
+		ttl, err := r.doDiscover(ctx, res, inst)
+		return r.shouldRequeue(ctx, res, ttl, reset), err
 	}
 
-	return shouldRequeue(res, 0), nil
+	return r.shouldRequeue(ctx, res, 0, reset), nil
 }
 
+// doAdvertise advertises the instance using its associated advertiser.
+//
+// If the provider rejects the request because its API rate limit has been
+// exceeded it returns the time at which the limit is reset.
 func (r *Reconciler) doAdvertise(
 	ctx context.Context,
 	res *crd.DNSSDServiceInstance,
-) error {
+	inst provider.ServiceInstance,
+) (time.Time, error) {
 	a, ok, err := r.getOrAssociateAdvertiser(ctx, res)
 	if !ok || err != nil {
-		return err
+		return time.Time{}, err
 	}
 
-	cs, err := a.Advertise(ctx, res.Spec.ToDissolve())
-
 	advertised := res.Condition(crd.ConditionTypeAdvertised)
+	inSync := isInSync(res, advertised)
+
+	start := time.Now()
+	cs, err := a.Advertise(ctx, inst)
+	observeOperation(res.Status.Provider, operationAdvertise, start, cs, err)
+
+	if err == nil && inSync && !cs.IsEmpty() {
+		// The records were previously in sync with this generation of the
+		// resource, so they must have been modified outside of Proclaim.
+		crd.DriftDetected(r.Manager, res)
+	}
+
+	var reset time.Time
 
 	if err != nil {
-		crd.ProviderError(
-			r.Manager,
+		r.providerError(
 			res,
 			res.Status.Provider,
 			res.Status.ProviderDescription,
+			operationAdvertise,
 			err,
 		)
 		advertised = crd.AdvertiseErrorCondition(err)
+		reset, _ = provider.RateLimitReset(err)
 	} else if cs.IsEmpty() {
-		crd.DNSRecordsVerified(r.Manager, res)
+		// Only record an event when the condition changes, otherwise every
+		// periodic resync would produce a new event. DriftDetected is the only
+		// event recorded when records that are already in sync are re-checked.
 		if advertised.Status != metav1.ConditionTrue {
+			crd.DNSRecordsVerified(r.Manager, res)
 			advertised = crd.DNSRecordsObservedCondition()
 		}
 	} else if cs.IsCreate() {
@@ -75,13 +118,23 @@ func (r *Reconciler) doAdvertise(
 		advertised = crd.DNSRecordsUpdatedCondition()
 	}
 
-	return r.update(
+	var pending []string
+	if _, ok := a.(provider.PropagationAdvertiser); ok && err == nil {
+		pending = cs.PendingChanges
+	}
+
+	return reset, r.update(
 		res,
 		crd.MergeCondition(advertised),
+		crd.SetPendingChanges(pending),
+		crd.If(
+			len(pending) != 0,
+			crd.MergeCondition(crd.DNSRecordsPropagatingCondition()),
+		),
 	)
 }
 
-func shouldAdvertise(res *crd.DNSSDServiceInstance) bool {
+func (r *Reconciler) shouldAdvertise(res *crd.DNSSDServiceInstance) bool {
 	a := res.Condition(crd.ConditionTypeAdvertised)
 	d := res.Condition(crd.ConditionTypeDiscoverable)
 
@@ -94,12 +147,21 @@ func shouldAdvertise(res *crd.DNSSDServiceInstance) bool {
 	}
 
 	if d.Status == metav1.ConditionTrue {
-		return false
+		// The instance is already discoverable, we only need to re-advertise
+		// it if we're periodically checking for drift.
+		return r.ResyncInterval > 0
 	}
 
 	return true
 }
 
+// isInSync returns true if the advertised condition indicates that the DNS
+// records were in sync with the current generation of the resource.
+func isInSync(res crd.Resource, advertised metav1.Condition) bool {
+	return advertised.Status == metav1.ConditionTrue &&
+		advertised.ObservedGeneration >= res.GetGeneration()
+}
+
 func shouldDiscover(res *crd.DNSSDServiceInstance) bool {
 	a := res.Condition(crd.ConditionTypeAdvertised)
 
@@ -114,12 +176,20 @@ func shouldDiscover(res *crd.DNSSDServiceInstance) bool {
 	return true
 }
 
-func shouldRequeue(res *crd.DNSSDServiceInstance, discoveredTTL time.Duration) reconcile.Result {
+func (r *Reconciler) shouldRequeue(
+	ctx context.Context,
+	res *crd.DNSSDServiceInstance,
+	discoveredTTL time.Duration,
+	rateLimitReset time.Time,
+) reconcile.Result {
 	a := res.Condition(crd.ConditionTypeAdvertised)
 	d := res.Condition(crd.ConditionTypeDiscoverable)
 
 	if a.Status != metav1.ConditionTrue {
-		return reconcile.Result{Requeue: true}
+		// The instance could not be advertised. If the failure was due to the
+		// provider's rate limit there's no point retrying until the limit is
+		// reset.
+		return withRateLimit(rateLimitReset, reconcile.Result{Requeue: true})
 	}
 
 	if a.ObservedGeneration < res.Generation {
@@ -127,18 +197,28 @@ func shouldRequeue(res *crd.DNSSDServiceInstance, discoveredTTL time.Duration) r
 	}
 
 	if d.Status == metav1.ConditionTrue {
-		return reconcile.Result{}
+		// Note that RequeueAfter is ignored if it's zero.
+		return reconcile.Result{
+			RequeueAfter: r.ResyncInterval,
+		}
 	}
 
 	if discoveredTTL == 0 {
-		// We have no TTL information of "out of sync" DNS records, so we use
-		// the TTL from the specification.
-		//
-		// HACK: This doesn't really have anything to do with the TTL, we're
-		// just using it as a (hopefully) reasonable indicator of how long we
-		// should wait before re-trying. It would be better if the provider
-		// could give us retry intervals based on the zone's SOA record (e.g.
-		// negative cache times) and/or API rate limiting.
+		t := r.zoneTiming(ctx, res)
+
+		// We have no TTL information for "out of sync" DNS records, which
+		// typically means the records were not found at all. Resolvers cache
+		// such negative responses according to the zone's SOA record, so we
+		// wait long enough for them to expire (plus a small buffer).
+		if t.NegativeTTL > 0 {
+			return reconcile.Result{
+				RequeueAfter: t.NegativeTTL + (1 * time.Second),
+			}
+		}
+
+		// Otherwise, the provider can't tell us anything about the zone, so we
+		// fall back to the TTL from the specification as a (hopefully)
+		// reasonable indicator of how long we should wait before re-trying.
 		return reconcile.Result{
 			RequeueAfter: res.Spec.Instance.TTL.Duration,
 		}
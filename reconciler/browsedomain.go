@@ -0,0 +1,338 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReconcileBrowseDomain performs a full reconciliation for the object referred
+// to by the Request, which must be a crd.DNSSDBrowseDomain.
+func (r *Reconciler) ReconcileBrowseDomain(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	res := &crd.DNSSDBrowseDomain{}
+	if err := r.Client.Get(ctx, req.NamespacedName, res); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if requeue, err := r.initializeBrowseDomain(ctx, res); requeue || err != nil {
+		return reconcile.Result{Requeue: true}, err
+	}
+
+	if res.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.advertiseBrowseDomain(ctx, res)
+	}
+	return r.unadvertiseBrowseDomain(ctx, res)
+}
+
+func (r *Reconciler) initializeBrowseDomain(
+	ctx context.Context,
+	res *crd.DNSSDBrowseDomain,
+) (bool, error) {
+	types := []string{
+		crd.ConditionTypeAdopted,
+		crd.ConditionTypeAdvertised,
+	}
+
+	var updates []crd.StatusUpdate
+
+	for _, t := range types {
+		c := res.Condition(t)
+		if c.LastTransitionTime.IsZero() {
+			updates = append(
+				updates,
+				crd.MergeCondition(
+					metav1.Condition{
+						Type:   t,
+						Status: metav1.ConditionUnknown,
+					},
+				),
+			)
+		}
+	}
+
+	return len(updates) > 0, r.update(res, updates...)
+}
+
+// advertiseBrowseDomain adds/updates DNS records to ensure the given browse
+// domains are advertised.
+func (r *Reconciler) advertiseBrowseDomain(
+	ctx context.Context,
+	res *crd.DNSSDBrowseDomain,
+) (reconcile.Result, error) {
+	if controllerutil.AddFinalizer(res, crd.FinalizerName) {
+		if err := r.Client.Update(ctx, res); err != nil {
+			return reconcile.Result{}, fmt.Errorf("unable to add finalizer: %w", err)
+		}
+	}
+
+	advertised := res.Condition(crd.ConditionTypeAdvertised)
+	inSync := isInSync(res, advertised)
+
+	if inSync && r.ResyncInterval == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	a, ok, err := r.getOrAssociateBrowseDomainAdvertiser(ctx, res)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !ok {
+		return reconcile.Result{RequeueAfter: browseDomainRetryInterval(res)}, nil
+	}
+
+	start := time.Now()
+	cs, err := a.AdvertiseBrowseDomains(ctx, res.Spec.ToBrowseDomains())
+	observeOperation(res.Status.Provider, operationAdvertiseBrowseDomains, start, cs, err)
+	reset, _ := provider.RateLimitReset(err)
+
+	if err == nil && inSync && !cs.IsEmpty() {
+		// The records were previously in sync with this generation of the
+		// resource, so they must have been modified outside of Proclaim.
+		crd.DriftDetected(r.Manager, res)
+	}
+
+	if err != nil {
+		r.providerError(
+			res,
+			res.Status.Provider,
+			res.Status.ProviderDescription,
+			operationAdvertiseBrowseDomains,
+			err,
+		)
+		advertised = crd.AdvertiseErrorCondition(err)
+	} else if cs.IsEmpty() {
+		if advertised.Status != metav1.ConditionTrue {
+			crd.DNSRecordsVerified(r.Manager, res)
+			advertised = crd.DNSRecordsObservedCondition()
+		}
+	} else if cs.IsCreate() {
+		crd.DNSRecordsCreated(r.Manager, res)
+		advertised = crd.DNSRecordsCreatedCondition()
+	} else {
+		crd.DNSRecordsUpdated(r.Manager, res)
+		advertised = crd.DNSRecordsUpdatedCondition()
+	}
+
+	if err := r.update(
+		res,
+		crd.MergeCondition(advertised),
+	); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if advertised.Status != metav1.ConditionTrue {
+		return withRateLimit(
+			reset,
+			reconcile.Result{RequeueAfter: browseDomainRetryInterval(res)},
+		), nil
+	}
+
+	return reconcile.Result{RequeueAfter: r.ResyncInterval}, nil
+}
+
+// unadvertiseBrowseDomain removes DNS records to ensure the given browse
+// domains are no longer advertised.
+func (r *Reconciler) unadvertiseBrowseDomain(
+	ctx context.Context,
+	res *crd.DNSSDBrowseDomain,
+) (reconcile.Result, error) {
+	if res.Status.Provider != "" {
+		a, ok, err := r.getBrowseDomainAdvertiser(ctx, res)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !ok {
+			return r.unknownProviderResult(), nil
+		}
+
+		advertised := res.Condition(crd.ConditionTypeAdvertised)
+
+		start := time.Now()
+		cs, err := a.UnadvertiseBrowseDomains(ctx, res.Spec.ToBrowseDomains())
+		observeOperation(res.Status.Provider, operationUnadvertiseBrowseDomains, start, cs, err)
+		reset, _ := provider.RateLimitReset(err)
+
+		if err != nil {
+			r.providerError(
+				res,
+				res.Status.Provider,
+				res.Status.ProviderDescription,
+				operationUnadvertiseBrowseDomains,
+				err,
+			)
+			advertised = crd.UnadvertiseErrorCondition(err)
+		} else if !cs.IsEmpty() {
+			crd.DNSRecordsDeleted(r.Manager, res)
+			advertised = crd.DNSRecordsDeletedCondition()
+		}
+
+		if err := r.update(
+			res,
+			crd.MergeCondition(advertised),
+		); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if advertised.Status != metav1.ConditionFalse {
+			return withRateLimit(reset, reconcile.Result{Requeue: true}), nil
+		}
+	}
+
+	controllerutil.RemoveFinalizer(res, crd.FinalizerName)
+	if err := r.Client.Update(ctx, res); err != nil {
+		return reconcile.Result{}, fmt.Errorf("unable to remove finalizer: %w", err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// getOrAssociateBrowseDomainAdvertiser returns the advertiser used to
+// advertise/unadvertise the given browse domains.
+func (r *Reconciler) getOrAssociateBrowseDomainAdvertiser(
+	ctx context.Context,
+	res *crd.DNSSDBrowseDomain,
+) (provider.BrowseDomainAdvertiser, bool, error) {
+	if res.Status.Provider != "" {
+		return r.getBrowseDomainAdvertiser(ctx, res)
+	}
+	return r.associateBrowseDomainAdvertiser(ctx, res)
+}
+
+// associateBrowseDomainAdvertiser finds the appropriate advertiser for the
+// given browse domains from all available providers and associates it with
+// the resource.
+//
+// Only advertisers that implement provider.BrowseDomainAdvertiser are
+// considered. If a provider manages the domain but does not support browse
+// domains the resource is marked as unsupported rather than ignored.
+func (r *Reconciler) associateBrowseDomainAdvertiser(
+	ctx context.Context,
+	res *crd.DNSSDBrowseDomain,
+) (provider.BrowseDomainAdvertiser, bool, error) {
+	exhaustive := true
+	unsupported := ""
+
+	for _, p := range r.providers() {
+		a, ok, err := p.AdvertiserByDomain(ctx, res.Spec.Domain)
+		if err != nil {
+			r.providerError(
+				res,
+				p.ID(),
+				p.Describe(),
+				operationGetAdvertiser,
+				err,
+			)
+
+			exhaustive = false
+
+			if ctx.Err() != nil {
+				return nil, false, ctx.Err()
+			}
+		}
+
+		if !ok {
+			continue
+		}
+
+		ba, ok := a.(provider.BrowseDomainAdvertiser)
+		if !ok {
+			if unsupported == "" {
+				unsupported = p.Describe()
+			}
+			continue
+		}
+
+		if err := r.update(
+			res,
+			crd.MergeCondition(crd.BrowseDomainAdoptedCondition()),
+			crd.UpdateProviderDescription(p.Describe()),
+			crd.AssociateProvider(p.ID(), a.ID()),
+		); err != nil {
+			return nil, false, err
+		}
+
+		crd.BrowseDomainAdopted(r.Manager, res)
+
+		return ba, true, nil
+	}
+
+	if !exhaustive {
+		return nil, false, nil
+	}
+
+	condition := crd.BrowseDomainIgnoredCondition()
+
+	if unsupported != "" {
+		crd.BrowseDomainUnsupported(r.Manager, res, unsupported)
+		condition = crd.BrowseDomainUnsupportedCondition()
+	} else {
+		crd.BrowseDomainIgnored(r.Manager, res)
+	}
+
+	return nil, false, r.update(
+		res,
+		crd.MergeCondition(condition),
+	)
+}
+
+func (r *Reconciler) getBrowseDomainAdvertiser(
+	ctx context.Context,
+	res *crd.DNSSDBrowseDomain,
+) (provider.BrowseDomainAdvertiser, bool, error) {
+	for _, p := range r.providers() {
+		if p.ID() != res.Status.Provider {
+			continue
+		}
+
+		// Make sure the provider's description is up-to-date.
+		if err := r.update(
+			res,
+			crd.UpdateProviderDescription(p.Describe()),
+		); err != nil {
+			return nil, false, err
+		}
+
+		a, err := p.AdvertiserByID(ctx, res.Status.Advertiser)
+		if err != nil {
+			r.providerError(
+				res,
+				p.ID(),
+				p.Describe(),
+				operationGetAdvertiser,
+				err,
+			)
+			return nil, false, ctx.Err()
+		}
+
+		ba, ok := a.(provider.BrowseDomainAdvertiser)
+		return ba, ok, nil
+	}
+
+	// This reconciler does not know about the provider that is associated with
+	// the resource. This is likely because the resource is managed by some
+	// other instance of Proclaim.
+	return nil, false, nil
+}
+
+// browseDomainRetryInterval returns the interval to wait before retrying to
+// advertise browse domains that could not be advertised.
+func browseDomainRetryInterval(res *crd.DNSSDBrowseDomain) time.Duration {
+	if res.Spec.TTL.Duration != 0 {
+		return res.Spec.TTL.Duration
+	}
+	return dnssd.DefaultTTL
+}
@@ -0,0 +1,241 @@
+package reconciler_test
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/memoryprovider"
+	. "github.com/dogmatiq/proclaim/reconciler"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("func (*Reconciler) ReconcileBrowseDomain()", func() {
+	var (
+		ctx        context.Context
+		cli        client.Client
+		recorder   *record.FakeRecorder
+		dnsp       *memoryprovider.Provider
+		server     string
+		reconciler *Reconciler
+		req        reconcile.Request
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		DeferCleanup(cancel)
+
+		dnsp = &memoryprovider.Provider{
+			Domains: []string{domain},
+			Logger:  logr.Discard(),
+		}
+
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		go dnsp.Serve(ctx, conn) //nolint:errcheck
+
+		server = conn.LocalAddr().String()
+
+		scheme := runtime.NewScheme()
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+
+		res := &crd.DNSSDBrowseDomain{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "browse-domain",
+			},
+			Spec: crd.DNSSDBrowseDomainSpec{
+				Domain:              domain,
+				Browse:              []string{"a." + domain, "b." + domain},
+				DefaultBrowse:       "a." + domain,
+				DefaultRegistration: "a." + domain,
+				TTL:                 metav1.Duration{Duration: 5 * time.Second},
+			},
+		}
+
+		req = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name: res.Name,
+			},
+		}
+
+		cli = fake.
+			NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(res).
+			Build()
+
+		recorder = record.NewFakeRecorder(100)
+
+		reconciler = &Reconciler{
+			Manager:   &managerStub{Recorder: recorder},
+			Client:    cli,
+			Providers: []provider.Provider{dnsp},
+		}
+	})
+
+	// reconcileUntilSettled calls ReconcileBrowseDomain() until it no longer
+	// requests an immediate requeue, then returns the current state of the
+	// resource.
+	reconcileUntilSettled := func() (*crd.DNSSDBrowseDomain, reconcile.Result) {
+		for i := 0; i < 10; i++ {
+			result, err := reconciler.ReconcileBrowseDomain(ctx, req)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			if !result.Requeue {
+				r := &crd.DNSSDBrowseDomain{}
+				if err := cli.Get(ctx, req.NamespacedName, r); err != nil {
+					Expect(client.IgnoreNotFound(err)).ShouldNot(HaveOccurred())
+					return nil, result
+				}
+				return r, result
+			}
+		}
+
+		Fail("reconciler did not settle")
+		return nil, reconcile.Result{}
+	}
+
+	// queryPTR returns the targets of the PTR records with the given name.
+	queryPTR := func(name string) []string {
+		req := &dns.Msg{}
+		req.SetQuestion(dns.Fqdn(name), dns.TypePTR)
+
+		var c dns.Client
+		res, _, err := c.ExchangeContext(ctx, req, server)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		var targets []string
+		for _, rr := range res.Answer {
+			if ptr, ok := rr.(*dns.PTR); ok {
+				targets = append(targets, ptr.Ptr)
+			}
+		}
+
+		return targets
+	}
+
+	It("advertises the browse domains", func() {
+		r, result := reconcileUntilSettled()
+		Expect(result).To(Equal(reconcile.Result{}))
+
+		Expect(r.Finalizers).To(ContainElement(crd.FinalizerName))
+		Expect(r.Status.Provider).To(Equal("memory"))
+		Expect(r.Condition(crd.ConditionTypeAdopted).Reason).To(Equal("BrowseDomainAdopted"))
+		Expect(r.Condition(crd.ConditionTypeAdvertised).Reason).To(Equal("RecordsCreated"))
+
+		Expect(queryPTR("b._dns-sd._udp." + domain)).To(ConsistOf("a."+domain+".", "b."+domain+"."))
+		Expect(queryPTR("db._dns-sd._udp." + domain)).To(ConsistOf("a." + domain + "."))
+		Expect(queryPTR("dr._dns-sd._udp." + domain)).To(ConsistOf("a." + domain + "."))
+		Expect(queryPTR("lb._dns-sd._udp." + domain)).To(BeEmpty())
+
+		Expect(recorder.Events).To(Receive(Equal("Normal BrowseDomainAdopted In-memory can advertise browse domains on \"" + domain + "\"")))
+		Expect(recorder.Events).To(Receive(Equal("Normal RecordsCreated created new DNS records")))
+	})
+
+	It("updates the records when the resource is modified", func() {
+		r, _ := reconcileUntilSettled()
+
+		// The fake client does not maintain the generation, so we bump it
+		// manually.
+		r.Spec.Browse = []string{"c." + domain}
+		r.Generation++
+		Expect(cli.Update(ctx, r)).To(Succeed())
+
+		r, result := reconcileUntilSettled()
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(r.Condition(crd.ConditionTypeAdvertised).Reason).To(Equal("RecordsUpdated"))
+
+		Expect(queryPTR("b._dns-sd._udp." + domain)).To(ConsistOf("c." + domain + "."))
+	})
+
+	When("a resync interval is configured", func() {
+		BeforeEach(func() {
+			reconciler.ResyncInterval = 10 * time.Minute
+		})
+
+		It("repairs records that are removed outside of the controller", func() {
+			_, result := reconcileUntilSettled()
+			Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Minute}))
+
+			dnsp.DeleteRecords()
+
+			for len(recorder.Events) > 0 {
+				<-recorder.Events
+			}
+
+			_, result = reconcileUntilSettled()
+			Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Minute}))
+			Expect(recorder.Events).To(Receive(Equal("Warning DriftDetected DNS records no longer match the specification, repairing")))
+
+			Expect(queryPTR("b._dns-sd._udp." + domain)).To(ConsistOf("a."+domain+".", "b."+domain+"."))
+		})
+	})
+
+	It("unadvertises the browse domains when the resource is deleted", func() {
+		r, _ := reconcileUntilSettled()
+		Expect(cli.Delete(ctx, r)).To(Succeed())
+
+		r, result := reconcileUntilSettled()
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(r).To(BeNil(), "finalizer was not removed")
+
+		Expect(queryPTR("b._dns-sd._udp." + domain)).To(BeEmpty())
+		Expect(queryPTR("db._dns-sd._udp." + domain)).To(BeEmpty())
+	})
+
+	It("ignores domains that are not handled by any provider", func() {
+		r := &crd.DNSSDBrowseDomain{}
+		Expect(cli.Get(ctx, req.NamespacedName, r)).To(Succeed())
+		r.Spec.Domain = "unknown.example.org"
+		Expect(cli.Update(ctx, r)).To(Succeed())
+
+		r, result := reconcileUntilSettled()
+		Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+		Expect(r.Status.Provider).To(BeEmpty())
+		Expect(r.Condition(crd.ConditionTypeAdopted).Reason).To(Equal("BrowseDomainIgnored"))
+	})
+
+	It("reports domains whose providers do not support browse domains", func() {
+		reconciler.Providers = []provider.Provider{
+			withoutBrowseDomains{dnsp},
+		}
+
+		r, result := reconcileUntilSettled()
+		Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+		Expect(r.Status.Provider).To(BeEmpty())
+		Expect(r.Condition(crd.ConditionTypeAdopted).Reason).To(Equal("BrowseDomainUnsupported"))
+		Expect(recorder.Events).To(Receive(Equal("Warning BrowseDomainUnsupported In-memory manages \"" + domain + "\" but does not support browse domains")))
+	})
+})
+
+// withoutBrowseDomains is a provider that returns advertisers that do not
+// implement provider.BrowseDomainAdvertiser.
+type withoutBrowseDomains struct {
+	provider.Provider
+}
+
+func (p withoutBrowseDomains) AdvertiserByDomain(
+	ctx context.Context,
+	domain string,
+) (provider.Advertiser, bool, error) {
+	a, ok, err := p.Provider.AdvertiserByDomain(ctx, domain)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+
+	return struct{ provider.Advertiser }{a}, true, nil
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/internal/dnssdx"
+	"github.com/dogmatiq/proclaim/provider"
 	"golang.org/x/exp/slices"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -13,8 +15,11 @@ import (
 func (r *Reconciler) doDiscover(
 	ctx context.Context,
 	res *crd.DNSSDServiceInstance,
+	desired provider.ServiceInstance,
 ) (time.Duration, error) {
-	ttl, discoverable := r.computeDiscoverable(ctx, res)
+	ttl, discoverable := r.computeDiscoverable(ctx, res, desired)
+	discoveryResults.WithLabelValues(discoverable.Reason).Inc()
+
 	return ttl, r.update(
 		res,
 		crd.MergeCondition(discoverable),
@@ -24,6 +29,7 @@ func (r *Reconciler) doDiscover(
 func (r *Reconciler) computeDiscoverable(
 	ctx context.Context,
 	res *crd.DNSSDServiceInstance,
+	desired provider.ServiceInstance,
 ) (time.Duration, metav1.Condition) {
 	instances, err := r.Resolver.EnumerateInstances(
 		ctx,
@@ -34,18 +40,31 @@ func (r *Reconciler) computeDiscoverable(
 		return 0, crd.DiscoveryErrorCondition(err)
 	}
 
-	if !slices.ContainsFunc(
-		instances,
-		func(v string) bool {
-			return strings.EqualFold(v, res.Spec.Instance.Name)
-		},
-	) {
+	if !containsInstance(instances, res.Spec.Instance.Name) {
 		crd.NegativeBrowseResult(r.Manager, res)
 		return 0, crd.NegativeBrowseResultCondition()
 	}
 
-	observed, ok, err := r.Resolver.LookupInstance(
+	for _, subtype := range res.Spec.Instance.Subtypes {
+		instances, err := r.Resolver.EnumerateInstancesBySubType(
+			ctx,
+			subtype,
+			res.Spec.Instance.ServiceType,
+			res.Spec.Instance.Domain,
+		)
+		if err != nil {
+			return 0, crd.DiscoveryErrorCondition(err)
+		}
+
+		if !containsInstance(instances, res.Spec.Instance.Name) {
+			crd.NegativeSubtypeBrowseResult(r.Manager, res, subtype)
+			return 0, crd.NegativeSubtypeBrowseResultCondition(subtype)
+		}
+	}
+
+	observed, ok, err := dnssdx.LookupInstance(
 		ctx,
+		r.Resolver,
 		res.Spec.Instance.Name,
 		res.Spec.Instance.ServiceType,
 		res.Spec.Instance.Domain,
@@ -59,7 +78,10 @@ func (r *Reconciler) computeDiscoverable(
 		return 0, crd.NegativeLookupResultCondition()
 	}
 
-	desired := res.Spec.ToDissolve()
+	// Subtypes are not part of the instance's own records, so the lookup can
+	// not observe them. They have already been verified by browsing each
+	// subtype, above.
+	observed.Subtypes = desired.Subtypes
 
 	// The TTL of the observed instance may be less than the desired TTL based
 	// on how old the DNS server's cache is. So long as the observed TTL does
@@ -67,7 +89,11 @@ func (r *Reconciler) computeDiscoverable(
 	if observed.TTL <= desired.TTL {
 		desired.TTL = observed.TTL
 		if observed.Equal(desired) {
-			crd.Discovered(r.Manager, res)
+			// As with RecordsVerified, only record an event when the
+			// instance first becomes discoverable, not on every resync.
+			if res.Condition(crd.ConditionTypeDiscoverable).Status != metav1.ConditionTrue {
+				crd.Discovered(r.Manager, res)
+			}
 			return observed.TTL, crd.DiscoveredCondition()
 		}
 	}
@@ -75,3 +101,13 @@ func (r *Reconciler) computeDiscoverable(
 	crd.LookupResultOutOfSync(r.Manager, res)
 	return observed.TTL, crd.LookupResultOutOfSyncCondition()
 }
+
+// containsInstance returns true if instances contains the given instance name.
+func containsInstance(instances []string, name string) bool {
+	return slices.ContainsFunc(
+		instances,
+		func(v string) bool {
+			return strings.EqualFold(v, name)
+		},
+	)
+}
@@ -0,0 +1,410 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// providerRecheckInterval is the interval at which DNSProvider resources are
+// reconciled, so that their credentials are periodically re-verified, and any
+// changes to their credentials secret are loaded.
+const providerRecheckInterval = 5 * time.Minute
+
+// providerInUsePollInterval is the interval at which a deleted DNSProvider
+// resource is reconciled while service instances or browse domains are still
+// associated with its provider.
+const providerInUsePollInterval = 30 * time.Second
+
+// enqueueRetryInterval is the interval at which a DNSProvider resource is
+// reconciled again when the events for its associated resources could not all
+// be sent because the event channels were full.
+const enqueueRetryInterval = 5 * time.Second
+
+// unknownProviderRetryInterval is the interval at which a resource that is
+// associated with an unknown provider is retried when DNSProvider resources are
+// enabled.
+const unknownProviderRetryInterval = 1 * time.Minute
+
+// ReconcileProvider performs a full reconciliation for the object referred to
+// by the Request, which must be a crd.DNSProvider.
+//
+// It creates a provider from the resource and adds it to the set of providers
+// used to advertise service instances and browse domains, or removes it if the
+// resource has been deleted.
+//
+// The resource's finalizer prevents its deletion, and keeps the provider
+// available, until all of the service instances and browse domains associated
+// with the provider have been unadvertised.
+func (r *Reconciler) ReconcileProvider(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	res := &crd.DNSProvider{}
+	if err := r.Client.Get(ctx, req.NamespacedName, res); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			r.removeProvider(req.Name)
+		}
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	recheckInterval := providerRecheckInterval
+
+	if res.DeletionTimestamp.IsZero() {
+		if controllerutil.AddFinalizer(res, crd.FinalizerName) {
+			if err := r.Client.Update(ctx, res); err != nil {
+				return reconcile.Result{}, fmt.Errorf("unable to add finalizer: %w", err)
+			}
+		}
+	} else {
+		refs, err := r.associatedResources(ctx, res.Status.Provider)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if len(refs) == 0 {
+			r.removeProvider(res.Name)
+
+			controllerutil.RemoveFinalizer(res, crd.FinalizerName)
+			if err := r.Client.Update(ctx, res); err != nil {
+				return reconcile.Result{}, fmt.Errorf("unable to remove finalizer: %w", err)
+			}
+
+			return reconcile.Result{}, nil
+		}
+
+		// The provider must remain available so that the records of the
+		// associated resources can be removed when they are deleted.
+		crd.ProviderInUse(r.Manager, res, len(refs))
+		recheckInterval = providerInUsePollInterval
+	}
+
+	p, reason, err := r.newProvider(ctx, res)
+
+	if err == nil {
+		if !r.addProvider(res.Name, p) {
+			reason = "DuplicateProvider"
+			err = fmt.Errorf("another provider has the same ID (%s)", p.ID())
+		}
+	}
+
+	if err != nil {
+		r.removeProvider(res.Name)
+
+		if res.Condition(crd.ConditionTypeReady).Reason != reason {
+			crd.ProviderNotReady(r.Manager, res, reason, err)
+		}
+
+		return reconcile.Result{RequeueAfter: recheckInterval}, r.update(
+			res,
+			crd.MergeCondition(crd.ProviderNotReadyCondition(reason, err)),
+		)
+	}
+
+	wasReady := res.Condition(crd.ConditionTypeReady).Status == metav1.ConditionTrue
+
+	if err := r.update(
+		res,
+		crd.MergeCondition(crd.ProviderReadyCondition()),
+		crd.UpdateProviderDescription(p.Describe()),
+		crd.AssociateProvider(p.ID(), nil),
+	); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !wasReady {
+		crd.ProviderReady(r.Manager, res)
+	}
+
+	sent, err := r.enqueueAssociatedResources(ctx, res.Name, p.ID())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !sent {
+		return reconcile.Result{RequeueAfter: enqueueRetryInterval}, nil
+	}
+
+	return reconcile.Result{RequeueAfter: recheckInterval}, nil
+}
+
+// InstanceEvents returns a channel that receives an event for each service
+// instance that is associated with a provider created from a DNSProvider
+// resource, when that provider becomes available.
+//
+// It allows instances that could not be reconciled because their provider was
+// not yet known, such as when the controller has just started, to be
+// reconciled without waiting to be retried.
+func (r *Reconciler) InstanceEvents() <-chan event.GenericEvent {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.initEvents()
+	return r.instanceEvents
+}
+
+// BrowseDomainEvents returns a channel that receives an event for each browse
+// domain that is associated with a provider created from a DNSProvider
+// resource, when that provider becomes available.
+func (r *Reconciler) BrowseDomainEvents() <-chan event.GenericEvent {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.initEvents()
+	return r.browseDomainEvents
+}
+
+// initEvents creates the channels returned by InstanceEvents() and
+// BrowseDomainEvents(). r.m must be locked.
+func (r *Reconciler) initEvents() {
+	if r.instanceEvents == nil {
+		r.instanceEvents = make(chan event.GenericEvent, 100)
+		r.browseDomainEvents = make(chan event.GenericEvent, 100)
+	}
+}
+
+// enqueueAssociatedResources sends an event for each service instance and
+// browse domain that is associated with the provider created from the
+// DNSProvider resource with the given name, if that provider has been added
+// since the events were last sent.
+//
+// Events are only sent to the channels returned by InstanceEvents() and
+// BrowseDomainEvents() if they have been requested.
+//
+// Sends never block, so that full channels can not stall the reconciliation.
+// It returns false if some events could not be sent, in which case the
+// remaining events are sent by the next call. r.enqueued has an entry for each
+// DNSProvider resource with pending events, containing the keys of the
+// resources for which events have already been sent.
+func (r *Reconciler) enqueueAssociatedResources(
+	ctx context.Context,
+	name, id string,
+) (bool, error) {
+	r.m.RLock()
+	instances, browseDomains := r.instanceEvents, r.browseDomainEvents
+	sent, pending := r.enqueued[name]
+	sent = maps.Clone(sent)
+	r.m.RUnlock()
+
+	if !pending {
+		return true, nil
+	}
+
+	complete := true
+
+	if instances != nil {
+		refs, err := r.associatedResources(ctx, id)
+		if err != nil {
+			return false, err
+		}
+
+		for _, obj := range refs {
+			key := client.ObjectKeyFromObject(obj)
+			if _, ok := sent[key]; ok {
+				continue
+			}
+
+			events := instances
+			if _, ok := obj.(*crd.DNSSDBrowseDomain); ok {
+				events = browseDomains
+			}
+
+			select {
+			case events <- event.GenericEvent{Object: obj}:
+				sent[key] = struct{}{}
+			default:
+				complete = false
+			}
+
+			if !complete {
+				break
+			}
+		}
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// The provider may have been removed in the meantime.
+	if _, ok := r.enqueued[name]; ok {
+		if complete {
+			delete(r.enqueued, name)
+		} else {
+			r.enqueued[name] = sent
+		}
+	}
+
+	return complete, nil
+}
+
+// associatedResources returns the service instances and browse domains that
+// are associated with the provider with the given ID.
+func (r *Reconciler) associatedResources(ctx context.Context, id string) ([]client.Object, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	instances := &crd.DNSSDServiceInstanceList{}
+	if err := r.Client.List(ctx, instances); err != nil {
+		return nil, fmt.Errorf("unable to list service instances: %w", err)
+	}
+
+	browseDomains := &crd.DNSSDBrowseDomainList{}
+	if err := r.Client.List(ctx, browseDomains); err != nil {
+		return nil, fmt.Errorf("unable to list browse domains: %w", err)
+	}
+
+	var refs []client.Object
+
+	for i := range instances.Items {
+		if res := &instances.Items[i]; res.Status.Provider == id {
+			refs = append(refs, res)
+		}
+	}
+
+	for i := range browseDomains.Items {
+		if res := &browseDomains.Items[i]; res.Status.Provider == id {
+			refs = append(refs, res)
+		}
+	}
+
+	return refs, nil
+}
+
+// unknownProviderResult returns the result of reconciling a resource that is
+// associated with a provider that is not known to this reconciler.
+func (r *Reconciler) unknownProviderResult() reconcile.Result {
+	if r.NewProvider == nil {
+		// The resource is likely managed by some other instance of Proclaim.
+		return reconcile.Result{}
+	}
+
+	// The provider may be created from a DNSProvider resource that has not
+	// been reconciled yet. The resource is reconciled again as soon as the
+	// provider becomes available, but it's retried periodically in case the
+	// provider is never created by this reconciler.
+	return reconcile.Result{RequeueAfter: unknownProviderRetryInterval}
+}
+
+// newProvider returns the provider described by res, and verifies that it is
+// healthy.
+//
+// If an error occurs, reason is a short machine-readable explanation suitable
+// for use as a condition reason.
+func (r *Reconciler) newProvider(
+	ctx context.Context,
+	res *crd.DNSProvider,
+) (_ provider.Provider, reason string, _ error) {
+	if r.NewProvider == nil {
+		return nil, "ProviderResourcesDisabled", errors.New("this controller does not support DNSProvider resources")
+	}
+
+	p, err := r.NewProvider(ctx, res)
+	if err != nil {
+		return nil, "ConfigurationError", err
+	}
+
+	if len(res.Spec.Zones) != 0 {
+		p = provider.FilterZones(p, res.Spec.Zones)
+	}
+
+	if hc, ok := p.(provider.HealthChecker); ok {
+		ctx, cancel := context.WithTimeout(ctx, provider.Timeout)
+		defer cancel()
+
+		start := time.Now()
+		err := hc.CheckHealth(ctx)
+		observeOperation(p.ID(), operationCheckHealth, start, provider.ChangeSet{}, err)
+
+		if err != nil {
+			return nil, "HealthCheckFailed", err
+		}
+	}
+
+	return p, "", nil
+}
+
+// providers returns all of the providers that are available to the
+// reconciler, including those created from DNSProvider resources.
+func (r *Reconciler) providers() []provider.Provider {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	if len(r.dynamic) == 0 {
+		return r.Providers
+	}
+
+	names := maps.Keys(r.dynamic)
+	slices.Sort(names)
+
+	providers := slices.Clone(r.Providers)
+	for _, n := range names {
+		providers = append(providers, r.dynamic[n])
+	}
+
+	return providers
+}
+
+// addProvider adds (or replaces) the provider created from the DNSProvider
+// resource with the given name.
+//
+// If a provider with the same ID was not already available, the events for its
+// associated resources are marked as pending, see enqueueAssociatedResources().
+//
+// It returns false if the provider's ID conflicts with that of some other
+// provider.
+func (r *Reconciler) addProvider(name string, p provider.Provider) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for _, x := range r.Providers {
+		if x.ID() == p.ID() {
+			return false
+		}
+	}
+
+	for n, x := range r.dynamic {
+		if n != name && x.ID() == p.ID() {
+			return false
+		}
+	}
+
+	if r.dynamic == nil {
+		r.dynamic = map[string]provider.Provider{}
+	}
+
+	if x, ok := r.dynamic[name]; !ok || x.ID() != p.ID() {
+		if r.enqueued == nil {
+			r.enqueued = map[string]map[client.ObjectKey]struct{}{}
+		}
+		r.enqueued[name] = map[client.ObjectKey]struct{}{}
+	}
+
+	r.dynamic[name] = p
+
+	return true
+}
+
+// removeProvider removes the provider created from the DNSProvider resource
+// with the given name, if any.
+func (r *Reconciler) removeProvider(name string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	delete(r.dynamic, name)
+	delete(r.enqueued, name)
+}
@@ -0,0 +1,339 @@
+package reconciler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/memoryprovider"
+	. "github.com/dogmatiq/proclaim/reconciler"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("func (*Reconciler) ReconcileProvider()", func() {
+	var (
+		ctx        context.Context
+		cli        client.Client
+		recorder   *record.FakeRecorder
+		dnsp       *memoryprovider.Provider
+		reconciler *Reconciler
+		res        *crd.DNSProvider
+		inst       *crd.DNSSDServiceInstance
+		req        reconcile.Request
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		DeferCleanup(cancel)
+
+		dnsp = &memoryprovider.Provider{
+			Domains: []string{domain},
+			Logger:  logr.Discard(),
+		}
+
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		go dnsp.Serve(ctx, conn) //nolint:errcheck
+
+		_, port, err := net.SplitHostPort(conn.LocalAddr().String())
+		Expect(err).ShouldNot(HaveOccurred())
+
+		scheme := runtime.NewScheme()
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+
+		res = &crd.DNSProvider{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "memory",
+			},
+			Spec: crd.DNSProviderSpec{
+				Type: "memory",
+			},
+		}
+
+		inst = &crd.DNSSDServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "instance",
+			},
+			Spec: crd.DNSSDServiceInstanceSpec{
+				Instance: crd.Instance{
+					Name:        "instance",
+					ServiceType: "_proclaim._tcp",
+					Domain:      domain,
+					TTL:         metav1.Duration{Duration: 5 * time.Second},
+					Targets: []crd.Target{
+						{Host: "host.example.com", Port: 443},
+					},
+				},
+			},
+		}
+
+		req = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name: res.Name,
+			},
+		}
+
+		cli = fake.
+			NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(res, inst).
+			Build()
+
+		recorder = record.NewFakeRecorder(100)
+
+		reconciler = &Reconciler{
+			Manager: &managerStub{Recorder: recorder},
+			Client:  cli,
+			Resolver: &dnssd.UnicastResolver{
+				Config: &dns.ClientConfig{
+					Servers:  []string{"127.0.0.1"},
+					Port:     port,
+					Ndots:    1,
+					Timeout:  1,
+					Attempts: 3,
+				},
+			},
+			NewProvider: func(_ context.Context, r *crd.DNSProvider) (provider.Provider, error) {
+				switch r.Spec.Type {
+				case "memory":
+					return dnsp, nil
+				case "unhealthy":
+					return &unhealthyProvider{Provider: dnsp, IDValue: "unhealthy"}, nil
+				default:
+					return nil, errors.New("unsupported provider type")
+				}
+			},
+		}
+	})
+
+	reconcileProvider := func() (*crd.DNSProvider, reconcile.Result) {
+		result, err := reconciler.ReconcileProvider(ctx, req)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		r := &crd.DNSProvider{}
+		if err := cli.Get(ctx, req.NamespacedName, r); err != nil {
+			Expect(client.IgnoreNotFound(err)).ShouldNot(HaveOccurred())
+			return nil, result
+		}
+
+		return r, result
+	}
+
+	// adoptInstance reconciles the service instance and returns the ID of the
+	// provider that adopted it, if any.
+	adoptInstance := func() string {
+		for i := 0; i < 10; i++ {
+			result, err := reconciler.Reconcile(
+				ctx,
+				reconcile.Request{
+					NamespacedName: client.ObjectKeyFromObject(inst),
+				},
+			)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			if !result.Requeue {
+				break
+			}
+		}
+
+		r := &crd.DNSSDServiceInstance{}
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(inst), r)).To(Succeed())
+		return r.Status.Provider
+	}
+
+	It("makes the provider available to the reconciler", func() {
+		r, result := reconcileProvider()
+
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(r.Status.Provider).To(Equal("memory"))
+		Expect(r.Status.ProviderDescription).To(Equal(dnsp.Describe()))
+		Expect(r.Condition(crd.ConditionTypeReady).Status).To(Equal(metav1.ConditionTrue))
+		Expect(recorder.Events).To(Receive(ContainSubstring("ProviderReady")))
+
+		Expect(adoptInstance()).To(Equal("memory"))
+	})
+
+	It("removes the provider when the resource is deleted", func() {
+		reconcileProvider()
+
+		Expect(cli.Delete(ctx, res)).To(Succeed())
+		reconcileProvider()
+
+		Expect(adoptInstance()).To(BeEmpty())
+	})
+
+	It("retains the provider until its instances are unadvertised", func() {
+		reconcileProvider()
+		Expect(adoptInstance()).To(Equal("memory"))
+
+		Expect(cli.Delete(ctx, res)).To(Succeed())
+
+		r, result := reconcileProvider()
+		Expect(r).NotTo(BeNil())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("ProviderInUse")))
+
+		Expect(cli.Delete(ctx, inst)).To(Succeed())
+		for i := 0; i < 10; i++ {
+			_, err := reconciler.Reconcile(
+				ctx,
+				reconcile.Request{
+					NamespacedName: client.ObjectKeyFromObject(inst),
+				},
+			)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = cli.Get(ctx, client.ObjectKeyFromObject(inst), &crd.DNSSDServiceInstance{})
+			if err != nil {
+				Expect(client.IgnoreNotFound(err)).ShouldNot(HaveOccurred())
+				break
+			}
+		}
+
+		r, _ = reconcileProvider()
+		Expect(r).To(BeNil())
+	})
+
+	It("enqueues the associated resources when the provider becomes available", func() {
+		inst.Status.Provider = "memory"
+		Expect(cli.Status().Update(ctx, inst)).To(Succeed())
+
+		events := reconciler.InstanceEvents()
+		reconcileProvider()
+
+		var ev event.GenericEvent
+		Expect(events).To(Receive(&ev))
+		Expect(ev.Object.GetName()).To(Equal(inst.Name))
+
+		// The provider is already available when it's reconciled again.
+		reconcileProvider()
+		Expect(events).NotTo(Receive())
+	})
+
+	It("does not block when the event channels are full", func() {
+		events := reconciler.InstanceEvents()
+
+		inst.Status.Provider = "memory"
+		Expect(cli.Status().Update(ctx, inst)).To(Succeed())
+
+		// Associate one more instance with the provider than will fit in the
+		// channel's buffer.
+		for i := 0; i < cap(events); i++ {
+			x := &crd.DNSSDServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: inst.Namespace,
+					Name:      fmt.Sprintf("instance-%d", i),
+				},
+				Spec: inst.Spec,
+			}
+			Expect(cli.Create(ctx, x)).To(Succeed())
+
+			x.Status.Provider = "memory"
+			Expect(cli.Status().Update(ctx, x)).To(Succeed())
+		}
+
+		_, result := reconcileProvider()
+		Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+		Expect(events).To(HaveLen(cap(events)))
+
+		for len(events) > 0 {
+			<-events
+		}
+
+		// The remaining event is sent once there is room in the channel.
+		_, result = reconcileProvider()
+		Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+		Expect(events).To(HaveLen(1))
+	})
+
+	It("retries unadvertising instances associated with a provider that is not yet available", func() {
+		inst.Finalizers = []string{crd.FinalizerName}
+		Expect(cli.Update(ctx, inst)).To(Succeed())
+
+		inst.Status.Provider = "memory"
+		Expect(cli.Status().Update(ctx, inst)).To(Succeed())
+
+		Expect(cli.Delete(ctx, inst)).To(Succeed())
+
+		var result reconcile.Result
+		for i := 0; i < 10; i++ {
+			var err error
+			result, err = reconciler.Reconcile(
+				ctx,
+				reconcile.Request{
+					NamespacedName: client.ObjectKeyFromObject(inst),
+				},
+			)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			if !result.Requeue {
+				break
+			}
+		}
+
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(inst), inst)).To(Succeed())
+	})
+
+	It("restricts the provider to the specified zones", func() {
+		res.Spec.Zones = []string{"other." + domain}
+		Expect(cli.Update(ctx, res)).To(Succeed())
+
+		r, _ := reconcileProvider()
+		Expect(r.Status.ProviderDescription).To(ContainSubstring("restricted to other." + domain))
+
+		Expect(adoptInstance()).To(BeEmpty())
+	})
+
+	It("reports a configuration error", func() {
+		res.Spec.Type = "unknown"
+		Expect(cli.Update(ctx, res)).To(Succeed())
+
+		r, result := reconcileProvider()
+
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		c := r.Condition(crd.ConditionTypeReady)
+		Expect(c.Status).To(Equal(metav1.ConditionFalse))
+		Expect(c.Reason).To(Equal("ConfigurationError"))
+		Expect(c.Message).To(Equal("unsupported provider type"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("ConfigurationError")))
+		Expect(adoptInstance()).To(BeEmpty())
+	})
+
+	It("reports a failed health check", func() {
+		res.Spec.Type = "unhealthy"
+		Expect(cli.Update(ctx, res)).To(Succeed())
+
+		r, _ := reconcileProvider()
+
+		Expect(r.Condition(crd.ConditionTypeReady).Reason).To(Equal("HealthCheckFailed"))
+		Expect(adoptInstance()).To(BeEmpty())
+	})
+
+	It("reports a provider with a duplicate ID", func() {
+		reconciler.Providers = []provider.Provider{dnsp}
+
+		r, _ := reconcileProvider()
+
+		Expect(r.Condition(crd.ConditionTypeReady).Reason).To(Equal("DuplicateProvider"))
+	})
+})
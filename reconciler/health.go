@@ -0,0 +1,194 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// healthCheckInterval is the interval at which ProviderHealthMonitor checks
+// the health of each provider.
+//
+// The results are cached between checks so that frequent readiness probes and
+// metrics scrapes neither wait on the provider's API nor exhaust its rate
+// limits.
+const healthCheckInterval = 1 * time.Minute
+
+// HealthChecks returns readiness checks for the reconciler's dependencies,
+// keyed by name.
+//
+// There is one check named "resolver", which verifies that the DNS resolver
+// configuration was loaded, and a check named "provider-<id>" for each
+// provider in r.Providers that implements provider.HealthChecker.
+//
+// The provider checks report the most recent result obtained by
+// ProviderHealthMonitor; they never call the provider's API themselves. They
+// fail until the provider's health has been checked for the first time.
+func (r *Reconciler) HealthChecks() map[string]healthz.Checker {
+	checks := map[string]healthz.Checker{
+		"resolver": r.checkResolver,
+	}
+
+	for _, p := range r.Providers {
+		if _, ok := p.(provider.HealthChecker); ok {
+			id := p.ID()
+			checks["provider-"+id] = func(*http.Request) error {
+				return r.cachedHealth(id)
+			}
+		}
+	}
+
+	return checks
+}
+
+// checkResolver returns an error if the DNS resolver configuration has not
+// been loaded.
+func (r *Reconciler) checkResolver(*http.Request) error {
+	if r.Resolver == nil || r.Resolver.Config == nil {
+		return errors.New("DNS resolver configuration is not loaded")
+	}
+
+	if len(r.Resolver.Config.Servers) == 0 {
+		return errors.New("DNS resolver configuration does not contain any servers")
+	}
+
+	return nil
+}
+
+// ProviderHealthMonitor is a manager.Runnable that periodically checks the
+// health of each provider that implements provider.HealthChecker, including
+// those created from DNSProvider resources.
+//
+// The results are used by the readiness checks returned by
+// Reconciler.HealthChecks() and are reported by ProviderHealthCollector.
+type ProviderHealthMonitor struct {
+	Reconciler *Reconciler
+}
+
+// Start checks the health of each provider every healthCheckInterval until ctx
+// is canceled.
+func (m *ProviderHealthMonitor) Start(ctx context.Context) error {
+	for {
+		m.Reconciler.checkHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(healthCheckInterval):
+		}
+	}
+}
+
+// NeedLeaderElection returns false, as readiness is reported by every replica,
+// not only the leader.
+func (m *ProviderHealthMonitor) NeedLeaderElection() bool {
+	return false
+}
+
+// ProviderHealthCollector is a prometheus.Collector that reports whether each
+// provider that implements provider.HealthChecker is healthy, including those
+// created from DNSProvider resources.
+//
+// It reports the most recent results obtained by ProviderHealthMonitor, and
+// does not call the providers' APIs itself. Providers that have not yet been
+// checked are omitted.
+type ProviderHealthCollector struct {
+	Reconciler *Reconciler
+}
+
+var providerHealthyDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(metricsNamespace, "", "provider_healthy"),
+	"Whether each provider is able to communicate and authenticate with its DNS hosting provider (1) or not (0).",
+	[]string{"provider"},
+	nil,
+)
+
+// Describe sends the descriptors of the collector's metrics to ch.
+func (c *ProviderHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- providerHealthyDesc
+}
+
+// Collect sends the collector's metrics to ch.
+func (c *ProviderHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	c.Reconciler.healthM.Lock()
+	defer c.Reconciler.healthM.Unlock()
+
+	for id, err := range c.Reconciler.health {
+		healthy := 1.0
+		if err != nil {
+			healthy = 0
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			providerHealthyDesc,
+			prometheus.GaugeValue,
+			healthy,
+			id,
+		)
+	}
+}
+
+// checkHealth checks the health of each provider that implements
+// provider.HealthChecker and caches the results.
+//
+// The providers are checked concurrently so that a slow provider does not
+// delay the results for the others.
+func (r *Reconciler) checkHealth(ctx context.Context) {
+	var (
+		g       sync.WaitGroup
+		m       sync.Mutex
+		results = map[string]error{}
+	)
+
+	for _, p := range r.providers() {
+		hc, ok := p.(provider.HealthChecker)
+		if !ok {
+			continue
+		}
+
+		g.Add(1)
+		go func() {
+			defer g.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, provider.Timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := hc.CheckHealth(ctx)
+			observeOperation(hc.ID(), operationCheckHealth, start, provider.ChangeSet{}, err)
+
+			m.Lock()
+			results[hc.ID()] = err
+			m.Unlock()
+		}()
+	}
+
+	g.Wait()
+
+	// Replace the results entirely, so that providers that have since been
+	// removed are no longer reported.
+	r.healthM.Lock()
+	r.health = results
+	r.healthM.Unlock()
+}
+
+// cachedHealth returns the most recent result of checking the health of the
+// provider with the given ID.
+func (r *Reconciler) cachedHealth(id string) error {
+	r.healthM.Lock()
+	defer r.healthM.Unlock()
+
+	err, ok := r.health[id]
+	if !ok {
+		return fmt.Errorf("the health of the %s provider has not been checked yet", id)
+	}
+
+	return err
+}
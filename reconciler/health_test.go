@@ -0,0 +1,211 @@
+package reconciler_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/memoryprovider"
+	. "github.com/dogmatiq/proclaim/reconciler"
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("func (*Reconciler) HealthChecks()", func() {
+	var reconciler *Reconciler
+
+	BeforeEach(func() {
+		reconciler = &Reconciler{
+			Resolver: &dnssd.UnicastResolver{
+				Config: &dns.ClientConfig{
+					Servers: []string{"127.0.0.1"},
+					Port:    "53",
+				},
+			},
+		}
+	})
+
+	It("returns a check for the resolver", func() {
+		checks := reconciler.HealthChecks()
+		Expect(checks).To(HaveKey("resolver"))
+
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		Expect(checks["resolver"](req)).To(Succeed())
+	})
+
+	It("fails the resolver check if there are no DNS servers", func() {
+		reconciler.Resolver.Config.Servers = nil
+
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		err := reconciler.HealthChecks()["resolver"](req)
+		Expect(err).To(MatchError("DNS resolver configuration does not contain any servers"))
+	})
+
+	It("returns a check for each provider that implements provider.HealthChecker", func() {
+		reconciler.Providers = []provider.Provider{
+			&memoryprovider.Provider{},
+			&unhealthyProvider{Provider: &memoryprovider.Provider{}, IDValue: "unhealthy"},
+		}
+
+		checks := reconciler.HealthChecks()
+		Expect(checks).To(HaveLen(3))
+		Expect(checks).To(HaveKey("provider-memory"))
+		Expect(checks).To(HaveKey("provider-unhealthy"))
+	})
+
+	It("fails the provider checks until the providers have been checked", func() {
+		reconciler.Providers = []provider.Provider{
+			&memoryprovider.Provider{},
+		}
+
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		err := reconciler.HealthChecks()["provider-memory"](req)
+		Expect(err).To(MatchError("the health of the memory provider has not been checked yet"))
+	})
+
+	It("reports the most recent result without calling the provider", func() {
+		p := &unhealthyProvider{Provider: &memoryprovider.Provider{}, IDValue: "unhealthy"}
+		reconciler.Providers = []provider.Provider{
+			&memoryprovider.Provider{},
+			p,
+		}
+
+		checkHealthOnce(reconciler)
+
+		checks := reconciler.HealthChecks()
+		req := httptest.NewRequest("GET", "/readyz", nil)
+
+		Expect(checks["provider-memory"](req)).To(Succeed())
+		Expect(checks["provider-unhealthy"](req)).To(MatchError("authentication failed"))
+		Expect(checks["provider-unhealthy"](req)).To(MatchError("authentication failed"))
+		Expect(p.Calls).To(Equal(1))
+	})
+})
+
+var _ = Describe("type ProviderHealthCollector", func() {
+	var reconciler *Reconciler
+
+	BeforeEach(func() {
+		reconciler = &Reconciler{}
+	})
+
+	It("reports the health of each provider", func() {
+		reconciler.Providers = []provider.Provider{
+			&memoryprovider.Provider{},
+			&unhealthyProvider{Provider: &memoryprovider.Provider{}, IDValue: "unhealthy"},
+		}
+
+		checkHealthOnce(reconciler)
+
+		err := testutil.CollectAndCompare(
+			&ProviderHealthCollector{Reconciler: reconciler},
+			strings.NewReader(`
+# HELP proclaim_provider_healthy Whether each provider is able to communicate and authenticate with its DNS hosting provider (1) or not (0).
+# TYPE proclaim_provider_healthy gauge
+proclaim_provider_healthy{provider="memory"} 1
+proclaim_provider_healthy{provider="unhealthy"} 0
+`),
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("reports the health of providers created from DNSProvider resources", func() {
+		scheme := runtime.NewScheme()
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+
+		res := &crd.DNSProvider{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "memory",
+			},
+			Spec: crd.DNSProviderSpec{
+				Type:  "memory",
+				Zones: []string{domain},
+			},
+		}
+
+		reconciler.Manager = &managerStub{Recorder: record.NewFakeRecorder(100)}
+		reconciler.Client = fake.
+			NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(res).
+			Build()
+		reconciler.NewProvider = func(context.Context, *crd.DNSProvider) (provider.Provider, error) {
+			return &memoryprovider.Provider{}, nil
+		}
+
+		_, err := reconciler.ReconcileProvider(
+			context.Background(),
+			reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: res.Name},
+			},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		checkHealthOnce(reconciler)
+
+		err = testutil.CollectAndCompare(
+			&ProviderHealthCollector{Reconciler: reconciler},
+			strings.NewReader(`
+# HELP proclaim_provider_healthy Whether each provider is able to communicate and authenticate with its DNS hosting provider (1) or not (0).
+# TYPE proclaim_provider_healthy gauge
+proclaim_provider_healthy{provider="memory"} 1
+`),
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("does not call the providers' APIs", func() {
+		p := &unhealthyProvider{Provider: &memoryprovider.Provider{}, IDValue: "unhealthy"}
+		reconciler.Providers = []provider.Provider{p}
+
+		c := &ProviderHealthCollector{Reconciler: reconciler}
+		Expect(testutil.CollectAndCount(c)).To(Equal(0))
+		Expect(p.Calls).To(Equal(0))
+
+		checkHealthOnce(reconciler)
+
+		Expect(testutil.CollectAndCount(c)).To(Equal(1))
+		Expect(testutil.CollectAndCount(c)).To(Equal(1))
+		Expect(p.Calls).To(Equal(1))
+	})
+})
+
+// checkHealthOnce checks the health of the reconciler's providers once, using
+// a ProviderHealthMonitor.
+//
+// The monitor checks the providers before waiting for the next interval, so
+// starting it with a canceled context performs exactly one check.
+func checkHealthOnce(r *Reconciler) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	m := &ProviderHealthMonitor{Reconciler: r}
+	Expect(m.Start(ctx)).To(Succeed())
+}
+
+type unhealthyProvider struct {
+	provider.Provider
+	IDValue string
+	Calls   int
+}
+
+func (p *unhealthyProvider) ID() string {
+	return p.IDValue
+}
+
+func (p *unhealthyProvider) CheckHealth(context.Context) error {
+	p.Calls++
+	return errors.New("authentication failed")
+}
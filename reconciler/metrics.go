@@ -0,0 +1,236 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsNamespace = "proclaim"
+
+// Values for the "operation" label of provider metrics.
+const (
+	operationGetAdvertiser            = "get_advertiser"
+	operationAdvertise                = "advertise"
+	operationUnadvertise              = "unadvertise"
+	operationAdvertiseBrowseDomains   = "advertise_browse_domains"
+	operationUnadvertiseBrowseDomains = "unadvertise_browse_domains"
+	operationZoneTiming               = "zone_timing"
+	operationCheckHealth              = "check_health"
+	operationCheckPropagation         = "check_propagation"
+)
+
+// Values for the "error_class" label of the provider errors metric.
+const (
+	errorClassThrottled = "throttled"
+	errorClassAuth      = "auth"
+	errorClassNotFound  = "not_found"
+	errorClassConflict  = "conflict"
+	errorClassOther     = "other"
+)
+
+var (
+	providerOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "provider_operations_total",
+			Help:      "The number of advertise/unadvertise operations performed by each provider.",
+		},
+		[]string{"provider", "operation", "result"},
+	)
+
+	providerOperationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "provider_operation_duration_seconds",
+			Help:      "The time taken to perform advertise/unadvertise operations with each provider.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"provider", "operation"},
+	)
+
+	providerErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "provider_errors_total",
+			Help:      "The number of errors returned by each provider, by the operation that failed and the class of error.",
+		},
+		[]string{"provider", "operation", "error_class"},
+	)
+
+	recordChanges = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "record_changes_total",
+			Help:      "The number of changes made to DNS records by each provider, by record type.",
+		},
+		[]string{"provider", "record_type", "change"},
+	)
+
+	discoveryResults = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "discovery_results_total",
+			Help:      "The number of DNS-SD discovery attempts, by the reason given in the resulting Discoverable condition.",
+		},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		providerOperations,
+		providerOperationDuration,
+		providerErrors,
+		recordChanges,
+		discoveryResults,
+	)
+}
+
+// observeOperation records metrics about a provider operation that began at
+// the given time.
+func observeOperation(
+	providerID, operation string,
+	start time.Time,
+	cs provider.ChangeSet,
+	err error,
+) {
+	providerOperationDuration.
+		WithLabelValues(providerID, operation).
+		Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		providerOperations.WithLabelValues(providerID, operation, "error").Inc()
+		return
+	}
+
+	providerOperations.WithLabelValues(providerID, operation, "success").Inc()
+
+	observeChange(providerID, "PTR", cs.PTR)
+	observeChange(providerID, "SRV", cs.SRV)
+	observeChange(providerID, "TXT", cs.TXT)
+}
+
+// observeChange records metrics about the changes made to a specific type of
+// DNS record.
+func observeChange(providerID, recordType string, c provider.Change) {
+	for _, x := range []struct {
+		Change provider.Change
+		Label  string
+	}{
+		{provider.Created, "created"},
+		{provider.Updated, "updated"},
+		{provider.Deleted, "deleted"},
+	} {
+		if c&x.Change != 0 {
+			recordChanges.WithLabelValues(providerID, recordType, x.Label).Inc()
+		}
+	}
+}
+
+// providerError records an event and metrics about an error returned by a
+// provider.
+func (r *Reconciler) providerError(
+	res crd.Resource,
+	providerID, providerDesc, operation string,
+	err error,
+) {
+	providerErrors.WithLabelValues(providerID, operation, errorClass(err)).Inc()
+	crd.ProviderError(r.Manager, res, providerID, providerDesc, err)
+}
+
+// errorClass returns the value of the "error_class" label for an error
+// returned by a provider.
+//
+// Errors are classified by the HTTP status code of the API response, if they
+// have one, see provider.HTTPError.
+func errorClass(err error) string {
+	if _, ok := provider.RateLimitReset(err); ok {
+		return errorClassThrottled
+	}
+
+	var e interface{ HTTPStatusCode() int }
+	if errors.As(err, &e) {
+		switch e.HTTPStatusCode() {
+		case http.StatusTooManyRequests:
+			return errorClassThrottled
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return errorClassAuth
+		case http.StatusNotFound:
+			return errorClassNotFound
+		case http.StatusConflict, http.StatusPreconditionFailed:
+			return errorClassConflict
+		}
+	}
+
+	return errorClassOther
+}
+
+// StatusCollector is a prometheus.Collector that reports the number of
+// DNS-SD service instances with each status of the Adopted, Advertised and
+// Discoverable conditions.
+type StatusCollector struct {
+	Client client.Client
+}
+
+var instancesDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(metricsNamespace, "", "instances"),
+	"The number of DNS-SD service instances, by the status of each of their conditions.",
+	[]string{"condition", "status"},
+	nil,
+)
+
+// Describe sends the descriptors of the collector's metrics to ch.
+func (c *StatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- instancesDesc
+}
+
+// Collect sends the collector's metrics to ch.
+func (c *StatusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	list := &crd.DNSSDServiceInstanceList{}
+	if err := c.Client.List(ctx, list); err != nil {
+		ch <- prometheus.NewInvalidMetric(instancesDesc, err)
+		return
+	}
+
+	types := []string{
+		crd.ConditionTypeAdopted,
+		crd.ConditionTypeAdvertised,
+		crd.ConditionTypeDiscoverable,
+	}
+
+	statuses := []metav1.ConditionStatus{
+		metav1.ConditionTrue,
+		metav1.ConditionFalse,
+		metav1.ConditionUnknown,
+	}
+
+	for _, t := range types {
+		counts := map[metav1.ConditionStatus]int{}
+
+		for _, res := range list.Items {
+			counts[res.Condition(t).Status]++
+		}
+
+		for _, s := range statuses {
+			ch <- prometheus.MustNewConstMetric(
+				instancesDesc,
+				prometheus.GaugeValue,
+				float64(counts[s]),
+				t,
+				string(s),
+			)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package reconciler
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// propagationPollInterval is the interval at which the reconciler checks
+// whether changes to DNS records have propagated to the provider's name
+// servers.
+const propagationPollInterval = 5 * time.Second
+
+// isPropagating returns true if changes made to the DNS records for the
+// current generation of the resource have not yet propagated to the provider's
+// name servers.
+func isPropagating(res *crd.DNSSDServiceInstance) bool {
+	if len(res.Status.PendingChanges) == 0 {
+		return false
+	}
+
+	a := res.Condition(crd.ConditionTypeAdvertised)
+	return a.ObservedGeneration >= res.Generation
+}
+
+// checkPropagation returns true if the resource's pending changes have
+// propagated to the provider's name servers, in which case the pending changes
+// are cleared and the instance is marked as advertised.
+//
+// A non-nil error indicates context cancelation or a problem interacting with
+// Kubernetes itself.
+func (r *Reconciler) checkPropagation(
+	ctx context.Context,
+	res *crd.DNSSDServiceInstance,
+) (bool, error) {
+	a, ok, err := r.getAdvertiser(ctx, res)
+	if !ok || err != nil {
+		return false, err
+	}
+
+	// If the advertiser no longer reports on propagation there's nothing to
+	// wait for.
+	if pa, ok := a.(provider.PropagationAdvertiser); ok {
+		start := time.Now()
+		ok, err := pa.IsPropagated(ctx, res.Status.PendingChanges)
+		observeOperation(res.Status.Provider, operationCheckPropagation, start, provider.ChangeSet{}, err)
+
+		if err != nil {
+			r.providerError(
+				res,
+				res.Status.Provider,
+				res.Status.ProviderDescription,
+				operationCheckPropagation,
+				err,
+			)
+			return false, ctx.Err()
+		}
+
+		if !ok {
+			return false, nil
+		}
+	}
+
+	crd.DNSRecordsPropagated(r.Manager, res)
+
+	return true, r.update(
+		res,
+		crd.SetPendingChanges(nil),
+		crd.If(
+			res.Condition(crd.ConditionTypeAdvertised).Status != metav1.ConditionTrue,
+			crd.MergeCondition(crd.DNSRecordsPropagatedCondition()),
+		),
+	)
+}
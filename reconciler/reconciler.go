@@ -3,6 +3,7 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/dogmatiq/dissolve/dnssd"
@@ -10,6 +11,7 @@ import (
 	"github.com/dogmatiq/proclaim/provider"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -21,6 +23,34 @@ type Reconciler struct {
 	Client    client.Client
 	Resolver  *dnssd.UnicastResolver
 	Providers []provider.Provider
+
+	// ResyncInterval is the interval at which resources that are already in
+	// sync are re-verified against the DNS records in their zone, so that
+	// records modified or deleted outside of Proclaim are repaired.
+	//
+	// If it is zero, resources are not re-verified once they are in sync.
+	ResyncInterval time.Duration
+
+	// NewProvider returns the provider described by a crd.DNSProvider
+	// resource. It is responsible for loading any credentials referenced by
+	// the resource.
+	//
+	// Providers created from DNSProvider resources are used in addition to
+	// those in Providers. If NewProvider is nil, DNSProvider resources are
+	// reported as not ready.
+	NewProvider func(context.Context, *crd.DNSProvider) (provider.Provider, error)
+
+	m                  sync.RWMutex
+	dynamic            map[string]provider.Provider
+	enqueued           map[string]map[client.ObjectKey]struct{}
+	instanceEvents     chan event.GenericEvent
+	browseDomainEvents chan event.GenericEvent
+
+	timingM sync.Mutex
+	timings map[string]cachedZoneTiming
+
+	healthM sync.Mutex
+	health  map[string]error
 }
 
 // Reconcile performs a full reconciliation for the object referred to by the
@@ -81,7 +111,7 @@ func (r *Reconciler) initialize(
 }
 
 func (r *Reconciler) update(
-	res *crd.DNSSDServiceInstance,
+	res crd.Resource,
 	updates ...crd.StatusUpdate,
 ) error {
 	// Build our own context with a timeout, so that we don't block forever, but
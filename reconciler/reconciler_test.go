@@ -0,0 +1,641 @@
+package reconciler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/dissolve/dnssd"
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/internal/dnssdx"
+	"github.com/dogmatiq/proclaim/provider"
+	"github.com/dogmatiq/proclaim/provider/memoryprovider"
+	. "github.com/dogmatiq/proclaim/reconciler"
+	"github.com/go-logr/logr"
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const domain = "proclaim-test.example.org"
+
+var _ = Describe("type Reconciler", func() {
+	var (
+		ctx        context.Context
+		cli        client.Client
+		recorder   *record.FakeRecorder
+		dnsp       *memoryprovider.Provider
+		resolver   *dnssd.UnicastResolver
+		reconciler *Reconciler
+		res        *crd.DNSSDServiceInstance
+		req        reconcile.Request
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		DeferCleanup(cancel)
+
+		dnsp = &memoryprovider.Provider{
+			Domains: []string{domain},
+			Logger:  logr.Discard(),
+		}
+
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		go dnsp.Serve(ctx, conn) //nolint:errcheck
+
+		_, port, err := net.SplitHostPort(conn.LocalAddr().String())
+		Expect(err).ShouldNot(HaveOccurred())
+
+		resolver = &dnssd.UnicastResolver{
+			Config: &dns.ClientConfig{
+				Servers:  []string{"127.0.0.1"},
+				Port:     port,
+				Ndots:    1,
+				Timeout:  1,
+				Attempts: 3,
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+
+		res = &crd.DNSSDServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "instance",
+			},
+			Spec: crd.DNSSDServiceInstanceSpec{
+				Instance: crd.Instance{
+					Name:        "instance",
+					ServiceType: "_proclaim._tcp",
+					Domain:      domain,
+					TTL:         metav1.Duration{Duration: 5 * time.Second},
+					Targets: []crd.Target{
+						{
+							Host:     "host.example.com",
+							Port:     443,
+							Priority: 10,
+							Weight:   20,
+						},
+						{
+							Host:     "backup.example.com",
+							Port:     443,
+							Priority: 20,
+						},
+					},
+					Attributes: []map[string]any{
+						{"key": "value"},
+					},
+				},
+			},
+		}
+
+		req = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: res.Namespace,
+				Name:      res.Name,
+			},
+		}
+
+		cli = fake.
+			NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(res).
+			Build()
+
+		recorder = record.NewFakeRecorder(100)
+
+		reconciler = &Reconciler{
+			Manager:   &managerStub{Recorder: recorder},
+			Client:    cli,
+			Resolver:  resolver,
+			Providers: []provider.Provider{dnsp},
+		}
+	})
+
+	// reconcileUntilSettled calls Reconcile() until it no longer requests an immediate
+	// requeue, then returns the current state of the resource.
+	reconcileUntilSettled := func() (*crd.DNSSDServiceInstance, reconcile.Result) {
+		for i := 0; i < 10; i++ {
+			result, err := reconciler.Reconcile(ctx, req)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			if !result.Requeue {
+				r := &crd.DNSSDServiceInstance{}
+				if err := cli.Get(ctx, req.NamespacedName, r); err != nil {
+					Expect(client.IgnoreNotFound(err)).ShouldNot(HaveOccurred())
+					return nil, result
+				}
+				return r, result
+			}
+		}
+
+		Fail("reconciler did not settle")
+		return nil, reconcile.Result{}
+	}
+
+	Describe("func Reconcile()", func() {
+		It("advertises the instance and verifies that it is discoverable", func() {
+			r, result := reconcileUntilSettled()
+			Expect(result).To(Equal(reconcile.Result{}))
+
+			Expect(r.Finalizers).To(ContainElement(crd.FinalizerName))
+			Expect(r.Status.Provider).To(Equal("memory"))
+			Expect(r.Condition(crd.ConditionTypeAdopted).Status).To(Equal(metav1.ConditionTrue))
+			Expect(r.Condition(crd.ConditionTypeAdvertised).Reason).To(Equal("RecordsCreated"))
+			Expect(r.Condition(crd.ConditionTypeDiscoverable).Reason).To(Equal("Discovered"))
+
+			inst, ok, err := dnssdx.LookupInstance(ctx, resolver, "instance", "_proclaim._tcp", domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			desired, err := r.Spec.ToServiceInstance()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(inst.Equal(desired)).To(BeTrue())
+
+			serviceTypes, err := resolver.EnumerateServiceTypes(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(serviceTypes).To(ConsistOf("_proclaim._tcp"))
+
+			Expect(recorder.Events).To(Receive(Equal("Normal InstanceAdopted In-memory can advertise on \"" + domain + "\"")))
+			Expect(recorder.Events).To(Receive(Equal("Normal RecordsCreated created new DNS records")))
+			Expect(recorder.Events).To(Receive(Equal("Normal Discovered instance discovered")))
+		})
+
+		It("advertises the instance under each of its subtypes", func() {
+			r := &crd.DNSSDServiceInstance{}
+			Expect(cli.Get(ctx, req.NamespacedName, r)).To(Succeed())
+			r.Spec.Instance.Subtypes = []string{"_printer", "_color"}
+			Expect(cli.Update(ctx, r)).To(Succeed())
+
+			r, result := reconcileUntilSettled()
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(r.Condition(crd.ConditionTypeDiscoverable).Reason).To(Equal("Discovered"))
+
+			for _, st := range []string{"_printer", "_color"} {
+				instances, err := resolver.EnumerateInstancesBySubType(ctx, st, "_proclaim._tcp", domain)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(instances).To(ConsistOf("instance"))
+			}
+
+			// Remove one of the subtypes. The fake client does not maintain
+			// the generation, so we bump it manually.
+			r.Spec.Instance.Subtypes = []string{"_printer"}
+			r.Generation++
+			Expect(cli.Update(ctx, r)).To(Succeed())
+
+			r, result = reconcileUntilSettled()
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(r.Condition(crd.ConditionTypeDiscoverable).Reason).To(Equal("Discovered"))
+
+			instances, err := resolver.EnumerateInstancesBySubType(ctx, "_printer", "_proclaim._tcp", domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(instances).To(ConsistOf("instance"))
+
+			instances, err = resolver.EnumerateInstancesBySubType(ctx, "_color", "_proclaim._tcp", domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(instances).To(BeEmpty())
+		})
+
+		It("recreates records that are removed outside of the controller", func() {
+			reconcileUntilSettled()
+			dnsp.DeleteRecords()
+
+			// The controller only re-advertises once the instance is no longer
+			// discoverable. It waits for the negative response to expire from
+			// resolver caches, based on the zone's SOA record.
+			r, result := reconcileUntilSettled()
+			Expect(result.RequeueAfter).To(Equal(2 * time.Second))
+			Expect(r.Condition(crd.ConditionTypeDiscoverable).Reason).To(Equal("NegativeBrowseResult"))
+
+			r, result = reconcileUntilSettled()
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(r.Condition(crd.ConditionTypeAdvertised).Reason).To(Equal("RecordsCreated"))
+			Expect(r.Condition(crd.ConditionTypeDiscoverable).Reason).To(Equal("Discovered"))
+		})
+
+		When("a resync interval is configured", func() {
+			BeforeEach(func() {
+				reconciler.ResyncInterval = 10 * time.Minute
+			})
+
+			It("requeues instances that are in sync after the resync interval", func() {
+				r, result := reconcileUntilSettled()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Minute}))
+				Expect(r.Condition(crd.ConditionTypeDiscoverable).Reason).To(Equal("Discovered"))
+			})
+
+			It("repairs records that are removed outside of the controller", func() {
+				reconcileUntilSettled()
+				dnsp.DeleteRecords()
+
+				for len(recorder.Events) > 0 {
+					<-recorder.Events
+				}
+
+				r, result := reconcileUntilSettled()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Minute}))
+				Expect(r.Condition(crd.ConditionTypeAdvertised).Reason).To(Equal("RecordsCreated"))
+				Expect(r.Condition(crd.ConditionTypeDiscoverable).Reason).To(Equal("Discovered"))
+
+				Expect(recorder.Events).To(Receive(Equal("Warning DriftDetected DNS records no longer match the specification, repairing")))
+				Expect(recorder.Events).To(Receive(Equal("Normal RecordsCreated created new DNS records")))
+				Expect(recorder.Events).NotTo(Receive())
+			})
+
+			It("does not record any events when the records are unchanged", func() {
+				reconcileUntilSettled()
+
+				for len(recorder.Events) > 0 {
+					<-recorder.Events
+				}
+
+				r, _ := reconcileUntilSettled()
+				Expect(r.Condition(crd.ConditionTypeAdvertised).Reason).To(Equal("RecordsCreated"))
+				Expect(recorder.Events).NotTo(Receive())
+			})
+		})
+
+		It("unadvertises the instance when the resource is deleted", func() {
+			r, _ := reconcileUntilSettled()
+			Expect(cli.Delete(ctx, r)).To(Succeed())
+
+			r, result := reconcileUntilSettled()
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(r).To(BeNil(), "finalizer was not removed")
+
+			instances, err := resolver.EnumerateInstances(ctx, "_proclaim._tcp", domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(instances).To(BeEmpty())
+
+			serviceTypes, err := resolver.EnumerateServiceTypes(ctx, domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(serviceTypes).To(BeEmpty())
+		})
+
+		It("does not advertise instances with unsupported attribute values", func() {
+			r := &crd.DNSSDServiceInstance{}
+			Expect(cli.Get(ctx, req.NamespacedName, r)).To(Succeed())
+			r.Spec.Instance.Attributes = []map[string]any{
+				{"key": []any{"value"}},
+			}
+			Expect(cli.Update(ctx, r)).To(Succeed())
+
+			r, result := reconcileUntilSettled()
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(r.Condition(crd.ConditionTypeAdvertised).Reason).To(Equal("InvalidSpec"))
+
+			instances, err := resolver.EnumerateInstances(ctx, "_proclaim._tcp", domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(instances).To(BeEmpty())
+		})
+
+		It("unadvertises instances with unsupported attribute values when the resource is deleted", func() {
+			r, _ := reconcileUntilSettled()
+			r.Spec.Instance.Attributes = []map[string]any{
+				{"key": []any{"value"}},
+			}
+			Expect(cli.Update(ctx, r)).To(Succeed())
+			Expect(cli.Delete(ctx, r)).To(Succeed())
+
+			r, result := reconcileUntilSettled()
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(r).To(BeNil(), "finalizer was not removed")
+
+			instances, err := resolver.EnumerateInstances(ctx, "_proclaim._tcp", domain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(instances).To(BeEmpty())
+		})
+
+		It("waits for the provider's rate limit to reset before retrying", func() {
+			reset := time.Now().Add(1 * time.Minute)
+			reconciler.Providers = []provider.Provider{
+				&failingProvider{
+					dnsp,
+					provider.RateLimitError{
+						Reset: reset,
+						Cause: errors.New("rate limit exceeded"),
+					},
+				},
+			}
+
+			r, result := reconcileUntilSettled()
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Until(reset), time.Second))
+			Expect(r.Condition(crd.ConditionTypeAdvertised).Reason).To(Equal("AdvertiseError"))
+		})
+
+		It("caches the zone's timing information", func() {
+			p := &undiscoverableProvider{Provider: dnsp}
+			reconciler.Providers = []provider.Provider{p}
+
+			for i := 0; i < 3; i++ {
+				r, result := reconcileUntilSettled()
+				Expect(result.RequeueAfter).To(Equal(31 * time.Second))
+				Expect(r.Condition(crd.ConditionTypeDiscoverable).Reason).To(Equal("NegativeBrowseResult"))
+			}
+
+			Expect(p.ZoneTimingCalls).To(Equal(1))
+		})
+
+		It("waits for changes to propagate before verifying discoverability", func() {
+			p := &propagatingProvider{Provider: dnsp}
+			reconciler.Providers = []provider.Provider{p}
+
+			r, result := reconcileUntilSettled()
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(r.Status.PendingChanges).To(ConsistOf("change-1"))
+			Expect(r.Condition(crd.ConditionTypeAdvertised).Reason).To(Equal("Propagating"))
+			Expect(r.Condition(crd.ConditionTypeDiscoverable).Status).To(Equal(metav1.ConditionUnknown))
+
+			p.Propagated = true
+
+			r, _ = reconcileUntilSettled()
+			Expect(r.Status.PendingChanges).To(BeEmpty())
+			Expect(r.Condition(crd.ConditionTypeAdvertised).Reason).To(Equal("RecordsPropagated"))
+			Expect(r.Condition(crd.ConditionTypeDiscoverable).Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("ignores instances on domains that are not handled by any provider", func() {
+			r := &crd.DNSSDServiceInstance{}
+			Expect(cli.Get(ctx, req.NamespacedName, r)).To(Succeed())
+			r.Spec.Instance.Domain = "unknown.example.org"
+			Expect(cli.Update(ctx, r)).To(Succeed())
+
+			// The reconciler continues to requeue ignored instances (with
+			// back-off) in case a provider that can advertise on the domain
+			// becomes available, so it never "settles".
+			for i := 0; i < 2; i++ {
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+
+			Expect(cli.Get(ctx, req.NamespacedName, r)).To(Succeed())
+			Expect(r.Status.Provider).To(BeEmpty())
+			Expect(r.Condition(crd.ConditionTypeAdopted).Reason).To(Equal("InstanceIgnored"))
+		})
+	})
+
+	Describe("metrics", func() {
+		It("counts provider operations and record changes", func() {
+			before := map[string]float64{
+				"operations": counterValue("proclaim_provider_operations_total", "provider", "memory", "operation", "advertise", "result", "success"),
+				"ptr":        counterValue("proclaim_record_changes_total", "provider", "memory", "record_type", "PTR", "change", "created"),
+				"discovered": counterValue("proclaim_discovery_results_total", "reason", "Discovered"),
+			}
+
+			reconcileUntilSettled()
+
+			Expect(counterValue("proclaim_provider_operations_total", "provider", "memory", "operation", "advertise", "result", "success")).To(Equal(before["operations"] + 1))
+			Expect(counterValue("proclaim_record_changes_total", "provider", "memory", "record_type", "PTR", "change", "created")).To(Equal(before["ptr"] + 1))
+			Expect(counterValue("proclaim_discovery_results_total", "reason", "Discovered")).To(Equal(before["discovered"] + 1))
+		})
+
+		DescribeTable(
+			"it counts provider errors by operation and class",
+			func(err error, class string) {
+				reconciler.Providers = []provider.Provider{
+					&failingProvider{dnsp, err},
+				}
+
+				before := counterValue("proclaim_provider_errors_total", "provider", "memory", "operation", "advertise", "error_class", class)
+
+				// The first reconciliation initializes the resource's
+				// conditions, the second attempts to advertise it.
+				for i := 0; i < 2; i++ {
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).ShouldNot(HaveOccurred())
+				}
+
+				Expect(counterValue("proclaim_provider_errors_total", "provider", "memory", "operation", "advertise", "error_class", class)).To(Equal(before + 1))
+			},
+			Entry(
+				"rate limit exceeded",
+				provider.RateLimitError{Reset: time.Now().Add(1 * time.Minute), Cause: errors.New("<error>")},
+				"throttled",
+			),
+			Entry(
+				"HTTP 429",
+				provider.HTTPError{StatusCode: 429, Cause: errors.New("<error>")},
+				"throttled",
+			),
+			Entry(
+				"HTTP 401",
+				provider.HTTPError{StatusCode: 401, Cause: errors.New("<error>")},
+				"auth",
+			),
+			Entry(
+				"HTTP 403",
+				provider.HTTPError{StatusCode: 403, Cause: errors.New("<error>")},
+				"auth",
+			),
+			Entry(
+				"HTTP 404",
+				provider.HTTPError{StatusCode: 404, Cause: errors.New("<error>")},
+				"not_found",
+			),
+			Entry(
+				"HTTP 409",
+				provider.HTTPError{StatusCode: 409, Cause: errors.New("<error>")},
+				"conflict",
+			),
+			Entry(
+				"HTTP 412",
+				provider.HTTPError{StatusCode: 412, Cause: errors.New("<error>")},
+				"conflict",
+			),
+			Entry(
+				"HTTP 500",
+				provider.HTTPError{StatusCode: 500, Cause: errors.New("<error>")},
+				"other",
+			),
+			Entry(
+				"wrapped HTTP error",
+				fmt.Errorf("<context>: %w", provider.HTTPError{StatusCode: 403, Cause: errors.New("<error>")}),
+				"auth",
+			),
+			Entry(
+				"non-HTTP error",
+				errors.New("<error>"),
+				"other",
+			),
+		)
+
+		It("reports the number of instances with each condition status", func() {
+			reconcileUntilSettled()
+
+			err := testutil.CollectAndCompare(
+				&StatusCollector{Client: cli},
+				strings.NewReader(`
+# HELP proclaim_instances The number of DNS-SD service instances, by the status of each of their conditions.
+# TYPE proclaim_instances gauge
+proclaim_instances{condition="Adopted",status="False"} 0
+proclaim_instances{condition="Adopted",status="True"} 1
+proclaim_instances{condition="Adopted",status="Unknown"} 0
+proclaim_instances{condition="Advertised",status="False"} 0
+proclaim_instances{condition="Advertised",status="True"} 1
+proclaim_instances{condition="Advertised",status="Unknown"} 0
+proclaim_instances{condition="Discoverable",status="False"} 0
+proclaim_instances{condition="Discoverable",status="True"} 1
+proclaim_instances{condition="Discoverable",status="Unknown"} 0
+`),
+			)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+	})
+})
+
+// counterValue returns the value of the counter with the given name and label
+// values from the controller-runtime metrics registry, or 0 if there is no
+// such counter.
+func counterValue(name string, labels ...string) float64 {
+	families, err := metrics.Registry.Gather()
+	Expect(err).ShouldNot(HaveOccurred())
+
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+
+	next:
+		for _, m := range f.GetMetric() {
+			for i := 0; i < len(labels); i += 2 {
+				if !hasLabel(m.GetLabel(), labels[i], labels[i+1]) {
+					continue next
+				}
+			}
+
+			return m.GetCounter().GetValue()
+		}
+	}
+
+	return 0
+}
+
+// hasLabel returns true if pairs contains a label with the given name and
+// value.
+func hasLabel(pairs []*dto.LabelPair, name, value string) bool {
+	for _, p := range pairs {
+		if p.GetName() == name && p.GetValue() == value {
+			return true
+		}
+	}
+	return false
+}
+
+// managerStub is a manager.Manager that only supports recording events.
+type managerStub struct {
+	manager.Manager
+	Recorder record.EventRecorder
+}
+
+func (m *managerStub) GetEventRecorderFor(string) record.EventRecorder {
+	return m.Recorder
+}
+
+// failingProvider is a provider.Provider whose advertisers fail to advertise
+// instances with the given error.
+type failingProvider struct {
+	provider.Provider
+	Err error
+}
+
+func (p *failingProvider) AdvertiserByID(ctx context.Context, id map[string]any) (provider.Advertiser, error) {
+	a, err := p.Provider.AdvertiserByID(ctx, id)
+	return &failingAdvertiser{a, p.Err}, err
+}
+
+func (p *failingProvider) AdvertiserByDomain(ctx context.Context, domain string) (provider.Advertiser, bool, error) {
+	a, ok, err := p.Provider.AdvertiserByDomain(ctx, domain)
+	return &failingAdvertiser{a, p.Err}, ok, err
+}
+
+type failingAdvertiser struct {
+	provider.Advertiser
+	Err error
+}
+
+func (a *failingAdvertiser) Advertise(context.Context, provider.ServiceInstance) (provider.ChangeSet, error) {
+	return provider.ChangeSet{}, a.Err
+}
+
+// propagatingProvider is a provider.Provider whose advertisers report that
+// their changes are pending until Propagated is set to true.
+type propagatingProvider struct {
+	provider.Provider
+	Propagated bool
+}
+
+func (p *propagatingProvider) AdvertiserByID(ctx context.Context, id map[string]any) (provider.Advertiser, error) {
+	a, err := p.Provider.AdvertiserByID(ctx, id)
+	return &propagatingAdvertiser{a, p}, err
+}
+
+func (p *propagatingProvider) AdvertiserByDomain(ctx context.Context, domain string) (provider.Advertiser, bool, error) {
+	a, ok, err := p.Provider.AdvertiserByDomain(ctx, domain)
+	return &propagatingAdvertiser{a, p}, ok, err
+}
+
+type propagatingAdvertiser struct {
+	provider.Advertiser
+	Provider *propagatingProvider
+}
+
+func (a *propagatingAdvertiser) Advertise(ctx context.Context, inst provider.ServiceInstance) (provider.ChangeSet, error) {
+	cs, err := a.Advertiser.Advertise(ctx, inst)
+	if !cs.IsEmpty() {
+		cs.PendingChanges = []string{"change-1"}
+	}
+	return cs, err
+}
+
+func (a *propagatingAdvertiser) IsPropagated(context.Context, []string) (bool, error) {
+	return a.Provider.Propagated, nil
+}
+
+// undiscoverableProvider is a provider.Provider whose advertisers report that
+// records have been created without actually creating them, and which count
+// the number of times zone timing information is requested.
+type undiscoverableProvider struct {
+	provider.Provider
+	ZoneTimingCalls int
+}
+
+func (p *undiscoverableProvider) AdvertiserByID(ctx context.Context, id map[string]any) (provider.Advertiser, error) {
+	a, err := p.Provider.AdvertiserByID(ctx, id)
+	return &undiscoverableAdvertiser{a, p}, err
+}
+
+func (p *undiscoverableProvider) AdvertiserByDomain(ctx context.Context, domain string) (provider.Advertiser, bool, error) {
+	a, ok, err := p.Provider.AdvertiserByDomain(ctx, domain)
+	return &undiscoverableAdvertiser{a, p}, ok, err
+}
+
+type undiscoverableAdvertiser struct {
+	provider.Advertiser
+	Provider *undiscoverableProvider
+}
+
+func (a *undiscoverableAdvertiser) Advertise(context.Context, provider.ServiceInstance) (provider.ChangeSet, error) {
+	return provider.ChangeSet{PTR: provider.Created}, nil
+}
+
+func (a *undiscoverableAdvertiser) ZoneTiming(context.Context) (provider.ZoneTiming, error) {
+	a.Provider.ZoneTimingCalls++
+	return provider.ZoneTiming{NegativeTTL: 30 * time.Second}, nil
+}
@@ -0,0 +1,121 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// zoneTimingCacheTTL is the amount of time for which the timing information
+// for a zone is cached.
+//
+// The SOA record from which the timing information is obtained rarely
+// changes, so there's no need to request it each time an instance is
+// requeued.
+const zoneTimingCacheTTL = 1 * time.Hour
+
+// cachedZoneTiming is an entry in the reconciler's zone timing cache.
+type cachedZoneTiming struct {
+	Timing  provider.ZoneTiming
+	Expires time.Time
+}
+
+// zoneTiming returns timing information about the zone in which the given
+// service instance is advertised.
+//
+// It returns the zero value if the associated advertiser does not implement
+// provider.ZoneTimingAdvertiser, or the information can not be obtained.
+//
+// The information is cached per advertiser, see zoneTimingCacheTTL.
+func (r *Reconciler) zoneTiming(
+	ctx context.Context,
+	res *crd.DNSSDServiceInstance,
+) provider.ZoneTiming {
+	key, err := zoneTimingCacheKey(res)
+	if err != nil {
+		return provider.ZoneTiming{}
+	}
+
+	r.timingM.Lock()
+	c, ok := r.timings[key]
+	r.timingM.Unlock()
+
+	if ok && time.Now().Before(c.Expires) {
+		return c.Timing
+	}
+
+	a, ok, err := r.getAdvertiser(ctx, res)
+	if !ok || err != nil {
+		return provider.ZoneTiming{}
+	}
+
+	var t provider.ZoneTiming
+
+	if ta, ok := a.(provider.ZoneTimingAdvertiser); ok {
+		t, err = ta.ZoneTiming(ctx)
+		if err != nil {
+			r.providerError(
+				res,
+				res.Status.Provider,
+				res.Status.ProviderDescription,
+				operationZoneTiming,
+				err,
+			)
+			return provider.ZoneTiming{}
+		}
+	}
+
+	r.timingM.Lock()
+	defer r.timingM.Unlock()
+
+	if r.timings == nil {
+		r.timings = map[string]cachedZoneTiming{}
+	}
+
+	r.timings[key] = cachedZoneTiming{
+		Timing:  t,
+		Expires: time.Now().Add(zoneTimingCacheTTL),
+	}
+
+	return t
+}
+
+// zoneTimingCacheKey returns the key used to cache the timing information for
+// the advertiser associated with res.
+func zoneTimingCacheKey(res *crd.DNSSDServiceInstance) (string, error) {
+	id, err := json.Marshal(res.Status.Advertiser)
+	if err != nil {
+		return "", err
+	}
+
+	return res.Status.Provider + " " + string(id), nil
+}
+
+// withRateLimit returns a copy of result that delays the requeue until the
+// provider's rate limit is reset, if necessary.
+//
+// reset is the time at which the rate limit is reset, as obtained from a
+// provider.RateLimitError. It is the zero value if the provider did not
+// reject the request due to its rate limit.
+func withRateLimit(reset time.Time, result reconcile.Result) reconcile.Result {
+	if reset.IsZero() {
+		return result
+	}
+
+	d := time.Until(reset)
+	if d <= 0 {
+		return result
+	}
+
+	if result.Requeue || result.RequeueAfter < d {
+		return reconcile.Result{
+			RequeueAfter: d,
+		}
+	}
+
+	return result
+}
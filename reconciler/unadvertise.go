@@ -3,8 +3,10 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/dogmatiq/proclaim/crd"
+	"github.com/dogmatiq/proclaim/provider"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -21,20 +23,36 @@ func (r *Reconciler) unadvertise(
 ) (reconcile.Result, error) {
 	if res.Status.Provider != "" {
 		a, ok, err := r.getAdvertiser(ctx, res)
-		if !ok || err != nil {
-			// The associated provider is not known to this reconciler.
+		if err != nil {
 			return reconcile.Result{}, err
 		}
+		if !ok {
+			return r.unknownProviderResult(), nil
+		}
+
+		inst, err := res.Spec.ToServiceInstance()
+		if err != nil {
+			// The TXT record's content is not needed to remove the records,
+			// so an invalid attribute must not prevent the instance from
+			// being deleted.
+			spec := res.Spec
+			spec.Instance.Attributes = nil
+			inst, _ = spec.ToServiceInstance()
+		}
 
 		advertised := res.Condition(crd.ConditionTypeAdvertised)
 
-		cs, err := a.Unadvertise(ctx, res.Spec.ToDissolve())
+		start := time.Now()
+		cs, err := a.Unadvertise(ctx, inst)
+		observeOperation(res.Status.Provider, operationUnadvertise, start, cs, err)
+		reset, _ := provider.RateLimitReset(err)
+
 		if err != nil {
-			crd.ProviderError(
-				r.Manager,
+			r.providerError(
 				res,
 				res.Status.Provider,
 				res.Status.ProviderDescription,
+				operationUnadvertise,
 				err,
 			)
 			advertised = crd.UnadvertiseErrorCondition(err)
@@ -51,7 +69,7 @@ func (r *Reconciler) unadvertise(
 		}
 
 		if advertised.Status != metav1.ConditionFalse {
-			return reconcile.Result{Requeue: true}, nil
+			return withRateLimit(reset, reconcile.Result{Requeue: true}), nil
 		}
 	}
 
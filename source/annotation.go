@@ -0,0 +1,137 @@
+package source
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AnnotationServiceType is the annotation that specifies the DNS-SD
+	// service type, such as "_http._tcp".
+	//
+	// Services are only advertised if they have this annotation. Ingress and
+	// Gateway API resources use "_http._tcp" or "_https._tcp" by default,
+	// depending on whether TLS is enabled.
+	AnnotationServiceType = crd.GroupName + "/service-type"
+
+	// AnnotationDomain is the annotation that specifies the domain on which
+	// the service instance is advertised.
+	//
+	// A resource with this annotation, or the AnnotationServiceType
+	// annotation, is advertised.
+	AnnotationDomain = crd.GroupName + "/domain"
+
+	// AnnotationInstance is the annotation that specifies the DNS-SD service
+	// instance name. It defaults to the name of the annotated resource.
+	AnnotationInstance = crd.GroupName + "/instance"
+
+	// AnnotationSubtypes is the annotation that specifies a comma-separated
+	// list of DNS-SD service subtypes.
+	AnnotationSubtypes = crd.GroupName + "/subtypes"
+
+	// AnnotationTTL is the annotation that specifies the TTL of the DNS
+	// records, as a duration such as "5m".
+	AnnotationTTL = crd.GroupName + "/ttl"
+
+	// AnnotationPort is the annotation that specifies the name or number of
+	// the port to advertise. It is required if the resource exposes more than
+	// one port.
+	AnnotationPort = crd.GroupName + "/port"
+
+	// AnnotationHost is the annotation that specifies the target host to
+	// advertise, overriding the host that is derived from the resource.
+	AnnotationHost = crd.GroupName + "/host"
+)
+
+// annotations is the DNS-SD configuration specified by the annotations of a
+// Kubernetes resource.
+type annotations struct {
+	ServiceType string
+	Domain      string
+	Instance    string
+	Subtypes    []string
+	TTL         time.Duration
+	Port        string
+	Host        string
+}
+
+// parseAnnotations parses the DNS-SD configuration from the annotations of
+// obj.
+//
+// ok is false if obj has neither the AnnotationServiceType nor the
+// AnnotationDomain annotation.
+func parseAnnotations(obj client.Object) (_ annotations, ok bool, _ error) {
+	values := obj.GetAnnotations()
+
+	a := annotations{
+		ServiceType: values[AnnotationServiceType],
+		Domain:      values[AnnotationDomain],
+		Instance:    values[AnnotationInstance],
+		Port:        values[AnnotationPort],
+		Host:        values[AnnotationHost],
+	}
+
+	if a.ServiceType == "" && a.Domain == "" {
+		return annotations{}, false, nil
+	}
+
+	if a.Domain == "" {
+		return annotations{}, true, fmt.Errorf("the %q annotation is required", AnnotationDomain)
+	}
+
+	if a.Instance == "" {
+		a.Instance = obj.GetName()
+	}
+
+	if v := values[AnnotationSubtypes]; v != "" {
+		for _, st := range strings.Split(v, ",") {
+			if st = strings.TrimSpace(st); st != "" {
+				a.Subtypes = append(a.Subtypes, st)
+			}
+		}
+	}
+
+	if v := values[AnnotationTTL]; v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil {
+			return annotations{}, true, fmt.Errorf("the %q annotation is invalid: %w", AnnotationTTL, err)
+		}
+		a.TTL = ttl
+	}
+
+	return a, true, nil
+}
+
+// instanceSpec returns the specification of a service instance with the given
+// targets and TXT record attributes.
+//
+// It returns an error if the resulting specification is invalid.
+func (a annotations) instanceSpec(
+	targets []crd.Target,
+	attrs ...map[string]any,
+) (crd.DNSSDServiceInstanceSpec, error) {
+	res := &crd.DNSSDServiceInstance{
+		Spec: crd.DNSSDServiceInstanceSpec{
+			Instance: crd.Instance{
+				Name:        a.Instance,
+				ServiceType: a.ServiceType,
+				Domain:      a.Domain,
+				Subtypes:    a.Subtypes,
+				TTL:         metav1.Duration{Duration: a.TTL},
+				Targets:     targets,
+				Attributes:  attrs,
+			},
+		},
+	}
+
+	if errs := res.Validate(); len(errs) != 0 {
+		return crd.DNSSDServiceInstanceSpec{}, errs.ToAggregate()
+	}
+
+	return res.Spec, nil
+}
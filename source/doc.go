@@ -0,0 +1,7 @@
+// Package source contains controllers that create DNSSDServiceInstance
+// resources from other Kubernetes resources, such as Services.
+//
+// The instances created by these controllers are owned by the source
+// resource, so they are deleted (and hence unadvertised) when the source
+// resource is deleted.
+package source
@@ -0,0 +1,47 @@
+package source
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/dogmatiq/proclaim/crd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// recordError records an event indicating that service instances could not
+// be created from obj.
+func recordError(m manager.Manager, obj client.Object, err error) {
+	reason := "InvalidAnnotations"
+	if _, ok := obj.(*crd.DNSSDServiceTemplate); ok {
+		reason = "InvalidTemplate"
+	}
+	if errors.As(err, &conflictError{}) {
+		reason = "InstanceConflict"
+	}
+
+	m.
+		GetEventRecorderFor("proclaim-source").
+		Eventf(
+			obj,
+			"Warning",
+			reason,
+			"unable to create DNS-SD service instance: %s",
+			err,
+		)
+}
+
+// recordUnresolvableEndpoints records an event indicating that the endpoints
+// backed by the given pods are not advertised because they do not have a
+// hostname.
+func recordUnresolvableEndpoints(m manager.Manager, tmpl *crd.DNSSDServiceTemplate, pods []string) {
+	m.
+		GetEventRecorderFor("proclaim-source").
+		Eventf(
+			tmpl,
+			"Warning",
+			"UnresolvableEndpoints",
+			"not advertising endpoints without a hostname, such as those of pods that are not managed by a StatefulSet: %s",
+			strings.Join(pods, ", "),
+		)
+}
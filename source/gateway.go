@@ -0,0 +1,418 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// GatewayAPIGroupVersion is the group and version of the Gateway API
+// resources that are used as sources.
+//
+// The Gateway API types are not part of the core Kubernetes API, so these
+// resources are accessed as unstructured objects. Only the fields needed to
+// derive service instances are decoded.
+var GatewayAPIGroupVersion = schema.GroupVersion{
+	Group:   "gateway.networking.k8s.io",
+	Version: "v1beta1",
+}
+
+// NewGateway returns an empty Gateway API Gateway resource.
+func NewGateway() *unstructured.Unstructured {
+	return newGatewayAPIObject("Gateway")
+}
+
+// NewHTTPRoute returns an empty Gateway API HTTPRoute resource.
+func NewHTTPRoute() *unstructured.Unstructured {
+	return newGatewayAPIObject("HTTPRoute")
+}
+
+func newGatewayAPIObject(kind string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GatewayAPIGroupVersion.WithKind(kind))
+	return obj
+}
+
+// gatewaySpec is the subset of a Gateway's specification that is used to
+// derive service instances.
+type gatewaySpec struct {
+	Listeners []gatewayListener `json:"listeners"`
+}
+
+type gatewayListener struct {
+	Name     string `json:"name"`
+	Hostname string `json:"hostname,omitempty"`
+	Port     int32  `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+// httpRouteSpec is the subset of an HTTPRoute's specification that is used to
+// derive service instances.
+type httpRouteSpec struct {
+	ParentRefs []parentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []struct {
+		Matches []struct {
+			Path *struct {
+				Value string `json:"value,omitempty"`
+			} `json:"path,omitempty"`
+		} `json:"matches,omitempty"`
+	} `json:"rules,omitempty"`
+}
+
+type parentReference struct {
+	Group       *string `json:"group,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Name        string  `json:"name"`
+	SectionName *string `json:"sectionName,omitempty"`
+	Port        *int32  `json:"port,omitempty"`
+}
+
+// decodeSpec decodes the "spec" field of obj into spec.
+func decodeSpec(obj *unstructured.Unstructured, spec any) error {
+	m, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return err
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, spec); err != nil {
+		return fmt.Errorf("unable to decode %s specification: %w", obj.GetKind(), err)
+	}
+
+	return nil
+}
+
+// endpoint returns the HTTP endpoint exposed by the listener for the given
+// host.
+//
+// ok is false if the listener does not use the HTTP or HTTPS protocol.
+func (l gatewayListener) endpoint(host string) (_ httpEndpoint, ok bool) {
+	ep := httpEndpoint{
+		Host: host,
+		Port: uint16(l.Port),
+		Path: "/",
+	}
+
+	switch l.Protocol {
+	case "HTTP":
+	case "HTTPS":
+		ep.TLS = true
+	default:
+		return httpEndpoint{}, false
+	}
+
+	return ep, true
+}
+
+// matchesHostname returns true if host is accepted by the listener.
+func (l gatewayListener) matchesHostname(host string) bool {
+	if l.Hostname == "" || strings.EqualFold(l.Hostname, host) {
+		return true
+	}
+
+	if suffix, ok := strings.CutPrefix(l.Hostname, "*"); ok {
+		return strings.HasSuffix(strings.ToLower(host), strings.ToLower(suffix))
+	}
+
+	return false
+}
+
+// GatewayReconciler creates a crd.DNSSDServiceInstance for each HTTP and HTTPS
+// listener of each Gateway API Gateway that has the AnnotationDomain
+// annotation.
+//
+// The target host is taken from the listener's hostname, unless the
+// AnnotationHost annotation is present. Listeners without a hostname, or with
+// a wildcard hostname, are not advertised.
+type GatewayReconciler struct {
+	Manager manager.Manager
+	Client  client.Client
+}
+
+// Reconcile creates, updates or deletes the service instances for the Gateway
+// referred to by the Request.
+func (r *GatewayReconciler) Reconcile(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	gw := NewGateway()
+	if err := r.Client.Get(ctx, req.NamespacedName, gw); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return reconcileInstances(
+		ctx,
+		r.Manager,
+		r.Client,
+		gw,
+		func() (map[string]crd.DNSSDServiceInstanceSpec, error) {
+			return gatewayInstances(gw)
+		},
+	)
+}
+
+// gatewayInstances returns the specifications of the service instances that
+// should exist for gw, keyed by resource name.
+func gatewayInstances(gw *unstructured.Unstructured) (map[string]crd.DNSSDServiceInstanceSpec, error) {
+	a, ok, err := parseAnnotations(gw)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	var spec gatewaySpec
+	if err := decodeSpec(gw, &spec); err != nil {
+		return nil, err
+	}
+
+	var endpoints []httpEndpoint
+
+	for _, l := range spec.Listeners {
+		if l.Hostname == "" || isWildcard(l.Hostname) {
+			continue
+		}
+
+		if ep, ok := l.endpoint(l.Hostname); ok {
+			endpoints = append(endpoints, ep)
+		}
+	}
+
+	return httpInstances(gw, a, endpoints)
+}
+
+// HTTPRouteReconciler creates a crd.DNSSDServiceInstance for each hostname and
+// path of each Gateway API HTTPRoute that has the AnnotationDomain annotation.
+//
+// The ports and protocols are taken from the HTTP and HTTPS listeners of the
+// route's parent Gateways. If the route does not specify any hostnames, the
+// listeners' hostnames are used instead.
+type HTTPRouteReconciler struct {
+	Manager manager.Manager
+	Client  client.Client
+}
+
+// Reconcile creates, updates or deletes the service instances for the
+// HTTPRoute referred to by the Request.
+func (r *HTTPRouteReconciler) Reconcile(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	route := NewHTTPRoute()
+	if err := r.Client.Get(ctx, req.NamespacedName, route); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var (
+		spec     httpRouteSpec
+		gateways []gatewaySpec
+		err      error
+	)
+
+	if _, ok, _ := parseAnnotations(route); ok {
+		if err := decodeSpec(route, &spec); err != nil {
+			recordError(r.Manager, route, err)
+			return reconcile.Result{}, nil
+		}
+
+		gateways, err = r.parentGateways(ctx, route, spec)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcileInstances(
+		ctx,
+		r.Manager,
+		r.Client,
+		route,
+		func() (map[string]crd.DNSSDServiceInstanceSpec, error) {
+			return httpRouteInstances(route, spec, gateways)
+		},
+	)
+}
+
+// parentGateways returns the specifications of the Gateways that route is
+// attached to, keeping only the listeners that route's parent references
+// select.
+func (r *HTTPRouteReconciler) parentGateways(
+	ctx context.Context,
+	route *unstructured.Unstructured,
+	spec httpRouteSpec,
+) ([]gatewaySpec, error) {
+	var gateways []gatewaySpec
+
+	for _, ref := range spec.ParentRefs {
+		key, ok := gatewayKey(route, ref)
+		if !ok {
+			continue
+		}
+
+		gw := NewGateway()
+		if err := r.Client.Get(ctx, key, gw); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return nil, fmt.Errorf("unable to get parent gateway: %w", err)
+		}
+
+		var gs gatewaySpec
+		if err := decodeSpec(gw, &gs); err != nil {
+			return nil, err
+		}
+
+		var listeners []gatewayListener
+		for _, l := range gs.Listeners {
+			if ref.SectionName != nil && *ref.SectionName != l.Name {
+				continue
+			}
+			if ref.Port != nil && *ref.Port != l.Port {
+				continue
+			}
+			listeners = append(listeners, l)
+		}
+
+		gateways = append(gateways, gatewaySpec{Listeners: listeners})
+	}
+
+	return gateways, nil
+}
+
+// RequestsForGateway returns reconcile requests for each HTTPRoute that is
+// attached to the given Gateway.
+//
+// It is used to reconcile routes when their parent Gateway's listeners
+// change.
+func (r *HTTPRouteReconciler) RequestsForGateway(gw client.Object) []reconcile.Request {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	routes := &unstructured.UnstructuredList{}
+	routes.SetGroupVersionKind(GatewayAPIGroupVersion.WithKind("HTTPRouteList"))
+
+	if err := r.Client.List(ctx, routes); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for i := range routes.Items {
+		route := &routes.Items[i]
+
+		if _, ok, _ := parseAnnotations(route); !ok {
+			continue
+		}
+
+		var spec httpRouteSpec
+		if err := decodeSpec(route, &spec); err != nil {
+			continue
+		}
+
+		for _, ref := range spec.ParentRefs {
+			key, ok := gatewayKey(route, ref)
+			if ok && key.Namespace == gw.GetNamespace() && key.Name == gw.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: client.ObjectKeyFromObject(route),
+				})
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
+// gatewayKey returns the key of the Gateway referred to by a route's parent
+// reference.
+//
+// ok is false if the reference does not refer to a Gateway.
+func gatewayKey(route client.Object, ref parentReference) (_ types.NamespacedName, ok bool) {
+	if ref.Group != nil && *ref.Group != GatewayAPIGroupVersion.Group {
+		return types.NamespacedName{}, false
+	}
+
+	if ref.Kind != nil && *ref.Kind != "Gateway" {
+		return types.NamespacedName{}, false
+	}
+
+	key := types.NamespacedName{
+		Namespace: route.GetNamespace(),
+		Name:      ref.Name,
+	}
+
+	if ref.Namespace != nil {
+		key.Namespace = *ref.Namespace
+	}
+
+	return key, true
+}
+
+// httpRouteInstances returns the specifications of the service instances that
+// should exist for route, keyed by resource name.
+func httpRouteInstances(
+	route *unstructured.Unstructured,
+	spec httpRouteSpec,
+	gateways []gatewaySpec,
+) (map[string]crd.DNSSDServiceInstanceSpec, error) {
+	a, ok, err := parseAnnotations(route)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, rule := range spec.Rules {
+		for _, m := range rule.Matches {
+			if m.Path != nil {
+				paths = append(paths, normalizePath(m.Path.Value))
+			}
+		}
+	}
+
+	if len(paths) == 0 {
+		paths = []string{"/"}
+	}
+
+	var endpoints []httpEndpoint
+
+	for _, gw := range gateways {
+		for _, l := range gw.Listeners {
+			hosts := spec.Hostnames
+			if len(hosts) == 0 {
+				hosts = []string{l.Hostname}
+			}
+
+			for _, h := range hosts {
+				if h == "" || isWildcard(h) || !l.matchesHostname(h) {
+					continue
+				}
+
+				ep, ok := l.endpoint(h)
+				if !ok {
+					continue
+				}
+
+				for _, p := range paths {
+					ep.Path = p
+					endpoints = append(endpoints, ep)
+				}
+			}
+		}
+	}
+
+	return httpInstances(route, a, endpoints)
+}
@@ -0,0 +1,280 @@
+package source_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	. "github.com/dogmatiq/proclaim/source"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Gateway API sources", func() {
+	var (
+		ctx      context.Context
+		cli      client.Client
+		recorder *record.FakeRecorder
+		gw       *unstructured.Unstructured
+		route    *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		DeferCleanup(cancel)
+
+		scheme := runtime.NewScheme()
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+
+		for _, kind := range []string{"Gateway", "HTTPRoute"} {
+			scheme.AddKnownTypeWithName(GatewayAPIGroupVersion.WithKind(kind), &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(GatewayAPIGroupVersion.WithKind(kind+"List"), &unstructured.UnstructuredList{})
+		}
+
+		gw = NewGateway()
+		gw.SetNamespace("infra")
+		gw.SetName("gateway")
+		gw.SetUID("gateway-uid")
+		Expect(unstructured.SetNestedSlice(
+			gw.Object,
+			[]any{
+				map[string]any{
+					"name":     "http",
+					"hostname": "www.example.com",
+					"port":     int64(80),
+					"protocol": "HTTP",
+				},
+				map[string]any{
+					"name":     "https",
+					"hostname": "www.example.com",
+					"port":     int64(443),
+					"protocol": "HTTPS",
+				},
+				map[string]any{
+					"name":     "wildcard",
+					"hostname": "*.example.net",
+					"port":     int64(443),
+					"protocol": "HTTPS",
+				},
+				map[string]any{
+					"name":     "tcp",
+					"hostname": "tcp.example.com",
+					"port":     int64(5432),
+					"protocol": "TCP",
+				},
+			},
+			"spec", "listeners",
+		)).To(Succeed())
+
+		route = NewHTTPRoute()
+		route.SetNamespace("default")
+		route.SetName("route")
+		route.SetUID("route-uid")
+		route.SetAnnotations(map[string]string{
+			AnnotationDomain: "example.org",
+		})
+		Expect(unstructured.SetNestedField(
+			route.Object,
+			map[string]any{
+				"parentRefs": []any{
+					map[string]any{
+						"name":        "gateway",
+						"namespace":   "infra",
+						"sectionName": "wildcard",
+					},
+				},
+				"hostnames": []any{"app.example.net"},
+				"rules": []any{
+					map[string]any{
+						"matches": []any{
+							map[string]any{
+								"path": map[string]any{
+									"type":  "PathPrefix",
+									"value": "/app",
+								},
+							},
+						},
+					},
+				},
+			},
+			"spec",
+		)).To(Succeed())
+
+		cli = fake.
+			NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(gw, route).
+			Build()
+
+		recorder = record.NewFakeRecorder(100)
+	})
+
+	listInstances := func(namespace string) []crd.Instance {
+		list := &crd.DNSSDServiceInstanceList{}
+		Expect(cli.List(ctx, list, client.InNamespace(namespace))).To(Succeed())
+
+		var instances []crd.Instance
+		for _, res := range list.Items {
+			instances = append(instances, res.Spec.Instance)
+		}
+
+		return instances
+	}
+
+	Describe("type GatewayReconciler", func() {
+		var reconciler *GatewayReconciler
+
+		BeforeEach(func() {
+			reconciler = &GatewayReconciler{
+				Manager: &managerStub{Recorder: recorder},
+				Client:  cli,
+			}
+		})
+
+		reconcileGateway := func() {
+			_, err := reconciler.Reconcile(
+				ctx,
+				reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: "infra",
+						Name:      "gateway",
+					},
+				},
+			)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		It("ignores gateways without annotations", func() {
+			reconcileGateway()
+			Expect(listInstances("infra")).To(BeEmpty())
+		})
+
+		It("creates an instance for each HTTP and HTTPS listener with a hostname", func() {
+			gw.SetAnnotations(map[string]string{
+				AnnotationDomain: "example.org",
+			})
+			Expect(cli.Update(ctx, gw)).To(Succeed())
+
+			reconcileGateway()
+
+			Expect(listInstances("infra")).To(ConsistOf(
+				crd.Instance{
+					Name:        "gateway (www.example.com/)",
+					ServiceType: "_http._tcp",
+					Domain:      "example.org",
+					Targets:     []crd.Target{{Host: "www.example.com", Port: 80}},
+					Attributes:  []map[string]any{{"path": "/"}},
+				},
+				crd.Instance{
+					Name:        "gateway (www.example.com/)",
+					ServiceType: "_https._tcp",
+					Domain:      "example.org",
+					Targets:     []crd.Target{{Host: "www.example.com", Port: 443}},
+					Attributes:  []map[string]any{{"path": "/"}},
+				},
+			))
+		})
+	})
+
+	Describe("type HTTPRouteReconciler", func() {
+		var reconciler *HTTPRouteReconciler
+
+		BeforeEach(func() {
+			reconciler = &HTTPRouteReconciler{
+				Manager: &managerStub{Recorder: recorder},
+				Client:  cli,
+			}
+		})
+
+		reconcileRoute := func() {
+			_, err := reconciler.Reconcile(
+				ctx,
+				reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: "default",
+						Name:      "route",
+					},
+				},
+			)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		It("creates an instance for each hostname accepted by the parent listener", func() {
+			reconcileRoute()
+
+			res := &crd.DNSSDServiceInstance{}
+			Expect(cli.Get(
+				ctx,
+				types.NamespacedName{Namespace: "default", Name: "route"},
+				res,
+			)).To(Succeed())
+
+			Expect(res.Spec.Instance).To(Equal(crd.Instance{
+				Name:        "route",
+				ServiceType: "_https._tcp",
+				Domain:      "example.org",
+				Targets:     []crd.Target{{Host: "app.example.net", Port: 443}},
+				Attributes:  []map[string]any{{"path": "/app"}},
+			}))
+			Expect(metav1.IsControlledBy(res, route)).To(BeTrue())
+		})
+
+		It("uses the listener hostnames if the route does not specify any", func() {
+			Expect(unstructured.SetNestedStringSlice(route.Object, nil, "spec", "hostnames")).To(Succeed())
+			Expect(unstructured.SetNestedField(
+				route.Object,
+				[]any{
+					map[string]any{
+						"name":      "gateway",
+						"namespace": "infra",
+						"port":      int64(80),
+					},
+				},
+				"spec", "parentRefs",
+			)).To(Succeed())
+			Expect(cli.Update(ctx, route)).To(Succeed())
+
+			reconcileRoute()
+
+			Expect(listInstances("default")).To(ConsistOf(
+				crd.Instance{
+					Name:        "route",
+					ServiceType: "_http._tcp",
+					Domain:      "example.org",
+					Targets:     []crd.Target{{Host: "www.example.com", Port: 80}},
+					Attributes:  []map[string]any{{"path": "/app"}},
+				},
+			))
+		})
+
+		It("does not create instances for hostnames that are not accepted by the listener", func() {
+			Expect(unstructured.SetNestedStringSlice(route.Object, []string{"app.example.com"}, "spec", "hostnames")).To(Succeed())
+			Expect(cli.Update(ctx, route)).To(Succeed())
+
+			reconcileRoute()
+			Expect(listInstances("default")).To(BeEmpty())
+		})
+
+		Describe("func RequestsForGateway()", func() {
+			It("returns requests for the routes attached to the gateway", func() {
+				Expect(reconciler.RequestsForGateway(gw)).To(ConsistOf(
+					reconcile.Request{
+						NamespacedName: types.NamespacedName{
+							Namespace: "default",
+							Name:      "route",
+						},
+					},
+				))
+			})
+		})
+	})
+})
@@ -0,0 +1,113 @@
+package source
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/dogmatiq/proclaim/crd"
+	"golang.org/x/exp/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// httpEndpoint is an HTTP endpoint exposed by an Ingress or Gateway API
+// resource.
+type httpEndpoint struct {
+	Host string
+	Port uint16
+	TLS  bool
+	Path string
+}
+
+// serviceType returns the default DNS-SD service type for the endpoint.
+func (ep httpEndpoint) serviceType() string {
+	if ep.TLS {
+		return "_https._tcp"
+	}
+	return "_http._tcp"
+}
+
+// less returns true if ep should be sorted before x.
+func (ep httpEndpoint) less(x httpEndpoint) bool {
+	if ep.Host != x.Host {
+		return ep.Host < x.Host
+	}
+	if ep.Port != x.Port {
+		return ep.Port < x.Port
+	}
+	if ep.Path != x.Path {
+		return ep.Path < x.Path
+	}
+	return !ep.TLS && x.TLS
+}
+
+// normalizePath returns the path to use in a "path" TXT record attribute.
+func normalizePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	return p
+}
+
+// isWildcard returns true if host is a wildcard hostname, such as
+// "*.example.org". Wildcard hostnames can not be used as SRV targets.
+func isWildcard(host string) bool {
+	return strings.HasPrefix(host, "*")
+}
+
+// httpInstances returns the specifications of the service instances that
+// advertise the given endpoints, keyed by resource name.
+//
+// Each endpoint is advertised as a separate instance, with a "path" TXT record
+// attribute as per https://www.rfc-editor.org/rfc/rfc6763#section-6.2 and the
+// conventions described at http://www.dns-sd.org/txtrecords.html.
+func httpInstances(
+	obj client.Object,
+	a annotations,
+	endpoints []httpEndpoint,
+) (map[string]crd.DNSSDServiceInstanceSpec, error) {
+	slices.SortFunc(endpoints, httpEndpoint.less)
+	endpoints = slices.Compact(endpoints)
+
+	instances := map[string]crd.DNSSDServiceInstanceSpec{}
+
+	for _, ep := range endpoints {
+		x := a
+		name := obj.GetName()
+
+		if x.ServiceType == "" {
+			x.ServiceType = ep.serviceType()
+		}
+
+		// Only use the resource's own name if there is a single endpoint,
+		// otherwise derive a unique but stable name for each endpoint.
+		if len(endpoints) > 1 {
+			x.Instance = fmt.Sprintf("%s (%s%s)", a.Instance, ep.Host, ep.Path)
+
+			h := fnv.New32a()
+			fmt.Fprintf(h, "%s:%d:%t:%s", ep.Host, ep.Port, ep.TLS, ep.Path)
+			name = fmt.Sprintf("%s-%08x", name, h.Sum32())
+		}
+
+		host := ep.Host
+		if a.Host != "" {
+			host = a.Host
+		}
+
+		spec, err := x.instanceSpec(
+			[]crd.Target{
+				{Host: host, Port: ep.Port},
+			},
+			map[string]any{
+				"path": ep.Path,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		instances[name] = spec
+	}
+
+	return instances, nil
+}
@@ -0,0 +1,96 @@
+package source
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// IngressReconciler creates a crd.DNSSDServiceInstance for each host and path
+// of each Kubernetes Ingress that has the AnnotationDomain annotation.
+//
+// The target host is taken from the host of each ingress rule, unless the
+// AnnotationHost annotation is present. Hosts that are listed in the ingress's
+// TLS configuration are advertised as "_https._tcp" services on port 443,
+// others are advertised as "_http._tcp" services on port 80.
+type IngressReconciler struct {
+	Manager manager.Manager
+	Client  client.Client
+}
+
+// Reconcile creates, updates or deletes the service instances for the Ingress
+// referred to by the Request.
+func (r *IngressReconciler) Reconcile(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	ing := &networkingv1.Ingress{}
+	if err := r.Client.Get(ctx, req.NamespacedName, ing); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return reconcileInstances(
+		ctx,
+		r.Manager,
+		r.Client,
+		ing,
+		func() (map[string]crd.DNSSDServiceInstanceSpec, error) {
+			return ingressInstances(ing)
+		},
+	)
+}
+
+// ingressInstances returns the specifications of the service instances that
+// should exist for ing, keyed by resource name.
+func ingressInstances(ing *networkingv1.Ingress) (map[string]crd.DNSSDServiceInstanceSpec, error) {
+	a, ok, err := parseAnnotations(ing)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	tls := map[string]bool{}
+	for _, t := range ing.Spec.TLS {
+		for _, h := range t.Hosts {
+			tls[h] = true
+		}
+	}
+
+	var endpoints []httpEndpoint
+
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host == "" || isWildcard(rule.Host) {
+			continue
+		}
+
+		ep := httpEndpoint{
+			Host: rule.Host,
+			Port: 80,
+			TLS:  tls[rule.Host],
+			Path: "/",
+		}
+
+		if ep.TLS {
+			ep.Port = 443
+		}
+
+		if rule.HTTP == nil || len(rule.HTTP.Paths) == 0 {
+			endpoints = append(endpoints, ep)
+			continue
+		}
+
+		for _, p := range rule.HTTP.Paths {
+			ep.Path = normalizePath(p.Path)
+			endpoints = append(endpoints, ep)
+		}
+	}
+
+	return httpInstances(ing, a, endpoints)
+}
@@ -0,0 +1,230 @@
+package source_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	. "github.com/dogmatiq/proclaim/source"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("type IngressReconciler", func() {
+	var (
+		ctx        context.Context
+		cli        client.Client
+		recorder   *record.FakeRecorder
+		reconciler *IngressReconciler
+		ing        *networkingv1.Ingress
+		req        reconcile.Request
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		DeferCleanup(cancel)
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+
+		ing = &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "ingress",
+				UID:       "ingress-uid",
+				Annotations: map[string]string{
+					AnnotationDomain: "example.org",
+				},
+			},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					{
+						Host: "www.example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{Path: "/app"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		req = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: ing.Namespace,
+				Name:      ing.Name,
+			},
+		}
+
+		cli = fake.
+			NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(ing).
+			Build()
+
+		recorder = record.NewFakeRecorder(100)
+
+		reconciler = &IngressReconciler{
+			Manager: &managerStub{Recorder: recorder},
+			Client:  cli,
+		}
+	})
+
+	reconcileIngress := func() {
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	updateIngress := func(fn func(*networkingv1.Ingress)) {
+		i := &networkingv1.Ingress{}
+		Expect(cli.Get(ctx, req.NamespacedName, i)).To(Succeed())
+		fn(i)
+		Expect(cli.Update(ctx, i)).To(Succeed())
+	}
+
+	listInstances := func() []crd.Instance {
+		list := &crd.DNSSDServiceInstanceList{}
+		Expect(cli.List(ctx, list)).To(Succeed())
+
+		var instances []crd.Instance
+		for _, res := range list.Items {
+			Expect(metav1.IsControlledBy(&res, ing)).To(BeTrue())
+			instances = append(instances, res.Spec.Instance)
+		}
+
+		return instances
+	}
+
+	Describe("func Reconcile()", func() {
+		It("creates an _http._tcp instance with a path attribute", func() {
+			reconcileIngress()
+
+			res := &crd.DNSSDServiceInstance{}
+			Expect(cli.Get(ctx, req.NamespacedName, res)).To(Succeed())
+			Expect(res.Spec.Instance).To(Equal(crd.Instance{
+				Name:        "ingress",
+				ServiceType: "_http._tcp",
+				Domain:      "example.org",
+				Targets: []crd.Target{
+					{Host: "www.example.com", Port: 80},
+				},
+				Attributes: []map[string]any{
+					{"path": "/app"},
+				},
+			}))
+		})
+
+		It("creates an _https._tcp instance for hosts with TLS", func() {
+			updateIngress(func(i *networkingv1.Ingress) {
+				i.Spec.TLS = []networkingv1.IngressTLS{
+					{Hosts: []string{"www.example.com"}},
+				}
+			})
+
+			reconcileIngress()
+
+			Expect(listInstances()).To(ConsistOf(
+				crd.Instance{
+					Name:        "ingress",
+					ServiceType: "_https._tcp",
+					Domain:      "example.org",
+					Targets: []crd.Target{
+						{Host: "www.example.com", Port: 443},
+					},
+					Attributes: []map[string]any{
+						{"path": "/app"},
+					},
+				},
+			))
+		})
+
+		It("creates an instance for each host and path", func() {
+			updateIngress(func(i *networkingv1.Ingress) {
+				i.Spec.Rules[0].HTTP.Paths = append(
+					i.Spec.Rules[0].HTTP.Paths,
+					networkingv1.HTTPIngressPath{Path: "/api"},
+				)
+				i.Spec.Rules = append(
+					i.Spec.Rules,
+					networkingv1.IngressRule{Host: "other.example.com"},
+					networkingv1.IngressRule{Host: "*.example.com"},
+				)
+			})
+
+			reconcileIngress()
+
+			Expect(listInstances()).To(ConsistOf(
+				crd.Instance{
+					Name:        "ingress (www.example.com/app)",
+					ServiceType: "_http._tcp",
+					Domain:      "example.org",
+					Targets:     []crd.Target{{Host: "www.example.com", Port: 80}},
+					Attributes:  []map[string]any{{"path": "/app"}},
+				},
+				crd.Instance{
+					Name:        "ingress (www.example.com/api)",
+					ServiceType: "_http._tcp",
+					Domain:      "example.org",
+					Targets:     []crd.Target{{Host: "www.example.com", Port: 80}},
+					Attributes:  []map[string]any{{"path": "/api"}},
+				},
+				crd.Instance{
+					Name:        "ingress (other.example.com/)",
+					ServiceType: "_http._tcp",
+					Domain:      "example.org",
+					Targets:     []crd.Target{{Host: "other.example.com", Port: 80}},
+					Attributes:  []map[string]any{{"path": "/"}},
+				},
+			))
+		})
+
+		It("deletes instances that are no longer needed", func() {
+			updateIngress(func(i *networkingv1.Ingress) {
+				i.Spec.Rules = append(i.Spec.Rules, networkingv1.IngressRule{Host: "other.example.com"})
+			})
+
+			reconcileIngress()
+			Expect(listInstances()).To(HaveLen(2))
+
+			updateIngress(func(i *networkingv1.Ingress) {
+				i.Spec.Rules = i.Spec.Rules[:1]
+			})
+
+			reconcileIngress()
+			Expect(listInstances()).To(HaveLen(1))
+
+			updateIngress(func(i *networkingv1.Ingress) {
+				i.Annotations = nil
+			})
+
+			reconcileIngress()
+			Expect(listInstances()).To(BeEmpty())
+		})
+
+		It("uses the service type annotation, if present", func() {
+			updateIngress(func(i *networkingv1.Ingress) {
+				i.Annotations[AnnotationServiceType] = "_app._tcp"
+			})
+
+			reconcileIngress()
+
+			instances := listInstances()
+			Expect(instances).To(HaveLen(1))
+			Expect(instances[0].ServiceType).To(Equal("_app._tcp"))
+		})
+	})
+})
@@ -0,0 +1,119 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ServiceReconciler creates a crd.DNSSDServiceInstance for each Kubernetes
+// Service that has the AnnotationServiceType annotation.
+//
+// The target host is taken from the hostnames of the service's load balancer
+// ingress points, unless the AnnotationHost annotation is present. The target
+// port is taken from the service's ports.
+type ServiceReconciler struct {
+	Manager manager.Manager
+	Client  client.Client
+}
+
+// Reconcile creates, updates or deletes the service instances for the Service
+// referred to by the Request.
+func (r *ServiceReconciler) Reconcile(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	svc := &corev1.Service{}
+	if err := r.Client.Get(ctx, req.NamespacedName, svc); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return reconcileInstances(
+		ctx,
+		r.Manager,
+		r.Client,
+		svc,
+		func() (map[string]crd.DNSSDServiceInstanceSpec, error) {
+			return serviceInstances(svc)
+		},
+	)
+}
+
+// serviceInstances returns the specifications of the service instances that
+// should exist for svc, keyed by resource name.
+func serviceInstances(svc *corev1.Service) (map[string]crd.DNSSDServiceInstanceSpec, error) {
+	a, ok, err := parseAnnotations(svc)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	// Unlike HTTP resources, there is no default service type for services,
+	// so those without a service type are not advertised.
+	if a.ServiceType == "" {
+		return nil, nil
+	}
+
+	port, err := servicePort(svc, a.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []crd.Target
+
+	if a.Host != "" {
+		targets = append(targets, crd.Target{Host: a.Host, Port: port})
+	} else {
+		for _, ing := range svc.Status.LoadBalancer.Ingress {
+			// SRV records must refer to a hostname, so ingress points that only
+			// have an IP address can not be advertised without the "host"
+			// annotation.
+			if ing.Hostname != "" {
+				targets = append(targets, crd.Target{Host: ing.Hostname, Port: port})
+			}
+		}
+	}
+
+	// The load balancer has not been provisioned yet, the service will be
+	// reconciled again when its status is updated.
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	spec, err := a.instanceSpec(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]crd.DNSSDServiceInstanceSpec{
+		svc.Name: spec,
+	}, nil
+}
+
+// servicePort returns the port number of the service port with the given
+// name or number. If it is empty the service must have exactly one port.
+func servicePort(svc *corev1.Service, nameOrNumber string) (uint16, error) {
+	if nameOrNumber == "" {
+		if len(svc.Spec.Ports) != 1 {
+			return 0, fmt.Errorf("the %q annotation is required when the service does not have exactly one port", AnnotationPort)
+		}
+		return uint16(svc.Spec.Ports[0].Port), nil
+	}
+
+	for _, p := range svc.Spec.Ports {
+		if p.Name == nameOrNumber || strconv.Itoa(int(p.Port)) == nameOrNumber {
+			return uint16(p.Port), nil
+		}
+	}
+
+	return 0, fmt.Errorf("the %q annotation refers to an unknown port (%s)", AnnotationPort, nameOrNumber)
+}
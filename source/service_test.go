@@ -0,0 +1,273 @@
+package source_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	. "github.com/dogmatiq/proclaim/source"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("type ServiceReconciler", func() {
+	var (
+		ctx        context.Context
+		cli        client.Client
+		recorder   *record.FakeRecorder
+		reconciler *ServiceReconciler
+		svc        *corev1.Service
+		req        reconcile.Request
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		DeferCleanup(cancel)
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+
+		svc = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "service",
+				UID:       "service-uid",
+				Annotations: map[string]string{
+					AnnotationServiceType: "_http._tcp",
+					AnnotationDomain:      "example.org",
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Type: corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{
+					{Name: "http", Port: 8080},
+				},
+			},
+			Status: corev1.ServiceStatus{
+				LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{
+						{Hostname: "lb.example.com"},
+					},
+				},
+			},
+		}
+
+		req = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: svc.Namespace,
+				Name:      svc.Name,
+			},
+		}
+
+		cli = fake.
+			NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(svc).
+			Build()
+
+		recorder = record.NewFakeRecorder(100)
+
+		reconciler = &ServiceReconciler{
+			Manager: &managerStub{Recorder: recorder},
+			Client:  cli,
+		}
+	})
+
+	reconcileService := func() {
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	updateService := func(fn func(*corev1.Service)) {
+		s := &corev1.Service{}
+		Expect(cli.Get(ctx, req.NamespacedName, s)).To(Succeed())
+		fn(s)
+		Expect(cli.Update(ctx, s)).To(Succeed())
+	}
+
+	getInstance := func() *crd.DNSSDServiceInstance {
+		res := &crd.DNSSDServiceInstance{}
+		Expect(cli.Get(ctx, req.NamespacedName, res)).To(Succeed())
+		return res
+	}
+
+	expectNoInstances := func() {
+		list := &crd.DNSSDServiceInstanceList{}
+		Expect(cli.List(ctx, list)).To(Succeed())
+		Expect(list.Items).To(BeEmpty())
+	}
+
+	Describe("func Reconcile()", func() {
+		It("creates a service instance owned by the service", func() {
+			reconcileService()
+
+			res := getInstance()
+			Expect(res.Spec.Instance).To(Equal(crd.Instance{
+				Name:        "service",
+				ServiceType: "_http._tcp",
+				Domain:      "example.org",
+				Targets: []crd.Target{
+					{Host: "lb.example.com", Port: 8080},
+				},
+			}))
+			Expect(metav1.IsControlledBy(res, svc)).To(BeTrue())
+		})
+
+		It("uses the optional annotations", func() {
+			updateService(func(s *corev1.Service) {
+				s.Annotations[AnnotationInstance] = "My Service"
+				s.Annotations[AnnotationSubtypes] = "_printer, _scanner"
+				s.Annotations[AnnotationTTL] = "5m"
+				s.Annotations[AnnotationHost] = "service.example.com"
+				s.Annotations[AnnotationPort] = "https"
+				s.Spec.Ports = append(s.Spec.Ports, corev1.ServicePort{Name: "https", Port: 8443})
+			})
+
+			reconcileService()
+
+			res := getInstance()
+			Expect(res.Spec.Instance).To(Equal(crd.Instance{
+				Name:        "My Service",
+				ServiceType: "_http._tcp",
+				Domain:      "example.org",
+				Subtypes:    []string{"_printer", "_scanner"},
+				TTL:         metav1.Duration{Duration: 5 * time.Minute},
+				Targets: []crd.Target{
+					{Host: "service.example.com", Port: 8443},
+				},
+			}))
+		})
+
+		It("updates the service instance when the service changes", func() {
+			reconcileService()
+
+			updateService(func(s *corev1.Service) {
+				s.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{
+					{Hostname: "lb-1.example.com"},
+					{Hostname: "lb-2.example.com"},
+				}
+			})
+
+			reconcileService()
+
+			Expect(getInstance().Spec.Instance.Targets).To(Equal([]crd.Target{
+				{Host: "lb-1.example.com", Port: 8080},
+				{Host: "lb-2.example.com", Port: 8080},
+			}))
+		})
+
+		It("recreates the service instance when an immutable field changes", func() {
+			reconcileService()
+
+			res := getInstance()
+			res.Finalizers = []string{crd.FinalizerName}
+			Expect(cli.Update(ctx, res)).To(Succeed())
+
+			updateService(func(s *corev1.Service) {
+				s.Annotations[AnnotationDomain] = "example.com"
+			})
+
+			// The existing instance is deleted, but it can not be recreated
+			// until its finalizer has been removed.
+			reconcileService()
+
+			res = getInstance()
+			Expect(res.DeletionTimestamp.IsZero()).To(BeFalse())
+			Expect(res.Spec.Instance.Domain).To(Equal("example.org"))
+
+			res.Finalizers = nil
+			Expect(cli.Update(ctx, res)).To(Succeed())
+
+			reconcileService()
+			Expect(getInstance().Spec.Instance.Domain).To(Equal("example.com"))
+		})
+
+		It("deletes the service instance when the annotation is removed", func() {
+			reconcileService()
+
+			updateService(func(s *corev1.Service) {
+				delete(s.Annotations, AnnotationServiceType)
+			})
+
+			reconcileService()
+			expectNoInstances()
+		})
+
+		It("does not create a service instance until the load balancer has a hostname", func() {
+			updateService(func(s *corev1.Service) {
+				s.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{
+					{IP: "192.0.2.1"},
+				}
+			})
+
+			reconcileService()
+			expectNoInstances()
+		})
+
+		It("ignores services without the service type annotation", func() {
+			updateService(func(s *corev1.Service) {
+				s.Annotations = nil
+			})
+
+			reconcileService()
+			expectNoInstances()
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("records an event if the annotations are invalid", func() {
+			updateService(func(s *corev1.Service) {
+				s.Spec.Ports = append(s.Spec.Ports, corev1.ServicePort{Name: "https", Port: 8443})
+			})
+
+			reconcileService()
+			expectNoInstances()
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidAnnotations")))
+		})
+
+		It("does not modify a service instance that it does not control", func() {
+			existing := &crd.DNSSDServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: svc.Namespace,
+					Name:      svc.Name,
+				},
+				Spec: crd.DNSSDServiceInstanceSpec{
+					Instance: crd.Instance{
+						Name:        "manual",
+						ServiceType: "_other._tcp",
+						Domain:      "example.org",
+						Targets: []crd.Target{
+							{Host: "manual.example.com", Port: 443},
+						},
+					},
+				},
+			}
+			Expect(cli.Create(ctx, existing)).To(Succeed())
+
+			reconcileService()
+
+			Expect(getInstance().Spec).To(Equal(existing.Spec))
+			Expect(recorder.Events).To(Receive(ContainSubstring("InstanceConflict")))
+		})
+	})
+})
+
+type managerStub struct {
+	manager.Manager
+	Recorder record.EventRecorder
+}
+
+func (m *managerStub) GetEventRecorderFor(string) record.EventRecorder {
+	return m.Recorder
+}
@@ -0,0 +1,184 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/proclaim/crd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// conflictError is returned by syncInstances when a service instance with
+// the desired name already exists but is not controlled by the source
+// resource.
+type conflictError struct {
+	Name string
+}
+
+func (e conflictError) Error() string {
+	return fmt.Sprintf("a DNSSDServiceInstance named %q already exists and is not managed by this resource", e.Name)
+}
+
+// reconcileInstances creates, updates or deletes the service instances
+// controlled by owner so that they match the specifications returned by
+// desired.
+func reconcileInstances(
+	ctx context.Context,
+	m manager.Manager,
+	cli client.Client,
+	owner client.Object,
+	desired func() (map[string]crd.DNSSDServiceInstanceSpec, error),
+) (reconcile.Result, error) {
+	// Instances are deleted by the garbage collector via their owner
+	// references once the owner itself is deleted.
+	if !owner.GetDeletionTimestamp().IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	specs, err := desired()
+	if err != nil {
+		// There's no point retrying until the owner is modified.
+		recordError(m, owner, err)
+		return reconcile.Result{}, nil
+	}
+
+	if err := syncInstances(ctx, cli, owner, specs); err != nil {
+		if errors.As(err, &conflictError{}) {
+			recordError(m, owner, err)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// syncInstances creates or updates a service instance for each entry in
+// desired, keyed by resource name, such that each is controlled by owner. Any
+// other service instances controlled by owner are deleted.
+func syncInstances(
+	ctx context.Context,
+	cli client.Client,
+	owner client.Object,
+	desired map[string]crd.DNSSDServiceInstanceSpec,
+) error {
+	for name, spec := range desired {
+		// If the existing instance is still being deleted it can not be
+		// recreated yet. The owner is reconciled again once the deletion is
+		// complete.
+		if deleting, err := deleteIfImmutableChanged(ctx, cli, owner, name, spec); deleting || err != nil {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		res := &crd.DNSSDServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: owner.GetNamespace(),
+				Name:      name,
+			},
+		}
+
+		if _, err := controllerutil.CreateOrUpdate(
+			ctx,
+			cli,
+			res,
+			func() error {
+				// Never take control of an instance that was created by some
+				// other means, such as one that is managed by hand.
+				if res.ResourceVersion != "" && !metav1.IsControlledBy(res, owner) {
+					return conflictError{name}
+				}
+
+				res.Spec = spec
+				return controllerutil.SetControllerReference(owner, res, cli.Scheme())
+			},
+		); err != nil {
+			return err
+		}
+	}
+
+	existing := &crd.DNSSDServiceInstanceList{}
+	if err := cli.List(
+		ctx,
+		existing,
+		client.InNamespace(owner.GetNamespace()),
+	); err != nil {
+		return fmt.Errorf("unable to list service instances: %w", err)
+	}
+
+	for i := range existing.Items {
+		res := &existing.Items[i]
+
+		if _, ok := desired[res.Name]; ok {
+			continue
+		}
+
+		if !metav1.IsControlledBy(res, owner) {
+			continue
+		}
+
+		if err := cli.Delete(ctx, res); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("unable to delete service instance: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteIfImmutableChanged deletes the service instance with the given name if
+// it is controlled by owner and any of its immutable fields differ from spec.
+//
+// The instance name, service type and domain can not be changed once an
+// instance is created, so the instance must be deleted (and therefore
+// unadvertised) before it can be recreated with the new values.
+//
+// It returns true if the instance is being deleted.
+func deleteIfImmutableChanged(
+	ctx context.Context,
+	cli client.Client,
+	owner client.Object,
+	name string,
+	spec crd.DNSSDServiceInstanceSpec,
+) (bool, error) {
+	key := client.ObjectKey{
+		Namespace: owner.GetNamespace(),
+		Name:      name,
+	}
+
+	res := &crd.DNSSDServiceInstance{}
+	if err := cli.Get(ctx, key, res); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	if !metav1.IsControlledBy(res, owner) {
+		return false, nil
+	}
+
+	if !res.DeletionTimestamp.IsZero() {
+		return true, nil
+	}
+
+	x, y := res.Spec.Instance, spec.Instance
+	if x.Name == y.Name && x.ServiceType == y.ServiceType && x.Domain == y.Domain {
+		return false, nil
+	}
+
+	if err := cli.Delete(ctx, res); client.IgnoreNotFound(err) != nil {
+		return false, fmt.Errorf("unable to delete service instance: %w", err)
+	}
+
+	// The instance is deleted immediately if it has not yet been given a
+	// finalizer by the reconciler.
+	if err := cli.Get(ctx, key, res); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	return true, nil
+}
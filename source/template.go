@@ -0,0 +1,260 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// TemplateReconciler creates a crd.DNSSDServiceInstance for each ready
+// endpoint of the headless Service selected by each crd.DNSSDServiceTemplate.
+//
+// Each instance is named after the endpoint's pod, and targets the endpoint's
+// hostname within the template's target domain. Instances are deleted, and
+// hence unadvertised, as soon as their endpoint is no longer ready.
+//
+// Endpoints without a hostname, such as those of pods that are not managed by
+// a StatefulSet, do not have their own DNS records and are not advertised.
+type TemplateReconciler struct {
+	Manager manager.Manager
+	Client  client.Client
+}
+
+// Reconcile creates, updates or deletes the service instances for the
+// DNSSDServiceTemplate referred to by the Request.
+func (r *TemplateReconciler) Reconcile(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	tmpl := &crd.DNSSDServiceTemplate{}
+	if err := r.Client.Get(ctx, req.NamespacedName, tmpl); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	svc := &corev1.Service{}
+	if err := r.Client.Get(
+		ctx,
+		client.ObjectKey{
+			Namespace: tmpl.Namespace,
+			Name:      tmpl.Spec.ServiceName,
+		},
+		svc,
+	); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, fmt.Errorf("unable to get service: %w", err)
+		}
+
+		// The service does not exist (yet), so there are no endpoints to
+		// advertise.
+		svc = nil
+	}
+
+	slices := &discoveryv1.EndpointSliceList{}
+	if err := r.Client.List(
+		ctx,
+		slices,
+		client.InNamespace(tmpl.Namespace),
+		client.MatchingLabels{
+			discoveryv1.LabelServiceName: tmpl.Spec.ServiceName,
+		},
+	); err != nil {
+		return reconcile.Result{}, fmt.Errorf("unable to list endpoint slices: %w", err)
+	}
+
+	var unresolvable []string
+
+	result, err := reconcileInstances(
+		ctx,
+		r.Manager,
+		r.Client,
+		tmpl,
+		func() (map[string]crd.DNSSDServiceInstanceSpec, error) {
+			specs, pods, err := templateInstances(tmpl, svc, slices.Items)
+			unresolvable = pods
+			return specs, err
+		},
+	)
+
+	if len(unresolvable) != 0 {
+		recordUnresolvableEndpoints(r.Manager, tmpl, unresolvable)
+	}
+
+	return result, err
+}
+
+// RequestsForService returns reconcile requests for each DNSSDServiceTemplate
+// that selects the given Service, or the Service that owns the given
+// EndpointSlice.
+func (r *TemplateReconciler) RequestsForService(obj client.Object) []reconcile.Request {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	name := obj.GetName()
+	if _, ok := obj.(*discoveryv1.EndpointSlice); ok {
+		name = obj.GetLabels()[discoveryv1.LabelServiceName]
+	}
+
+	templates := &crd.DNSSDServiceTemplateList{}
+	if err := r.Client.List(
+		ctx,
+		templates,
+		client.InNamespace(obj.GetNamespace()),
+	); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for _, tmpl := range templates.Items {
+		if tmpl.Spec.ServiceName == name {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&tmpl),
+			})
+		}
+	}
+
+	return requests
+}
+
+// templateInstances returns the specifications of the service instances that
+// should exist for tmpl, keyed by resource name.
+//
+// unresolvable contains the names of the pods backing ready endpoints that
+// can not be advertised because they do not have a hostname.
+func templateInstances(
+	tmpl *crd.DNSSDServiceTemplate,
+	svc *corev1.Service,
+	slices []discoveryv1.EndpointSlice,
+) (_ map[string]crd.DNSSDServiceInstanceSpec, unresolvable []string, _ error) {
+	if svc == nil {
+		return nil, nil, nil
+	}
+
+	// Only the endpoints of headless services have their own DNS records, so
+	// endpoint hostnames are not resolvable for other service types.
+	if svc.Spec.ClusterIP != corev1.ClusterIPNone {
+		return nil, nil, fmt.Errorf("the %q service is not a headless service", svc.Name)
+	}
+
+	targetDomain := tmpl.Spec.TargetDomain
+	if targetDomain == "" {
+		targetDomain = fmt.Sprintf("%s.%s.svc.cluster.local", svc.Name, svc.Namespace)
+	}
+
+	a := annotations{
+		ServiceType: tmpl.Spec.Instance.ServiceType,
+		Domain:      tmpl.Spec.Instance.Domain,
+		Subtypes:    tmpl.Spec.Instance.Subtypes,
+		TTL:         tmpl.Spec.Instance.TTL.Duration,
+	}
+
+	instances := map[string]crd.DNSSDServiceInstanceSpec{}
+
+	for _, slice := range slices {
+		// Slices without any endpoints may not have any ports either.
+		if len(slice.Endpoints) == 0 {
+			continue
+		}
+
+		port, err := endpointSlicePort(slice, tmpl.Spec.Port)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, ep := range slice.Endpoints {
+			// A nil ready condition indicates an unknown state, which
+			// consumers are expected to interpret as ready.
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+
+			podName, hostname := endpointNames(ep)
+			if hostname == "" {
+				// Kubernetes only creates a DNS record for an endpoint of a
+				// headless service if its pod has a hostname and subdomain,
+				// as is the case for pods managed by a StatefulSet.
+				if podName != "" {
+					unresolvable = append(unresolvable, podName)
+				}
+				continue
+			}
+
+			x := a
+			x.Instance = podName
+
+			spec, err := x.instanceSpec(
+				[]crd.Target{
+					{
+						Host: hostname + "." + targetDomain,
+						Port: port,
+					},
+				},
+				tmpl.Spec.Instance.Attributes...,
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			instances[tmpl.Name+"-"+podName] = spec
+		}
+	}
+
+	return instances, unresolvable, nil
+}
+
+// endpointNames returns the name of the pod that backs ep, and the hostname
+// under which it is resolvable within the service's domain.
+//
+// Pods managed by a StatefulSet have a hostname that matches the pod name, and
+// hence includes the pod's ordinal. hostname is empty if the endpoint is not
+// resolvable by name.
+func endpointNames(ep discoveryv1.Endpoint) (podName, hostname string) {
+	if ep.TargetRef != nil && ep.TargetRef.Kind == "Pod" {
+		podName = ep.TargetRef.Name
+	}
+
+	if ep.Hostname != nil {
+		hostname = *ep.Hostname
+	}
+
+	if podName == "" {
+		podName = hostname
+	}
+
+	return podName, hostname
+}
+
+// endpointSlicePort returns the port number of the endpoint slice port with
+// the given name or number. If it is empty the slice must have exactly one
+// port.
+func endpointSlicePort(slice discoveryv1.EndpointSlice, nameOrNumber string) (uint16, error) {
+	if nameOrNumber == "" {
+		if len(slice.Ports) != 1 || slice.Ports[0].Port == nil {
+			return 0, fmt.Errorf("a port must be specified when the endpoints do not have exactly one port")
+		}
+		return uint16(*slice.Ports[0].Port), nil
+	}
+
+	for _, p := range slice.Ports {
+		if p.Port == nil {
+			continue
+		}
+
+		if (p.Name != nil && *p.Name == nameOrNumber) || strconv.Itoa(int(*p.Port)) == nameOrNumber {
+			return uint16(*p.Port), nil
+		}
+	}
+
+	return 0, fmt.Errorf("the endpoints do not have a port named %q", nameOrNumber)
+}
@@ -0,0 +1,289 @@
+package source_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/proclaim/crd"
+	. "github.com/dogmatiq/proclaim/source"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("type TemplateReconciler", func() {
+	var (
+		ctx        context.Context
+		cli        client.Client
+		recorder   *record.FakeRecorder
+		reconciler *TemplateReconciler
+		tmpl       *crd.DNSSDServiceTemplate
+		svc        *corev1.Service
+		slice      *discoveryv1.EndpointSlice
+		req        reconcile.Request
+	)
+
+	endpoint := func(pod string, ready bool) discoveryv1.Endpoint {
+		return discoveryv1.Endpoint{
+			Addresses: []string{"10.0.0.1"},
+			Hostname:  &pod,
+			Conditions: discoveryv1.EndpointConditions{
+				Ready: &ready,
+			},
+			TargetRef: &corev1.ObjectReference{
+				Kind: "Pod",
+				Name: pod,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		DeferCleanup(cancel)
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+
+		tmpl = &crd.DNSSDServiceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "peers",
+				UID:       "template-uid",
+			},
+			Spec: crd.DNSSDServiceTemplateSpec{
+				ServiceName: "db",
+				Instance: crd.InstanceTemplate{
+					ServiceType: "_db._tcp",
+					Domain:      "example.org",
+					Attributes: []map[string]any{
+						{"role": "peer"},
+					},
+				},
+			},
+		}
+
+		svc = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "db",
+			},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: corev1.ClusterIPNone,
+			},
+		}
+
+		portName := "peer"
+		portNumber := int32(7000)
+
+		slice = &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "db-abcde",
+				Labels: map[string]string{
+					discoveryv1.LabelServiceName: "db",
+				},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports: []discoveryv1.EndpointPort{
+				{Name: &portName, Port: &portNumber},
+			},
+			Endpoints: []discoveryv1.Endpoint{
+				endpoint("db-0", true),
+				endpoint("db-1", true),
+				endpoint("db-2", false),
+			},
+		}
+
+		req = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: tmpl.Namespace,
+				Name:      tmpl.Name,
+			},
+		}
+
+		cli = fake.
+			NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(tmpl, svc, slice).
+			Build()
+
+		recorder = record.NewFakeRecorder(100)
+
+		reconciler = &TemplateReconciler{
+			Manager: &managerStub{Recorder: recorder},
+			Client:  cli,
+		}
+	})
+
+	reconcileTemplate := func() {
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	listInstances := func() map[string]crd.Instance {
+		list := &crd.DNSSDServiceInstanceList{}
+		Expect(cli.List(ctx, list)).To(Succeed())
+
+		instances := map[string]crd.Instance{}
+		for _, res := range list.Items {
+			Expect(metav1.IsControlledBy(&res, tmpl)).To(BeTrue())
+			instances[res.Name] = res.Spec.Instance
+		}
+
+		return instances
+	}
+
+	Describe("func Reconcile()", func() {
+		It("creates an instance for each ready endpoint", func() {
+			reconcileTemplate()
+
+			Expect(listInstances()).To(Equal(map[string]crd.Instance{
+				"peers-db-0": {
+					Name:        "db-0",
+					ServiceType: "_db._tcp",
+					Domain:      "example.org",
+					Targets: []crd.Target{
+						{Host: "db-0.db.default.svc.cluster.local", Port: 7000},
+					},
+					Attributes: []map[string]any{
+						{"role": "peer"},
+					},
+				},
+				"peers-db-1": {
+					Name:        "db-1",
+					ServiceType: "_db._tcp",
+					Domain:      "example.org",
+					Targets: []crd.Target{
+						{Host: "db-1.db.default.svc.cluster.local", Port: 7000},
+					},
+					Attributes: []map[string]any{
+						{"role": "peer"},
+					},
+				},
+			}))
+		})
+
+		It("uses the target domain, if specified", func() {
+			tmpl.Spec.TargetDomain = "db.example.com"
+			Expect(cli.Update(ctx, tmpl)).To(Succeed())
+
+			reconcileTemplate()
+
+			Expect(listInstances()["peers-db-0"].Targets).To(Equal([]crd.Target{
+				{Host: "db-0.db.example.com", Port: 7000},
+			}))
+		})
+
+		It("deletes instances when their endpoint becomes unready", func() {
+			reconcileTemplate()
+			Expect(listInstances()).To(HaveKey("peers-db-1"))
+
+			slice.Endpoints = []discoveryv1.Endpoint{
+				endpoint("db-0", true),
+				endpoint("db-1", false),
+				endpoint("db-2", true),
+			}
+			Expect(cli.Update(ctx, slice)).To(Succeed())
+
+			reconcileTemplate()
+
+			instances := listInstances()
+			Expect(instances).To(HaveKey("peers-db-0"))
+			Expect(instances).To(HaveKey("peers-db-2"))
+			Expect(instances).NotTo(HaveKey("peers-db-1"))
+		})
+
+		It("deletes all instances when the service is deleted", func() {
+			reconcileTemplate()
+			Expect(listInstances()).To(HaveLen(2))
+
+			Expect(cli.Delete(ctx, svc)).To(Succeed())
+
+			reconcileTemplate()
+			Expect(listInstances()).To(BeEmpty())
+		})
+
+		It("does not advertise endpoints without a hostname", func() {
+			ready := true
+			slice.Endpoints = append(
+				slice.Endpoints,
+				discoveryv1.Endpoint{
+					Addresses: []string{"10.0.0.4"},
+					Conditions: discoveryv1.EndpointConditions{
+						Ready: &ready,
+					},
+					TargetRef: &corev1.ObjectReference{
+						Kind: "Pod",
+						Name: "web-5d8f7c9b4-x2x9q",
+					},
+				},
+			)
+			Expect(cli.Update(ctx, slice)).To(Succeed())
+
+			reconcileTemplate()
+
+			instances := listInstances()
+			Expect(instances).To(HaveKey("peers-db-0"))
+			Expect(instances).To(HaveKey("peers-db-1"))
+			Expect(instances).NotTo(HaveKey("peers-web-5d8f7c9b4-x2x9q"))
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring("UnresolvableEndpoints"),
+				ContainSubstring("web-5d8f7c9b4-x2x9q"),
+			)))
+		})
+
+		It("records an event if the service is not headless", func() {
+			svc.Spec.ClusterIP = "10.0.0.100"
+			Expect(cli.Update(ctx, svc)).To(Succeed())
+
+			reconcileTemplate()
+
+			Expect(listInstances()).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidTemplate")))
+		})
+
+		It("records an event if the port is ambiguous", func() {
+			name, number := "admin", int32(7001)
+			slice.Ports = append(slice.Ports, discoveryv1.EndpointPort{Name: &name, Port: &number})
+			Expect(cli.Update(ctx, slice)).To(Succeed())
+
+			reconcileTemplate()
+			Expect(listInstances()).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidTemplate")))
+
+			tmpl.Spec.Port = "admin"
+			Expect(cli.Update(ctx, tmpl)).To(Succeed())
+
+			reconcileTemplate()
+			Expect(listInstances()["peers-db-0"].Targets[0].Port).To(BeEquivalentTo(7001))
+		})
+	})
+
+	Describe("func RequestsForService()", func() {
+		It("returns requests for the templates that select the service", func() {
+			expect := []reconcile.Request{req}
+
+			Expect(reconciler.RequestsForService(svc)).To(Equal(expect))
+			Expect(reconciler.RequestsForService(slice)).To(Equal(expect))
+
+			other := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "other",
+				},
+			}
+			Expect(reconciler.RequestsForService(other)).To(BeEmpty())
+		})
+	})
+})